@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grokify/structured-changelog/changelog"
+	"github.com/grokify/structured-tasks/tasks"
+	"github.com/spf13/cobra"
+)
+
+var (
+	archivePhase     int
+	archiveVersion   string
+	archiveDate      string
+	archiveChangelog string
+	archiveMark      bool
+	archiveDryRun    bool
+)
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive [input]",
+	Short: "Move completed tasks of a phase into a changelog release",
+	Long: `Move the completed tasks of a phase into a new release in CHANGELOG.json.
+
+Each task becomes an entry in the changelog category named by its type
+(Added if unset), described by its title and description. The archived tasks
+are then removed from the task list, along with references to them, or kept
+and marked "archived": true with --mark. Archived tasks are not rendered.
+
+If the changelog file does not exist, it is created. Task lists that use
+include must be merged first.
+
+Example usage:
+  stasks archive --phase 1 --version 0.2.0
+  stasks archive TASKS.json --phase 2 --version 1.0.0 --changelog CHANGELOG.json --mark`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runArchive,
+}
+
+func init() {
+	archiveCmd.Flags().IntVar(&archivePhase, "phase", 0, "Phase whose completed tasks are archived (required)")
+	archiveCmd.Flags().StringVar(&archiveVersion, "version", "", "Version of the new changelog release (required)")
+	archiveCmd.Flags().StringVar(&archiveDate, "date", "", "Release date, YYYY-MM-DD (default: today)")
+	archiveCmd.Flags().StringVar(&archiveChangelog, "changelog", "CHANGELOG.json", "Changelog file to update")
+	archiveCmd.Flags().BoolVar(&archiveMark, "mark", false, "Keep archived tasks and mark them archived instead of removing them")
+	archiveCmd.Flags().BoolVar(&archiveDryRun, "dry-run", false, "Show what would be archived without writing files")
+	_ = archiveCmd.MarkFlagRequired("phase")
+	_ = archiveCmd.MarkFlagRequired("version")
+	addLabelFlag(archiveCmd)
+}
+
+func runArchive(cmd *cobra.Command, args []string) error {
+	input := "TASKS.json"
+	if len(args) > 0 {
+		input = args[0]
+	}
+
+	tl, err := tasks.ParseFile(input)
+	if err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+	if len(tl.IncludedFiles()) > 0 {
+		return fmt.Errorf("%s uses include; run 'stasks merge' first", input)
+	}
+
+	cl, err := changelog.LoadFile(archiveChangelog)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		cl = changelog.New(tl.Project)
+	case err != nil:
+		return fmt.Errorf("failed to read changelog: %w", err)
+	}
+
+	date := archiveDate
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+	archived, err := tasks.Archive(tl, cl, tasks.ArchiveOptions{
+		Phase:   archivePhase,
+		Version: archiveVersion,
+		Date:    date,
+		Mark:    archiveMark,
+		Labels:  labelFilter(),
+	})
+	if err != nil {
+		return err
+	}
+
+	out := cmd.ErrOrStderr()
+	for _, task := range archived {
+		fmt.Fprintf(out, "  %s: %s\n", task.ID, task.Title)
+	}
+	if archiveDryRun {
+		fmt.Fprintf(out, "Would archive %d task(s) from phase %d into %s %s\n", len(archived), archivePhase, archiveChangelog, archiveVersion)
+		return nil
+	}
+
+	if err := cl.WriteFile(archiveChangelog); err != nil {
+		return fmt.Errorf("%w: %v", tasks.ErrWriteFile, err)
+	}
+	format := tasks.FormatFromPath(input)
+	if format == "" {
+		format = tasks.FormatJSON
+	}
+	if err := tasks.WriteFileFormat(input, tl, format); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Archived %d task(s) from phase %d into %s %s\n", len(archived), archivePhase, archiveChangelog, archiveVersion)
+	return nil
+}
@@ -2,11 +2,15 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/grokify/structured-changelog/changelog"
+	"github.com/grokify/structured-tasks/renderer"
+	"github.com/grokify/structured-tasks/tasks"
 	"github.com/spf13/cobra"
 )
 
@@ -48,6 +52,20 @@ func TestValidateCommand(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
+	// Create a valid file that triggers phase ordering warnings
+	warnJSON := `{
+		"irVersion": "1.0",
+		"project": "test-project",
+		"tasks": [
+			{"id": "task-1", "title": "Feature 1", "status": "planned", "phase": 2},
+			{"id": "task-2", "title": "Feature 2", "status": "completed", "phase": 1, "dependsOn": ["task-1"]}
+		]
+	}`
+	warnFile := filepath.Join(tmpDir, "warn.json")
+	if err := os.WriteFile(warnFile, []byte(warnJSON), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
 	tests := []struct {
 		name      string
 		args      []string
@@ -61,10 +79,33 @@ func TestValidateCommand(t *testing.T) {
 			wantInOut: "valid",
 		},
 		{
-			name:    "invalid file - missing project",
-			args:    []string{"validate", invalidFile},
+			name:      "valid file with warnings",
+			args:      []string{"validate", warnFile},
+			wantErr:   false,
+			wantInOut: "warning",
+		},
+		{
+			name:    "warnings fail in strict mode",
+			args:    []string{"validate", warnFile, "--strict"},
+			wantErr: true,
+		},
+		{
+			name:      "disabled rules pass in strict mode",
+			args:      []string{"validate", warnFile, "--strict", "--disable", "phase-order,completed-dependency"},
+			wantErr:   false,
+			wantInOut: "valid",
+		},
+		{
+			name:    "unknown rule",
+			args:    []string{"validate", warnFile, "--disable", "no-such-rule"},
 			wantErr: true,
 		},
+		{
+			name:      "invalid file - missing project",
+			args:      []string{"validate", invalidFile},
+			wantErr:   true,
+			wantInOut: invalidFile + ":1:1: error [required-field] project",
+		},
 		{
 			name:    "nonexistent file",
 			args:    []string{"validate", "/nonexistent/file.json"},
@@ -78,6 +119,11 @@ func TestValidateCommand(t *testing.T) {
 			cmd := &cobra.Command{Use: "stasks"}
 			cmd.AddCommand(validateCmd)
 
+			defer func() {
+				validateStrict = false
+				validateDisable = nil
+			}()
+
 			_, stderr, err := executeCommand(cmd, tt.args...)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("validate error = %v, wantErr %v", err, tt.wantErr)
@@ -89,6 +135,139 @@ func TestValidateCommand(t *testing.T) {
 	}
 }
 
+func TestValidateMachineOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+	invalidJSON := `{
+  "irVersion": "1.0",
+  "project": "test-project",
+  "tasks": [
+    {"id": "task-1", "title": "Feature 1", "status": "planned", "dependsOn": ["missing"]}
+  ]
+}`
+	invalidFile := filepath.Join(tmpDir, "TASKS.json")
+	if err := os.WriteFile(invalidFile, []byte(invalidJSON), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer func() { validateOutput = "text" }()
+
+	t.Run("json", func(t *testing.T) {
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(validateCmd)
+
+		stdout, _, err := executeCommand(cmd, "validate", invalidFile, "--output", "json")
+		if err == nil {
+			t.Error("Expected validation error")
+		}
+
+		var result tasks.ValidationResult
+		if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, stdout)
+		}
+		if result.Valid || len(result.Errors) != 1 {
+			t.Fatalf("Expected one error, got %+v", result)
+		}
+		e := result.Errors[0]
+		if e.Code != tasks.RuleUnknownDependency || e.Field != "tasks[0].dependsOn[0]" || e.Pos.Line != 5 || e.Pos.File != invalidFile {
+			t.Errorf("Unexpected error: %+v", e)
+		}
+	})
+
+	t.Run("sarif", func(t *testing.T) {
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(validateCmd)
+
+		stdout, _, err := executeCommand(cmd, "validate", invalidFile, "--output", "sarif")
+		if err == nil {
+			t.Error("Expected validation error")
+		}
+
+		var log sarifLog
+		if err := json.Unmarshal([]byte(stdout), &log); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, stdout)
+		}
+		if log.Version != "2.1.0" || len(log.Runs) != 1 {
+			t.Fatalf("Unexpected SARIF log: %+v", log)
+		}
+		run := log.Runs[0]
+		if len(run.Results) != 1 {
+			t.Fatalf("Expected 1 result, got %d", len(run.Results))
+		}
+		res := run.Results[0]
+		if res.RuleID != "unknown-dependency" || res.Level != "error" {
+			t.Errorf("Unexpected result: %+v", res)
+		}
+		if run.Tool.Driver.Rules[res.RuleIndex].ID != res.RuleID {
+			t.Error("Expected ruleIndex to point at the matching rule")
+		}
+		region := res.Locations[0].PhysicalLocation.Region
+		if region == nil || region.StartLine != 5 {
+			t.Errorf("Expected region on line 5, got %+v", region)
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(validateCmd)
+
+		if _, _, err := executeCommand(cmd, "validate", invalidFile, "--output", "xml"); err == nil {
+			t.Error("Expected error for unknown output format")
+		}
+	})
+
+	malformedFile := filepath.Join(tmpDir, "MALFORMED.json")
+	if err := os.WriteFile(malformedFile, []byte("{\n  \"irVersion\": \"1.0\",\n  \"tasks\": [\n}\n"), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	t.Run("json parse error", func(t *testing.T) {
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(validateCmd)
+
+		stdout, _, err := executeCommand(cmd, "validate", malformedFile, "--output", "json")
+		if err == nil {
+			t.Error("Expected validation error")
+		}
+
+		var result tasks.ValidationResult
+		if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, stdout)
+		}
+		if result.Valid || len(result.Errors) != 1 {
+			t.Fatalf("Expected one error, got %+v", result)
+		}
+		e := result.Errors[0]
+		if e.Code != tasks.RuleParseError || e.Pos.Line != 4 || e.Pos.File != malformedFile {
+			t.Errorf("Unexpected error: %+v", e)
+		}
+	})
+
+	t.Run("sarif parse error", func(t *testing.T) {
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(validateCmd)
+
+		stdout, _, err := executeCommand(cmd, "validate", malformedFile, "--output", "sarif")
+		if err == nil {
+			t.Error("Expected validation error")
+		}
+
+		var log sarifLog
+		if err := json.Unmarshal([]byte(stdout), &log); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, stdout)
+		}
+		if len(log.Runs) != 1 || len(log.Runs[0].Results) != 1 {
+			t.Fatalf("Expected one result, got %+v", log)
+		}
+		res := log.Runs[0].Results[0]
+		if res.RuleID != "parse-error" || res.Level != "error" {
+			t.Errorf("Unexpected result: %+v", res)
+		}
+		region := res.Locations[0].PhysicalLocation.Region
+		if region == nil || region.StartLine != 4 {
+			t.Errorf("Expected region on line 4, got %+v", region)
+		}
+	})
+}
+
 func TestGenerateCommand(t *testing.T) {
 	// Create a temporary valid JSON file
 	tmpDir := t.TempDir()
@@ -220,7 +399,8 @@ func TestDepsCommand(t *testing.T) {
 		"tasks": [
 			{"id": "task-1", "title": "Foundation", "status": "completed"},
 			{"id": "task-2", "title": "Feature A", "status": "planned", "dependsOn": ["task-1"]},
-			{"id": "task-3", "title": "Feature B", "status": "planned", "dependsOn": ["task-1", "task-2"]}
+			{"id": "task-3", "title": "Feature B", "status": "planned", "dependsOn": ["task-1", "task-2"]},
+			{"id": "task-4", "title": "Docs", "status": "planned"}
 		]
 	}`
 	inputFile := filepath.Join(tmpDir, "TASKS.json")
@@ -261,4 +441,850 @@ func TestDepsCommand(t *testing.T) {
 			t.Error("Expected dependency arrows")
 		}
 	})
+
+	for _, tc := range []struct{ format, want string }{
+		{"plantuml", "@startuml"},
+		{"d2", "task_1 -> task_2"},
+		{"graphml", "<graphml"},
+		{"json", `"edges"`},
+	} {
+		t.Run(tc.format+" format", func(t *testing.T) {
+			cmd := &cobra.Command{Use: "stasks"}
+			cmd.AddCommand(depsCmd)
+
+			stdout, _, err := executeCommand(cmd, "deps", inputFile, "--format", tc.format)
+			if err != nil {
+				t.Fatalf("deps failed: %v", err)
+			}
+			if !strings.Contains(stdout, tc.want) {
+				t.Errorf("Expected output to contain %q, got:\n%s", tc.want, stdout)
+			}
+		})
+	}
+
+	t.Run("clustered with isolated tasks", func(t *testing.T) {
+		defer func() {
+			depsCluster = ""
+			depsIsolated = false
+			depsLinks = false
+			depsLegend = false
+		}()
+
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(depsCmd)
+
+		stdout, _, err := executeCommand(cmd, "deps", inputFile, "--format", "mermaid", "--cluster", "phase", "--isolated", "--links", "--legend")
+		if err != nil {
+			t.Fatalf("deps failed: %v", err)
+		}
+
+		for _, want := range []string{"subgraph phase_unphased", "click task-1 href", "subgraph legend"} {
+			if !strings.Contains(stdout, want) {
+				t.Errorf("Expected output to contain %q, got:\n%s", want, stdout)
+			}
+		}
+	})
+
+	t.Run("focus", func(t *testing.T) {
+		defer func() {
+			depsFocus = ""
+			depsUp, depsDown = 1, 1
+		}()
+
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(depsCmd)
+
+		stdout, _, err := executeCommand(cmd, "deps", inputFile, "--focus", "task-2", "--up", "1", "--down", "0")
+		if err != nil {
+			t.Fatalf("deps failed: %v", err)
+		}
+		if !strings.Contains(stdout, "task-1 --> task-2") {
+			t.Errorf("Expected upstream edge, got:\n%s", stdout)
+		}
+		if strings.Contains(stdout, "task-3") {
+			t.Errorf("Expected downstream task to be excluded, got:\n%s", stdout)
+		}
+	})
+
+	t.Run("focus with isolated tasks", func(t *testing.T) {
+		defer func() {
+			depsFocus = ""
+			depsIsolated = false
+			depsUp, depsDown = 1, 1
+		}()
+
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(depsCmd)
+
+		stdout, _, err := executeCommand(cmd, "deps", inputFile, "--focus", "task-2", "--isolated")
+		if err != nil {
+			t.Fatalf("deps failed: %v", err)
+		}
+		if !strings.Contains(stdout, "task-2 --> task-3") {
+			t.Errorf("Expected focused edge, got:\n%s", stdout)
+		}
+		if strings.Contains(stdout, "task-4") {
+			t.Errorf("Expected isolated task outside the focus to be excluded, got:\n%s", stdout)
+		}
+	})
+
+	t.Run("unknown cluster", func(t *testing.T) {
+		defer func() { depsCluster = "" }()
+
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(depsCmd)
+
+		if _, _, err := executeCommand(cmd, "deps", inputFile, "--cluster", "bogus"); err == nil {
+			t.Error("Expected error for unknown cluster value")
+		}
+	})
+}
+
+func TestImpactCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	validJSON := `{
+		"irVersion": "1.0",
+		"project": "Test Project",
+		"tasks": [
+			{"id": "task-1", "title": "Foundation", "status": "inProgress", "phase": 1},
+			{"id": "task-2", "title": "Feature A", "status": "planned", "phase": 2, "dependsOn": ["task-1"]},
+			{"id": "task-3", "title": "Feature B", "status": "planned", "dependsOn": ["task-2"]},
+			{"id": "task-4", "title": "Unrelated", "status": "planned", "phase": 2}
+		]
+	}`
+	inputFile := filepath.Join(tmpDir, "TASKS.json")
+	if err := os.WriteFile(inputFile, []byte(validJSON), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	t.Run("transitive impact", func(t *testing.T) {
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(impactCmd)
+
+		stdout, _, err := executeCommand(cmd, "impact", "task-1", "-i", inputFile)
+		if err != nil {
+			t.Fatalf("impact failed: %v", err)
+		}
+		for _, want := range []string{"2 task(s) would slip", "Phase 2:", "task-2: Feature A", "Unphased:", "task-3: Feature B"} {
+			if !strings.Contains(stdout, want) {
+				t.Errorf("Expected output to contain %q, got:\n%s", want, stdout)
+			}
+		}
+		if strings.Contains(stdout, "task-4") {
+			t.Error("Expected unrelated task to be excluded")
+		}
+	})
+
+	t.Run("unknown task", func(t *testing.T) {
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(impactCmd)
+
+		if _, _, err := executeCommand(cmd, "impact", "nope", "-i", inputFile); err == nil {
+			t.Error("Expected error for unknown task")
+		}
+	})
+}
+
+func TestConvertCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	validJSON := `{
+		"irVersion": "1.0",
+		"project": "Test Project",
+		"tasks": [
+			{"id": "task-1", "title": "Feature 1", "status": "completed"}
+		]
+	}`
+	inputFile := filepath.Join(tmpDir, "TASKS.json")
+	if err := os.WriteFile(inputFile, []byte(validJSON), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer func() { convertTo = "" }()
+
+	t.Run("json to yaml file", func(t *testing.T) {
+		outputFile := filepath.Join(tmpDir, "TASKS.yaml")
+
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(convertCmd)
+
+		if _, _, err := executeCommand(cmd, "convert", inputFile, outputFile); err != nil {
+			t.Fatalf("convert failed: %v", err)
+		}
+
+		content, err := os.ReadFile(outputFile)
+		if err != nil {
+			t.Fatalf("Failed to read output file: %v", err)
+		}
+		if !strings.Contains(string(content), "project: Test Project") {
+			t.Errorf("Expected YAML output, got:\n%s", content)
+		}
+
+		// The YAML file validates like the JSON original
+		vcmd := &cobra.Command{Use: "stasks"}
+		vcmd.AddCommand(validateCmd)
+		if _, _, err := executeCommand(vcmd, "validate", outputFile); err != nil {
+			t.Errorf("validate YAML failed: %v", err)
+		}
+	})
+
+	t.Run("json to toml stdout", func(t *testing.T) {
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(convertCmd)
+
+		stdout, _, err := executeCommand(cmd, "convert", inputFile, "--to", "toml")
+		if err != nil {
+			t.Fatalf("convert failed: %v", err)
+		}
+		if !strings.Contains(stdout, "[[tasks]]") {
+			t.Errorf("Expected TOML output, got:\n%s", stdout)
+		}
+	})
+
+	t.Run("missing output format", func(t *testing.T) {
+		convertTo = ""
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(convertCmd)
+
+		if _, _, err := executeCommand(cmd, "convert", inputFile); err == nil {
+			t.Error("Expected error without output format")
+		}
+	})
+}
+
+func TestSplitAndMergeCommands(t *testing.T) {
+	tmpDir := t.TempDir()
+	input := `{
+  "irVersion": "1.0",
+  "project": "Test Project",
+  "areas": [
+    {"id": "api", "name": "API"},
+    {"id": "web ui", "name": "Web UI"}
+  ],
+  "tasks": [
+    {"id": "task-1", "title": "Endpoint", "status": "planned", "area": "api"},
+    // design review pending
+    {"id": "task-2", "title": "Page", "status": "planned", "area": "web ui", "dependsOn": ["task-1"]},
+    {"id": "task-3", "title": "Release", "status": "future"}
+  ]
+}`
+	inputFile := filepath.Join(tmpDir, "TASKS.json")
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer func() {
+		splitBy, splitDir, splitOutput, splitForce = "area", "tasks", "", false
+		mergeTo = ""
+	}()
+
+	cmd := &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(splitCmd)
+	if _, _, err := executeCommand(cmd, "split", inputFile, "--by", "area"); err != nil {
+		t.Fatalf("split failed: %v", err)
+	}
+
+	root, err := tasks.ParseFile(inputFile)
+	if err != nil {
+		t.Fatalf("Failed to parse split output: %v", err)
+	}
+	if len(root.Tasks) != 3 || len(root.Areas) != 2 {
+		t.Fatalf("Split files do not resolve to the original: %+v", root)
+	}
+	apiFile := filepath.Join(tmpDir, "tasks", "api.json")
+	for i, task := range root.Tasks {
+		if task.ID == "task-1" && root.TaskSource(i) != apiFile {
+			t.Errorf("TaskSource(%d) = %s, want %s", i, root.TaskSource(i), apiFile)
+		}
+	}
+	webFile, err := os.ReadFile(filepath.Join(tmpDir, "tasks", "web-ui.json"))
+	if err != nil {
+		t.Fatalf("Expected area file: %v", err)
+	}
+	if !strings.Contains(string(webFile), "// design review pending") {
+		t.Errorf("Expected comment to move with its task:\n%s", webFile)
+	}
+
+	// Splitting again refuses to overwrite area files
+	cmd = &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(splitCmd)
+	if _, _, err := executeCommand(cmd, "split", inputFile); err == nil {
+		t.Error("Expected error when area files exist")
+	}
+
+	// Merge flattens the files back into one
+	cmd = &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(mergeCmd)
+	stdout, _, err := executeCommand(cmd, "merge", inputFile)
+	if err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+	merged, err := tasks.Parse([]byte(stdout))
+	if err != nil {
+		t.Fatalf("Failed to parse merged output: %v\n%s", err, stdout)
+	}
+	if len(merged.Tasks) != 3 || len(merged.Areas) != 2 || len(merged.Include) != 0 {
+		t.Errorf("Unexpected merged task list: %+v", merged)
+	}
+	if result := tasks.Validate(merged); !result.Valid {
+		t.Errorf("Merged task list is invalid: %v", result.Errors)
+	}
+}
+
+func TestPortfolioCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"api.json": `{"irVersion": "1.0", "project": "API", "tasks": [
+			{"id": "t1", "title": "Endpoint", "status": "completed"},
+			{"id": "t2", "title": "Pagination", "status": "inProgress"}
+		]}`,
+		"web.json": `{"irVersion": "1.0", "project": "Web", "tasks": [
+			{"id": "t1", "title": "Login page", "status": "planned"}
+		]}`,
+		"portfolio.json": `{"name": "Platform", "projects": [{"path": "api.json"}, {"path": "web.json"}]}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+	defer func() {
+		portfolioManifest, portfolioFormat, portfolioOutput = "", "markdown", ""
+	}()
+
+	t.Run("files to markdown", func(t *testing.T) {
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(portfolioCmd)
+
+		stdout, _, err := executeCommand(cmd, "portfolio", filepath.Join(tmpDir, "api.json"), filepath.Join(tmpDir, "web.json"))
+		if err != nil {
+			t.Fatalf("portfolio failed: %v", err)
+		}
+		for _, want := range []string{"**Projects:** 2 · **Tasks:** 3", "`api#t2`", "`web#t1`"} {
+			if !strings.Contains(stdout, want) {
+				t.Errorf("Expected %q in output:\n%s", want, stdout)
+			}
+		}
+	})
+
+	t.Run("manifest to html file", func(t *testing.T) {
+		outputFile := filepath.Join(tmpDir, "portfolio.html")
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(portfolioCmd)
+
+		_, _, err := executeCommand(cmd, "portfolio", "--manifest", filepath.Join(tmpDir, "portfolio.json"), "--format", "html", "-o", outputFile)
+		if err != nil {
+			t.Fatalf("portfolio failed: %v", err)
+		}
+		content, err := os.ReadFile(outputFile)
+		if err != nil {
+			t.Fatalf("Failed to read output file: %v", err)
+		}
+		if !strings.Contains(string(content), "<strong>Portfolio:</strong> Platform") {
+			t.Errorf("Expected HTML report, got:\n%s", content)
+		}
+	})
+
+	t.Run("no projects", func(t *testing.T) {
+		portfolioManifest, portfolioFormat, portfolioOutput = "", "markdown", ""
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(portfolioCmd)
+
+		if _, _, err := executeCommand(cmd, "portfolio"); err == nil {
+			t.Error("Expected error without projects")
+		}
+	})
+}
+
+func TestCrossProjectReferences(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"api.json": `{"irVersion": "1.0", "project": "API", "tasks": [
+			{"id": "auth", "title": "Auth tokens", "status": "completed"}
+		]}`,
+		"web.json": `{"irVersion": "1.0", "project": "Web", "tasks": [
+			{"id": "login", "title": "Login page", "status": "planned", "dependsOn": ["api#auth"]}
+		]}`,
+		"broken.json": `{"irVersion": "1.0", "project": "Web", "tasks": [
+			{"id": "login", "title": "Login page", "status": "planned", "dependsOn": ["api#nope"]}
+		]}`,
+		"siblings.json": `{"projects": [{"path": "api.json"}]}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+	manifest := filepath.Join(tmpDir, "siblings.json")
+	defer func() { validateManifest, depsManifest = "", "" }()
+
+	t.Run("validate without manifest warns", func(t *testing.T) {
+		validateManifest = ""
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(validateCmd)
+		_, stderr, err := executeCommand(cmd, "validate", filepath.Join(tmpDir, "web.json"))
+		if err != nil {
+			t.Fatalf("validate failed: %v", err)
+		}
+		if !strings.Contains(stderr, "[unresolved-reference]") {
+			t.Errorf("Expected unresolved reference warning, got:\n%s", stderr)
+		}
+	})
+
+	t.Run("validate with manifest", func(t *testing.T) {
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(validateCmd)
+		_, stderr, err := executeCommand(cmd, "validate", "--manifest", manifest, filepath.Join(tmpDir, "web.json"))
+		if err != nil || strings.Contains(stderr, "warning") {
+			t.Errorf("validate = %v:\n%s", err, stderr)
+		}
+
+		cmd = &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(validateCmd)
+		if _, _, err := executeCommand(cmd, "validate", "--manifest", manifest, filepath.Join(tmpDir, "broken.json")); err == nil {
+			t.Error("Expected unknown cross-project task to fail validation")
+		}
+	})
+
+	t.Run("deps labels external nodes", func(t *testing.T) {
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(depsCmd)
+		stdout, _, err := executeCommand(cmd, "deps", "--manifest", manifest, filepath.Join(tmpDir, "web.json"))
+		if err != nil {
+			t.Fatalf("deps failed: %v", err)
+		}
+		if !strings.Contains(stdout, `ext_api_auth(["api: Auth tokens"])`) {
+			t.Errorf("Expected labeled external node, got:\n%s", stdout)
+		}
+	})
+}
+
+func TestArchiveCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	input := `{
+  "irVersion": "1.0",
+  "project": "Test Project",
+  "tasks": [
+    {"id": "task-1", "title": "Parser", "status": "completed", "phase": 1, "type": "Added"},
+    {"id": "task-2", "title": "Renderer", "status": "inProgress", "phase": 1, "dependsOn": ["task-1"]}
+  ]
+}`
+	inputFile := filepath.Join(tmpDir, "TASKS.json")
+	changelogFile := filepath.Join(tmpDir, "CHANGELOG.json")
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer func() {
+		archivePhase, archiveVersion, archiveDate = 0, "", ""
+		archiveChangelog, archiveMark, archiveDryRun = "CHANGELOG.json", false, false
+	}()
+
+	cmd := &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(archiveCmd)
+	_, stderr, err := executeCommand(cmd, "archive", inputFile, "--phase", "1", "--version", "0.1.0",
+		"--date", "2026-02-01", "--changelog", changelogFile, "--mark")
+	if err != nil {
+		t.Fatalf("archive failed: %v", err)
+	}
+	if !strings.Contains(stderr, "Archived 1 task(s)") {
+		t.Errorf("Expected summary, got: %s", stderr)
+	}
+
+	cl, err := changelog.LoadFile(changelogFile)
+	if err != nil {
+		t.Fatalf("Expected changelog to be created: %v", err)
+	}
+	if len(cl.Releases) != 1 || len(cl.Releases[0].Added) != 1 || cl.Releases[0].Added[0].Description != "Parser" {
+		t.Errorf("Unexpected changelog: %+v", cl.Releases)
+	}
+	tl, err := tasks.ParseFile(inputFile)
+	if err != nil {
+		t.Fatalf("Failed to parse task list: %v", err)
+	}
+	if !tl.Tasks[0].Archived {
+		t.Error("Expected task-1 to be marked archived")
+	}
+	if md := renderer.Render(tl, renderer.DefaultOptions()); strings.Contains(md, "Parser") {
+		t.Errorf("Expected archived task to be hidden:\n%s", md)
+	}
+
+	cmd = &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(statsCmd)
+	stdout, _, err := executeCommand(cmd, "stats", inputFile)
+	if err != nil {
+		t.Fatalf("stats failed: %v", err)
+	}
+	if !strings.Contains(stdout, "Total tasks: 1") || !strings.Contains(stdout, "Phase 1: 1 tasks") {
+		t.Errorf("Expected archived task excluded from stats:\n%s", stdout)
+	}
+
+	defer func() { queryCount, queryArchived = false, false }()
+	cmd = &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(queryCmd)
+	if stdout, _, err = executeCommand(cmd, "query", inputFile, "--count"); err != nil || stdout != "1\n" {
+		t.Errorf("Expected archived task excluded from query, got %q (%v)", stdout, err)
+	}
+	cmd = &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(queryCmd)
+	if stdout, _, err = executeCommand(cmd, "query", inputFile, "archived = true", "--count", "--archived"); err != nil || stdout != "1\n" {
+		t.Errorf("Expected --archived to include archived task, got %q (%v)", stdout, err)
+	}
+}
+
+func TestSyncCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	input := `{
+  "irVersion": "1.0",
+  "project": "Test Project",
+  "tasks": [
+    {"id": "task-1", "title": "Parser", "status": "completed"},
+    {"id": "task-2", "title": "Renderer", "status": "planned"}
+  ]
+}`
+	inputFile := filepath.Join(tmpDir, "TASKS.json")
+	changelogFile := filepath.Join(tmpDir, "CHANGELOG.json")
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	cl := changelog.New("Test Project")
+	release := changelog.NewRelease("0.3.0", "2026-03-01")
+	release.AddAdded(changelog.NewEntry("JSON parser").WithIssue("task-1"))
+	cl.AddRelease(release)
+	if err := cl.WriteFile(changelogFile); err != nil {
+		t.Fatalf("Failed to create changelog: %v", err)
+	}
+	defer func() {
+		syncChangelog, syncCheck = "CHANGELOG.json", false
+	}()
+
+	cmd := &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(syncCmd)
+	_, stderr, err := executeCommand(cmd, "sync", inputFile, "--changelog", changelogFile, "--check")
+	if err == nil || !strings.Contains(stderr, "task-1: missing version (changelog: 0.3.0)") {
+		t.Errorf("Expected check to fail with missing version, got err=%v stderr=%s", err, stderr)
+	}
+
+	syncCheck = false
+	cmd = &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(syncCmd)
+	if _, _, err := executeCommand(cmd, "sync", inputFile, "--changelog", changelogFile); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	tl, err := tasks.ParseFile(inputFile)
+	if err != nil {
+		t.Fatalf("Failed to parse task list: %v", err)
+	}
+	if tl.Tasks[0].Version != "0.3.0" || tl.Tasks[1].Version != "" {
+		t.Errorf("Unexpected versions: %+v", tl.Tasks)
+	}
+
+	cmd = &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(syncCmd)
+	if _, _, err := executeCommand(cmd, "sync", inputFile, "--changelog", changelogFile, "--check"); err != nil {
+		t.Errorf("Expected versions in sync: %v", err)
+	}
+}
+
+func TestGenerateHTML(t *testing.T) {
+	tmpDir := t.TempDir()
+	input := `{
+  "irVersion": "1.0",
+  "project": "Test Project",
+  "sections": [{"id": "notes", "title": "Notes", "content": [{"type": "text", "value": "Hello"}]}],
+  "tasks": [{"id": "task-1", "title": "Feature 1", "status": "planned"}]
+}`
+	inputFile := filepath.Join(tmpDir, "TASKS.json")
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer func() { genFormat, genOutput = "markdown", "" }()
+
+	cmd := &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(generateCmd)
+	stdout, _, err := executeCommand(cmd, "generate", "-i", inputFile, "-o", "", "--format", "html")
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if !strings.Contains(stdout, "<!DOCTYPE html>") || !strings.Contains(stdout, "<p>Hello</p>") || !strings.Contains(stdout, "Feature 1") {
+		t.Errorf("Unexpected HTML output:\n%s", stdout)
+	}
+
+	cmd = &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(generateCmd)
+	if _, _, err := executeCommand(cmd, "generate", "-i", inputFile, "--format", "pdf"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
+
+func TestDepsPackages(t *testing.T) {
+	tmpDir := t.TempDir()
+	input := `{
+  "irVersion": "1.0",
+  "project": "Test Project",
+  "dependencies": {
+    "internal": [
+      {"package": "cmd/app", "dependsOn": ["core", "store"]},
+      {"package": "store", "dependsOn": "core"}
+    ]
+  },
+  "tasks": [
+    {"id": "task-1", "title": "Foundation", "status": "completed"}
+  ]
+}`
+	inputFile := filepath.Join(tmpDir, "TASKS.json")
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer func() { depsPackages, depsFormat = false, "mermaid" }()
+
+	cmd := &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(depsCmd)
+	stdout, _, err := executeCommand(cmd, "deps", inputFile, "--packages", "--format", "dot")
+	if err != nil {
+		t.Fatalf("deps --packages failed: %v", err)
+	}
+	for _, want := range []string{`"cmd/app" -> core;`, "store -> core;"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("Expected %q in output:\n%s", want, stdout)
+		}
+	}
+	if strings.Contains(stdout, "task-1") {
+		t.Errorf("Expected only packages in output:\n%s", stdout)
+	}
+}
+
+func TestGenerateSort(t *testing.T) {
+	tmpDir := t.TempDir()
+	input := `{
+  "irVersion": "1.0",
+  "project": "Test Project",
+  "tasks": [
+    {"id": "a", "title": "Alpha", "status": "planned", "priority": "low"},
+    {"id": "b", "title": "Beta", "status": "planned", "priority": "critical"}
+  ]
+}`
+	inputFile := filepath.Join(tmpDir, "TASKS.json")
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer func() { genSortBy, genOutput = "status", "" }()
+
+	cmd := &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(generateCmd)
+	stdout, _, err := executeCommand(cmd, "generate", "-i", inputFile, "-o", "", "--sort", "priority")
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if strings.Index(stdout, "### [ ] Beta") > strings.Index(stdout, "### [ ] Alpha") {
+		t.Errorf("Expected critical task first:\n%s", stdout)
+	}
+
+	cmd = &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(generateCmd)
+	if _, _, err := executeCommand(cmd, "generate", "-i", inputFile, "--sort", "size"); err == nil {
+		t.Error("Expected error for unknown sort")
+	}
+}
+
+func TestLabelFlag(t *testing.T) {
+	tmpDir := t.TempDir()
+	input := `{
+  "irVersion": "1.0",
+  "project": "Test Project",
+  "labels": [{"id": "security"}, {"id": "perf"}],
+  "areas": [{"id": "api", "name": "API"}],
+  "tasks": [
+    {"id": "auth", "title": "Auth hardening", "status": "planned", "area": "api", "labels": ["security"]},
+    {"id": "cache", "title": "Response cache", "status": "planned", "area": "api", "labels": ["perf"], "dependsOn": ["auth"]},
+    {"id": "docs", "title": "Docs", "status": "planned", "labels": ["docs"]}
+  ]
+}`
+	inputFile := filepath.Join(tmpDir, "TASKS.json")
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer func() { labelFlags, genOutput, impactInput = nil, "", "TASKS.json" }()
+
+	cmd := &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(statsCmd)
+	stdout, _, err := executeCommand(cmd, "stats", inputFile, "--label", "security,perf")
+	if err != nil {
+		t.Fatalf("stats failed: %v", err)
+	}
+	if !strings.Contains(stdout, "Total tasks: 2") || !strings.Contains(stdout, "By Label:\n  security: 1\n  perf: 1\n") {
+		t.Errorf("Unexpected stats output:\n%s", stdout)
+	}
+
+	labelFlags = nil
+	cmd = &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(generateCmd)
+	stdout, _, err = executeCommand(cmd, "generate", "-i", inputFile, "-o", "", "--label", "security", "--label", "!perf")
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if strings.Contains(stdout, "Response cache") || strings.Contains(stdout, "Docs") || !strings.Contains(stdout, "Auth hardening") {
+		t.Errorf("Expected only security tasks:\n%s", stdout)
+	}
+
+	labelFlags = nil
+	cmd = &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(validateCmd)
+	if _, _, err := executeCommand(cmd, "validate", inputFile); err == nil {
+		t.Error("Expected undeclared label error")
+	}
+	labelFlags = nil
+	cmd = &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(validateCmd)
+	if _, _, err := executeCommand(cmd, "validate", inputFile, "--label", "security"); err != nil {
+		t.Errorf("Expected findings on other tasks to be skipped: %v", err)
+	}
+
+	labelFlags = nil
+	cmd = &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(impactCmd)
+	stdout, _, err = executeCommand(cmd, "impact", "auth", "-i", inputFile, "--label", "docs")
+	if err != nil {
+		t.Fatalf("impact failed: %v", err)
+	}
+	if !strings.Contains(stdout, "0 task(s) would slip") {
+		t.Errorf("Expected impacted perf task filtered out:\n%s", stdout)
+	}
+
+	defer func() { splitOutput = "" }()
+	labelFlags = nil
+	cmd = &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(splitCmd)
+	if _, _, err := executeCommand(cmd, "split", inputFile, "--label", "security"); err == nil {
+		t.Error("Expected split --label without --output to fail")
+	}
+
+	labelFlags = nil
+	splitFile := filepath.Join(tmpDir, "split", "TASKS.json")
+	cmd = &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(splitCmd)
+	if _, _, err := executeCommand(cmd, "split", inputFile, "-o", splitFile, "--label", "security"); err != nil {
+		t.Fatalf("split failed: %v", err)
+	}
+	split, err := tasks.ParseFile(splitFile)
+	if err != nil {
+		t.Fatalf("Failed to parse split output: %v", err)
+	}
+	if len(split.Tasks) != 1 || split.Tasks[0].ID != "auth" {
+		t.Errorf("Expected only the security task to be split, got %+v", split.Tasks)
+	}
+}
+
+func TestFilterFlag(t *testing.T) {
+	tmpDir := t.TempDir()
+	input := `{
+  "irVersion": "1.0",
+  "project": "Test",
+  "tasks": [
+    {"id": "auth", "title": "Auth hardening", "status": "inProgress", "area": "api", "phase": 1},
+    {"id": "cache", "title": "Response cache", "status": "planned", "area": "core", "phase": 2, "dependsOn": ["auth"]},
+    {"id": "docs", "title": "Docs site", "status": "completed", "area": "api", "phase": 1}
+  ]
+}`
+	inputFile := filepath.Join(tmpDir, "TASKS.json")
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer func() { filterExpr, genOutput = "", "" }()
+
+	cmd := &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(statsCmd)
+	stdout, _, err := executeCommand(cmd, "stats", inputFile, "--filter", "status != completed")
+	if err != nil {
+		t.Fatalf("stats failed: %v", err)
+	}
+	if !strings.Contains(stdout, "Total tasks: 2") {
+		t.Errorf("Unexpected stats output:\n%s", stdout)
+	}
+
+	filterExpr = ""
+	cmd = &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(generateCmd)
+	stdout, _, err = executeCommand(cmd, "generate", "-i", inputFile, "-o", "", "--filter", "area = api")
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if strings.Contains(stdout, "Response cache") || !strings.Contains(stdout, "Auth hardening") || !strings.Contains(stdout, "Docs site") {
+		t.Errorf("Expected only api tasks:\n%s", stdout)
+	}
+
+	filterExpr = ""
+	cmd = &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(depsCmd)
+	stdout, _, err = executeCommand(cmd, "deps", inputFile, "--filter", "phase = 1")
+	if err != nil {
+		t.Fatalf("deps failed: %v", err)
+	}
+	if strings.Contains(stdout, "cache") {
+		t.Errorf("Expected phase 2 task filtered out:\n%s", stdout)
+	}
+
+	filterExpr = ""
+	cmd = &cobra.Command{Use: "stasks"}
+	cmd.AddCommand(statsCmd)
+	if _, _, err := executeCommand(cmd, "stats", inputFile, "--filter", "phase <= two"); err == nil || !strings.Contains(err.Error(), "--filter: invalid filter") {
+		t.Errorf("Expected invalid filter error, got %v", err)
+	}
+}
+
+func TestQueryCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	input := `{
+  "irVersion": "1.0",
+  "project": "Test",
+  "tasks": [
+    {"id": "auth", "title": "Auth hardening", "status": "inProgress", "area": "api", "phase": 1, "labels": ["security"]},
+    {"id": "cache", "title": "Response cache, v2", "status": "planned", "area": "core", "phase": 2},
+    {"id": "docs", "title": "Docs site", "status": "completed", "area": "api", "phase": 1}
+  ]
+}`
+	inputFile := filepath.Join(tmpDir, "TASKS.json")
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	defer func() { queryFields, queryFormat, queryCount, labelFlags = nil, "table", false, nil }()
+
+	run := func(args ...string) string {
+		t.Helper()
+		queryFields, queryFormat, queryCount, labelFlags = nil, "table", false, nil
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(queryCmd)
+		stdout, _, err := executeCommand(cmd, append([]string{"query", inputFile}, args...)...)
+		if err != nil {
+			t.Fatalf("query %v failed: %v", args, err)
+		}
+		return stdout
+	}
+
+	if got := run("status != completed", "--fields", "id,title"); got != "ID     TITLE\nauth   Auth hardening\ncache  Response cache, v2\n" {
+		t.Errorf("Unexpected table output:\n%s", got)
+	}
+	if got := run("area = api", "--fields", "id,phase,labels", "--format", "json"); got != "[\n  {\n    \"id\": \"auth\",\n    \"phase\": 1,\n    \"labels\": [\n      \"security\"\n    ]\n  },\n  {\n    \"id\": \"docs\",\n    \"phase\": 1,\n    \"labels\": []\n  }\n]\n" {
+		t.Errorf("Unexpected JSON output:\n%s", got)
+	}
+	if got := run("phase = 5", "--format", "json"); got != "[]\n" {
+		t.Errorf("Unexpected empty JSON output:\n%s", got)
+	}
+	if got := run("phase >= 2", "--fields", "id,status", "--format", "ndjson"); got != "{\"id\":\"cache\",\"status\":\"planned\"}\n" {
+		t.Errorf("Unexpected NDJSON output:\n%s", got)
+	}
+	if got := run("phase >= 2", "--fields", "id,title", "--format", "csv"); got != "id,title\ncache,\"Response cache, v2\"\n" {
+		t.Errorf("Unexpected CSV output:\n%s", got)
+	}
+	if got := run("--count"); got != "3\n" {
+		t.Errorf("Unexpected count: %q", got)
+	}
+	if got := run("phase = 1", "--label", "security", "--count"); got != "1\n" {
+		t.Errorf("Unexpected count with --label: %q", got)
+	}
+
+	for _, args := range [][]string{{"phase <= two"}, {"phase = 1", "--fields", "owner"}, {"phase = 1", "--format", "xml"}} {
+		queryFields, queryFormat, queryCount = nil, "table", false
+		cmd := &cobra.Command{Use: "stasks"}
+		cmd.AddCommand(queryCmd)
+		if _, _, err := executeCommand(cmd, append([]string{"query", inputFile}, args...)...); err == nil {
+			t.Errorf("Expected error for query %v", args)
+		}
+	}
 }
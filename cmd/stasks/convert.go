@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/grokify/structured-tasks/tasks"
+	"github.com/spf13/cobra"
+)
+
+var convertTo string
+
+var convertCmd = &cobra.Command{
+	Use:   "convert <input> [output]",
+	Short: "Convert a task list between JSON, YAML and TOML",
+	Long: `Convert a task list between JSON, YAML and TOML.
+
+The input format is detected from the file extension or content. The output
+format is taken from --to, or from the output file extension. If no output
+file is given, the result is written to stdout.
+
+Example usage:
+  stasks convert TASKS.json TASKS.yaml
+  stasks convert TASKS.toml --to json`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runConvert,
+}
+
+func init() {
+	convertCmd.Flags().StringVar(&convertTo, "to", "", "Output format: json, yaml, toml")
+	addLabelFlag(convertCmd)
+}
+
+func runConvert(cmd *cobra.Command, args []string) error {
+	tl, err := tasks.ParseFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+	tl = filterByLabel(tl)
+
+	var output string
+	if len(args) > 1 {
+		output = args[1]
+	}
+
+	var format tasks.Format
+	switch {
+	case convertTo != "":
+		if format, err = tasks.ParseFormatName(convertTo); err != nil {
+			return err
+		}
+	case output != "":
+		if format = tasks.FormatFromPath(output); format == "" {
+			return fmt.Errorf("cannot determine output format from %s; use --to", output)
+		}
+	default:
+		return fmt.Errorf("output format is required when writing to stdout; use --to")
+	}
+
+	if output == "" {
+		data, err := tasks.Marshal(tl, format)
+		if err != nil {
+			return fmt.Errorf("failed to convert: %w", err)
+		}
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	}
+
+	if err := tasks.WriteFileFormat(output, tl, format); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Converted %s to %s\n", args[0], output)
+	return nil
+}
@@ -3,23 +3,57 @@ package main
 import (
 	"fmt"
 
+	"github.com/grokify/structured-tasks/portfolio"
 	"github.com/grokify/structured-tasks/renderer"
 	"github.com/grokify/structured-tasks/tasks"
 	"github.com/spf13/cobra"
 )
 
-var depsFormat string
+var (
+	depsFormat       string
+	depsCluster      string
+	depsIsolated     bool
+	depsDirection    string
+	depsLinks        bool
+	depsLinkTemplate string
+	depsLegend       bool
+	depsFocus        string
+	depsUp           int
+	depsDown         int
+	depsManifest     string
+	depsPackages     bool
+)
 
 var depsCmd = &cobra.Command{
 	Use:   "deps <file>",
 	Short: "Generate dependency graph",
-	Long:  `Generate a dependency graph from item dependencies in Mermaid or DOT format.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runDeps,
+	Long: `Generate a dependency graph from item dependencies.
+
+Supported formats are Mermaid, Graphviz DOT, PlantUML, D2, GraphML
+(for yEd and Gephi), and a JSON node/edge document.
+
+With --packages, the graph of internal packages declared in
+dependencies.internal is drawn instead, with an edge from each package to
+each package it depends on.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDeps,
 }
 
 func init() {
-	depsCmd.Flags().StringVar(&depsFormat, "format", "mermaid", "Output format: mermaid, dot")
+	depsCmd.Flags().StringVar(&depsFormat, "format", "mermaid", "Output format: mermaid, dot, plantuml, d2, graphml, json")
+	depsCmd.Flags().StringVar(&depsCluster, "cluster", "", "Cluster nodes into subgraphs: area, phase")
+	depsCmd.Flags().BoolVar(&depsIsolated, "isolated", false, "Include tasks without dependencies")
+	depsCmd.Flags().StringVar(&depsDirection, "direction", "", "Layout direction: TD, LR (default: TD for mermaid, LR for dot)")
+	depsCmd.Flags().BoolVar(&depsLinks, "links", false, "Make nodes clickable")
+	depsCmd.Flags().StringVar(&depsLinkTemplate, "link-template", renderer.DefaultLinkTemplate, "Link target for clickable nodes; {id} and {slug} are replaced")
+	depsCmd.Flags().BoolVar(&depsLegend, "legend", false, "Show legend for node shapes and colors")
+	depsCmd.Flags().StringVar(&depsFocus, "focus", "", "Only show the subgraph around this task ID")
+	depsCmd.Flags().IntVar(&depsUp, "up", 1, "Levels of prerequisites to include with --focus (-1 = all)")
+	depsCmd.Flags().IntVar(&depsDown, "down", 1, "Levels of dependents to include with --focus (-1 = all)")
+	depsCmd.Flags().BoolVar(&depsPackages, "packages", false, "Draw the internal package graph instead of task dependencies")
+	depsCmd.Flags().StringVar(&depsManifest, "manifest", "", "Manifest of sibling task lists for labeling project#task-id nodes")
+	addLabelFlag(depsCmd)
+	addFilterFlag(depsCmd)
 }
 
 func runDeps(cmd *cobra.Command, args []string) error {
@@ -30,21 +64,95 @@ func runDeps(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
+	gopts, err := buildGraphOptions()
+	if err != nil {
+		return err
+	}
+
+	if !depsPackages {
+		if r, err = selectTasks(r); err != nil {
+			return err
+		}
+	}
 	deps := renderer.BuildDependencyGraph(r)
+	if depsPackages {
+		if depsCluster != "" {
+			return fmt.Errorf("--cluster is not supported with --packages")
+		}
+		r, deps = renderer.PackageGraph(r)
+	}
+	if depsFocus != "" {
+		if _, ok := deps.TaskMap[depsFocus]; !ok {
+			return fmt.Errorf("unknown task: %s", depsFocus)
+		}
+		deps = deps.Focus(depsFocus, depsUp, depsDown)
+	}
 	out := cmd.OutOrStdout()
 
-	if len(deps.Edges) == 0 {
+	if len(deps.Edges) == 0 && !gopts.IncludeIsolated {
+		if depsPackages {
+			fmt.Fprintln(cmd.ErrOrStderr(), "No internal package dependencies found in task list")
+			return nil
+		}
 		fmt.Fprintln(cmd.ErrOrStderr(), "No dependencies found in task list")
 		return nil
 	}
 
 	switch depsFormat {
 	case "mermaid":
-		renderer.RenderMermaid(out, r, deps)
+		renderer.RenderMermaidWithOptions(out, r, deps, gopts)
 	case "dot":
-		renderer.RenderDOT(out, r, deps)
+		renderer.RenderDOTWithOptions(out, r, deps, gopts)
+	case "plantuml":
+		renderer.RenderPlantUMLWithOptions(out, r, deps, gopts)
+	case "d2":
+		renderer.RenderD2WithOptions(out, r, deps, gopts)
+	case "graphml":
+		renderer.RenderGraphMLWithOptions(out, r, deps, gopts)
+	case "json":
+		if err := renderer.RenderGraphJSONWithOptions(out, r, deps, gopts); err != nil {
+			return fmt.Errorf("failed to render JSON: %w", err)
+		}
 	default:
 		return fmt.Errorf("unknown format: %s", depsFormat)
 	}
 	return nil
 }
+
+func buildGraphOptions() (renderer.GraphOptions, error) {
+	gopts := renderer.DefaultGraphOptions().
+		WithIsolated(depsIsolated).
+		WithLegend(depsLegend)
+
+	switch depsCluster {
+	case "":
+	case "area":
+		gopts = gopts.WithClusterBy(renderer.ClusterByArea)
+	case "phase":
+		gopts = gopts.WithClusterBy(renderer.ClusterByPhase)
+	default:
+		return gopts, fmt.Errorf("unknown cluster value: %s", depsCluster)
+	}
+
+	switch depsDirection {
+	case "":
+	case "TD", "td", "TB", "tb":
+		gopts = gopts.WithDirection(renderer.DirectionTD)
+	case "LR", "lr":
+		gopts = gopts.WithDirection(renderer.DirectionLR)
+	default:
+		return gopts, fmt.Errorf("unknown direction: %s", depsDirection)
+	}
+
+	if depsLinks {
+		gopts = gopts.WithNodeLinks(depsLinkTemplate)
+	}
+	if depsManifest != "" {
+		resolver, err := portfolio.NewResolver(depsManifest)
+		if err != nil {
+			return gopts, fmt.Errorf("failed to read manifest: %w", err)
+		}
+		gopts = gopts.WithResolver(resolver)
+	}
+	return gopts, nil
+}
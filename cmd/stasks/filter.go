@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/grokify/structured-tasks/query"
+	"github.com/grokify/structured-tasks/tasks"
+	"github.com/spf13/cobra"
+)
+
+// filterExpr holds the --filter expression of the running command.
+var filterExpr string
+
+// addFilterFlag registers the --filter expression on a command.
+func addFilterFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&filterExpr, "filter", "", `Only include tasks matching a filter expression, e.g. "status != completed && phase <= 2"`)
+}
+
+// taskFilter parses --filter. It returns a nil filter, which matches every
+// task, if no expression was given.
+func taskFilter() (*query.Filter, error) {
+	if filterExpr == "" {
+		return nil, nil
+	}
+	f, err := query.Parse(filterExpr)
+	if err != nil {
+		return nil, fmt.Errorf("--filter: %w", err)
+	}
+	return f, nil
+}
+
+// selectTasks returns tl limited to the tasks selected by --label and
+// --filter, or tl itself if neither was given.
+func selectTasks(tl *tasks.TaskList) (*tasks.TaskList, error) {
+	f, err := taskFilter()
+	if err != nil {
+		return nil, err
+	}
+	tl = filterByLabel(tl)
+	if f == nil {
+		return tl, nil
+	}
+	return tl.Filter(f.Match), nil
+}
@@ -12,7 +12,9 @@ import (
 var (
 	genInput           string
 	genOutput          string
+	genFormat          string
 	genGroupBy         string
+	genSortBy          string
 	genCheckbox        bool
 	genEmoji           bool
 	genLegend          bool
@@ -20,6 +22,10 @@ var (
 	genTOC             bool
 	genTOCDepth        int
 	genOverview        bool
+	genMilestones      bool
+	genDependencies    bool
+	genCancelled       bool
+	genIssueLinks      bool
 	genAreaSubheadings bool
 	genNumbered        bool
 	genNoRules         bool
@@ -28,14 +34,19 @@ var (
 var generateCmd = &cobra.Command{
 	Use:   "generate",
 	Short: "Generate TASKS.md from TASKS.json",
-	Long:  `Generate a Markdown task list file from a JSON intermediate representation.`,
-	RunE:  runGenerate,
+	Long: `Generate a Markdown (or HTML) task list file from a JSON intermediate representation.
+
+HTML output uses the same grouping and includes sections and content blocks,
+but not the status table, table of contents or legend.`,
+	RunE: runGenerate,
 }
 
 func init() {
-	generateCmd.Flags().StringVarP(&genInput, "input", "i", "TASKS.json", "Input JSON file")
+	generateCmd.Flags().StringVarP(&genInput, "input", "i", "TASKS.json", "Input task list file (JSON, YAML or TOML)")
 	generateCmd.Flags().StringVarP(&genOutput, "output", "o", "", "Output Markdown file (default: stdout)")
-	generateCmd.Flags().StringVar(&genGroupBy, "group-by", "area", "Grouping: area, type, phase, status")
+	generateCmd.Flags().StringVarP(&genFormat, "format", "f", "markdown", "Output format: markdown, html")
+	generateCmd.Flags().StringVar(&genGroupBy, "group-by", "area", "Grouping: area, type, phase, status, version, milestone, label")
+	generateCmd.Flags().StringVar(&genSortBy, "sort", "status", "Sort within groups: order, priority, status, title, due, dependency")
 	generateCmd.Flags().BoolVar(&genCheckbox, "checkboxes", true, "Use [x]/[ ] checkbox syntax")
 	generateCmd.Flags().BoolVar(&genEmoji, "emoji", true, "Include emoji status indicators")
 	generateCmd.Flags().BoolVar(&genLegend, "legend", false, "Show legend table")
@@ -43,9 +54,15 @@ func init() {
 	generateCmd.Flags().BoolVar(&genTOC, "toc", false, "Show table of contents")
 	generateCmd.Flags().IntVar(&genTOCDepth, "toc-depth", 1, "TOC depth: 1 = sections only, 2 = sections + items")
 	generateCmd.Flags().BoolVar(&genOverview, "status-table", true, "Show status table at top")
+	generateCmd.Flags().BoolVar(&genMilestones, "milestone-table", true, "Show milestone summary table at top (if milestones are defined)")
+	generateCmd.Flags().BoolVar(&genDependencies, "dependencies", true, "Show dependencies section after the tasks (if dependencies are defined)")
+	generateCmd.Flags().BoolVar(&genCancelled, "cancelled", false, "Include cancelled tasks")
+	generateCmd.Flags().BoolVar(&genIssueLinks, "issue-links", false, "Link each task's primary issue in the overview table")
 	generateCmd.Flags().BoolVar(&genAreaSubheadings, "area-subheadings", false, "Show area sub-sections within phases (use with --group-by phase)")
 	generateCmd.Flags().BoolVar(&genNumbered, "numbered", false, "Number items")
 	generateCmd.Flags().BoolVar(&genNoRules, "no-rules", false, "Omit horizontal rules between sections")
+	addLabelFlag(generateCmd)
+	addFilterFlag(generateCmd)
 }
 
 func runGenerate(cmd *cobra.Command, args []string) error {
@@ -54,16 +71,30 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to read input: %w", err)
 	}
 
-	// Validate first
-	result := tasks.Validate(r)
+	filter, err := taskFilter()
+	if err != nil {
+		return err
+	}
+
+	// Validate first, skipping findings on tasks filtered out by --label or
+	// --filter
+	vopts := tasks.DefaultValidateOptions()
+	if labels := labelFilter(); !labels.IsEmpty() || filter != nil {
+		vopts = vopts.WithTasks(func(task tasks.Task) bool {
+			return labels.Match(task) && filter.Match(task)
+		})
+	}
+	result := tasks.ValidateWithOptions(r, vopts)
 	if !result.Valid {
 		fmt.Fprintf(cmd.ErrOrStderr(), "Validation errors in %s:\n", genInput)
 		for _, e := range result.Errors {
-			fmt.Fprintf(cmd.ErrOrStderr(), "  • %s: %s\n", e.Field, e.Message)
+			printDiagnostic(cmd.ErrOrStderr(), genInput, e)
 		}
 		return fmt.Errorf("validation failed with %d error(s)", len(result.Errors))
 	}
 
+	r = filterByLabel(r)
+
 	// Build options
 	opts := renderer.DefaultOptions()
 	opts.UseCheckboxes = genCheckbox
@@ -73,6 +104,11 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	opts.ShowTOC = genTOC
 	opts.TOCDepth = genTOCDepth
 	opts.ShowOverviewTable = genOverview
+	opts.ShowMilestoneTable = genMilestones
+	opts.ShowDependencies = genDependencies
+	opts.ShowCancelled = genCancelled
+	opts.Filter = filter
+	opts.ShowIssueLinks = genIssueLinks
 	opts.ShowAreaSubheadings = genAreaSubheadings
 	opts.NumberItems = genNumbered
 	opts.HorizontalRules = !genNoRules
@@ -86,12 +122,36 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		opts.GroupBy = renderer.GroupByPhase
 	case "status":
 		opts.GroupBy = renderer.GroupByStatus
+	case "version":
+		opts.GroupBy = renderer.GroupByVersion
+	case "milestone":
+		opts.GroupBy = renderer.GroupByMilestone
+	case "label":
+		opts.GroupBy = renderer.GroupByLabel
 	default:
 		return fmt.Errorf("unknown group-by value: %s", genGroupBy)
 	}
 
+	switch sortBy := renderer.SortBy(genSortBy); sortBy {
+	case renderer.SortByOrder, renderer.SortByPriority, renderer.SortByStatus,
+		renderer.SortByTitle, renderer.SortByDueDate, renderer.SortByDependency:
+		opts.SortBy = sortBy
+	default:
+		return fmt.Errorf("unknown sort value: %s", genSortBy)
+	}
+
 	// Render
-	output := renderer.Render(r, opts)
+	var output string
+	switch genFormat {
+	case "markdown", "md":
+		output = renderer.Render(r, opts)
+	case "html":
+		if output, err = renderer.RenderHTML(r, opts); err != nil {
+			return fmt.Errorf("failed to render HTML: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown format: %s (supported: markdown, html)", genFormat)
+	}
 
 	// Write output
 	if genOutput == "" {
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/grokify/structured-tasks/renderer"
+	"github.com/grokify/structured-tasks/tasks"
+	"github.com/spf13/cobra"
+)
+
+var impactInput string
+
+var impactCmd = &cobra.Command{
+	Use:   "impact <task-id>",
+	Short: "List tasks that would slip if a task slips",
+	Long:  `List every task that transitively depends on the given task, grouped by phase.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runImpact,
+}
+
+func init() {
+	impactCmd.Flags().StringVarP(&impactInput, "input", "i", "TASKS.json", "Input task list file (JSON, YAML or TOML)")
+	addLabelFlag(impactCmd)
+}
+
+func runImpact(cmd *cobra.Command, args []string) error {
+	id := args[0]
+
+	tl, err := tasks.ParseFile(impactInput)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	deps := renderer.BuildDependencyGraph(tl)
+	task, ok := deps.TaskMap[id]
+	if !ok {
+		return fmt.Errorf("unknown task: %s", id)
+	}
+
+	// The graph is walked in full; --label only limits the tasks listed.
+	filter := labelFilter()
+	impacted := make(map[string]bool)
+	for _, dep := range deps.Impact(id) {
+		if t, ok := deps.TaskMap[dep]; !ok || filter.Match(t) {
+			impacted[dep] = true
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Impact of %s (%s): %d task(s) would slip\n", id, task.Title, len(impacted))
+	if len(impacted) == 0 {
+		return nil
+	}
+
+	legend := tl.GetLegend()
+	tasksByPhase := tl.TasksByPhase()
+	printPhase := func(title string, phaseTasks []tasks.Task) {
+		header := false
+		for _, t := range phaseTasks {
+			if !impacted[t.ID] {
+				continue
+			}
+			if !header {
+				fmt.Fprintf(out, "\n%s:\n", title)
+				header = true
+			}
+			fmt.Fprintf(out, "  %s %s: %s\n", legend[t.Status].Emoji, t.ID, t.Title)
+		}
+	}
+	for _, phase := range tl.PhaseNumbers() {
+		printPhase(tl.PhaseName(phase), tasksByPhase[phase])
+	}
+	printPhase(tl.PhaseName(0), tasksByPhase[0])
+	return nil
+}
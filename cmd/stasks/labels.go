@@ -0,0 +1,30 @@
+package main
+
+import (
+	"github.com/grokify/structured-tasks/tasks"
+	"github.com/spf13/cobra"
+)
+
+// labelFlags holds the --label values of the running command. Values
+// prefixed with "!" exclude tasks with that label.
+var labelFlags []string
+
+// addLabelFlag registers the --label filter on a command.
+func addLabelFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&labelFlags, "label", nil, "Only include tasks with this label; prefix with ! to exclude (repeatable)")
+}
+
+// labelFilter returns the filter given by --label.
+func labelFilter() tasks.LabelFilter {
+	return tasks.ParseLabelFilter(labelFlags)
+}
+
+// filterByLabel returns tl limited to the tasks selected by --label, or tl
+// itself if no labels were given.
+func filterByLabel(tl *tasks.TaskList) *tasks.TaskList {
+	f := labelFilter()
+	if f.IsEmpty() {
+		return tl
+	}
+	return tl.Filter(f.Match)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/grokify/structured-tasks/tasks"
+	"github.com/spf13/cobra"
+)
+
+var mergeTo string
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge <input> [output]",
+	Short: "Flatten a task list and its includes into one file",
+	Long: `Flatten a task list and all files it includes into a single task list.
+
+The output format is taken from --to, or from the output file extension,
+defaulting to the input format. If no output file is given, the result is
+written to stdout.
+
+Example usage:
+  stasks merge TASKS.json TASKS.merged.json
+  stasks merge TASKS.yaml --to json`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runMerge,
+}
+
+func init() {
+	mergeCmd.Flags().StringVar(&mergeTo, "to", "", "Output format: json, yaml, toml")
+	addLabelFlag(mergeCmd)
+}
+
+func runMerge(cmd *cobra.Command, args []string) error {
+	tl, err := tasks.ParseFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+	tl = filterByLabel(tl)
+
+	var output string
+	if len(args) > 1 {
+		output = args[1]
+	}
+
+	format := tasks.FormatFromPath(output)
+	if mergeTo != "" {
+		if format, err = tasks.ParseFormatName(mergeTo); err != nil {
+			return err
+		}
+	}
+	if format == "" {
+		if format = tasks.FormatFromPath(args[0]); format == "" {
+			format = tasks.FormatJSON
+		}
+	}
+
+	if output == "" {
+		data, err := tasks.Marshal(tl, format)
+		if err != nil {
+			return fmt.Errorf("failed to merge: %w", err)
+		}
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	}
+
+	if err := tasks.WriteFileFormat(output, tl, format); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Merged %d file(s) into %s (%d tasks)\n", len(tl.IncludedFiles())+1, output, len(tl.Tasks))
+	return nil
+}
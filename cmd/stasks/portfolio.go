@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/grokify/structured-tasks/portfolio"
+	"github.com/spf13/cobra"
+)
+
+var (
+	portfolioManifest  string
+	portfolioFormat    string
+	portfolioOutput    string
+	portfolioTitle     string
+	portfolioCompleted bool
+	portfolioSections  bool
+)
+
+var portfolioCmd = &cobra.Command{
+	Use:   "portfolio [files...]",
+	Short: "Render a combined report for several projects",
+	Long: `Render a combined report for several task lists.
+
+Projects are given as task list files or listed in a JSON manifest:
+
+  {
+    "name": "Platform",
+    "projects": [
+      {"path": "../api/TASKS.json"},
+      {"key": "web", "path": "../web-app/TASKS.json"}
+    ]
+  }
+
+Task IDs are prefixed with each project's key (key#task-id). The key
+defaults to a slug of the project name.
+
+Example usage:
+  stasks portfolio api/TASKS.json web/TASKS.json
+  stasks portfolio --manifest portfolio.json --format html -o portfolio.html`,
+	RunE: runPortfolio,
+}
+
+func init() {
+	portfolioCmd.Flags().StringVarP(&portfolioManifest, "manifest", "m", "", "Manifest file listing task lists")
+	portfolioCmd.Flags().StringVarP(&portfolioFormat, "format", "f", "markdown", "Output format: markdown, html")
+	portfolioCmd.Flags().StringVarP(&portfolioOutput, "output", "o", "", "Output file (default: stdout)")
+	portfolioCmd.Flags().StringVar(&portfolioTitle, "title", "Portfolio", "Report title")
+	portfolioCmd.Flags().BoolVar(&portfolioCompleted, "show-completed", false, "Include completed tasks in the overview")
+	portfolioCmd.Flags().BoolVar(&portfolioSections, "project-sections", true, "Render a section per project")
+	addLabelFlag(portfolioCmd)
+}
+
+func runPortfolio(cmd *cobra.Command, args []string) error {
+	var (
+		p   *portfolio.Portfolio
+		err error
+	)
+	switch {
+	case portfolioManifest != "":
+		if p, err = portfolio.LoadManifest(portfolioManifest); err != nil {
+			return err
+		}
+		for _, path := range args {
+			if err := p.AddFile(path, ""); err != nil {
+				return err
+			}
+		}
+	case len(args) > 0:
+		if p, err = portfolio.Load(args...); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("no projects given; pass task list files or --manifest")
+	}
+
+	for i := range p.Projects {
+		p.Projects[i].Tasks = filterByLabel(p.Projects[i].Tasks)
+	}
+
+	opts := portfolio.DefaultOptions().
+		WithTitle(portfolioTitle).
+		WithCompleted(portfolioCompleted).
+		WithProjectSections(portfolioSections)
+
+	var output string
+	switch portfolioFormat {
+	case "markdown", "md":
+		output = portfolio.RenderMarkdown(p, opts)
+	case "html":
+		if output, err = portfolio.RenderHTML(p, opts); err != nil {
+			return fmt.Errorf("failed to render HTML: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown format: %s (supported: markdown, html)", portfolioFormat)
+	}
+
+	if portfolioOutput == "" {
+		fmt.Fprint(cmd.OutOrStdout(), output)
+		return nil
+	}
+	if err := os.WriteFile(portfolioOutput, []byte(output), 0600); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Generated %s (%d projects)\n", portfolioOutput, len(p.Projects))
+	return nil
+}
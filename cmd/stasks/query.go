@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/grokify/structured-tasks/query"
+	"github.com/grokify/structured-tasks/tasks"
+	"github.com/spf13/cobra"
+)
+
+var (
+	queryFields   []string
+	queryFormat   string
+	queryCount    bool
+	queryArchived bool
+)
+
+var queryCmd = &cobra.Command{
+	Use:   "query <file> [expression]",
+	Short: "List tasks matching a filter expression",
+	Long: `List the tasks matching a filter expression, or all tasks if none is given.
+
+The expression uses the same language as --filter, for example:
+  status != completed && area in (api, core) && phase <= 2
+
+Output is a table, JSON, NDJSON (one object per line) or CSV, with the
+fields given by --fields. --count prints the number of matching tasks.
+Archived tasks are left out unless --archived is set.
+
+Examples:
+  stasks query TASKS.json 'status = blocked'
+  stasks query TASKS.json 'labels = security' --fields id,title,priority --format json
+  stasks query TASKS.json 'phase <= 2' --count
+  stasks query TASKS.json 'archived = true' --archived`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runQuery,
+}
+
+func init() {
+	queryCmd.Flags().StringSliceVar(&queryFields, "fields", nil, fmt.Sprintf("Fields to output (default %s); one of %s",
+		strings.Join(query.DefaultFields, ","), strings.Join(query.Fields(), ", ")))
+	queryCmd.Flags().StringVarP(&queryFormat, "format", "f", "table", "Output format: table, json, ndjson, csv")
+	queryCmd.Flags().BoolVar(&queryCount, "count", false, "Only print the number of matching tasks")
+	queryCmd.Flags().BoolVar(&queryArchived, "archived", false, "Include archived tasks")
+	addLabelFlag(queryCmd)
+}
+
+func runQuery(cmd *cobra.Command, args []string) error {
+	var filter *query.Filter
+	if len(args) > 1 && strings.TrimSpace(args[1]) != "" {
+		f, err := query.Parse(args[1])
+		if err != nil {
+			return err
+		}
+		filter = f
+	}
+	projection, err := query.NewProjection(queryFields)
+	if err != nil {
+		return err
+	}
+
+	tl, err := tasks.ParseFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	if !queryArchived {
+		tl = tl.WithoutArchived()
+	}
+	matches := filterByLabel(tl).Filter(filter.Match).Tasks
+
+	out := cmd.OutOrStdout()
+	if queryCount {
+		fmt.Fprintln(out, len(matches))
+		return nil
+	}
+
+	switch queryFormat {
+	case "table":
+		return writeQueryTable(out, projection, matches)
+	case "json":
+		records := make([]query.Record, len(matches))
+		for i, task := range matches {
+			records[i] = projection.Record(task)
+		}
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(data))
+	case "ndjson":
+		enc := json.NewEncoder(out)
+		for _, task := range matches {
+			if err := enc.Encode(projection.Record(task)); err != nil {
+				return err
+			}
+		}
+	case "csv":
+		w := csv.NewWriter(out)
+		if err := w.Write(projection.Fields()); err != nil {
+			return err
+		}
+		for _, task := range matches {
+			if err := w.Write(projection.Strings(task)); err != nil {
+				return err
+			}
+		}
+		w.Flush()
+		return w.Error()
+	default:
+		return fmt.Errorf("unknown format: %s (supported: table, json, ndjson, csv)", queryFormat)
+	}
+	return nil
+}
+
+// writeQueryTable writes tasks as aligned columns headed by field names.
+func writeQueryTable(out io.Writer, projection query.Projection, matches []tasks.Task) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.ToUpper(strings.Join(projection.Fields(), "\t")))
+	for _, task := range matches {
+		fmt.Fprintln(w, strings.Join(projection.Strings(task), "\t"))
+	}
+	return w.Flush()
+}
@@ -42,5 +42,13 @@ func init() {
 	rootCmd.AddCommand(generateCmd)
 	rootCmd.AddCommand(statsCmd)
 	rootCmd.AddCommand(depsCmd)
+	rootCmd.AddCommand(impactCmd)
+	rootCmd.AddCommand(queryCmd)
+	rootCmd.AddCommand(convertCmd)
+	rootCmd.AddCommand(mergeCmd)
+	rootCmd.AddCommand(splitCmd)
+	rootCmd.AddCommand(portfolioCmd)
+	rootCmd.AddCommand(archiveCmd)
+	rootCmd.AddCommand(syncCmd)
 	rootCmd.AddCommand(versionCmd)
 }
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/grokify/structured-tasks/tasks"
+)
+
+// SARIF 2.1.0 document types, limited to the fields stasks emits.
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// sarifLevel maps a validation severity to a SARIF result level.
+func sarifLevel(s tasks.Severity) string {
+	switch s {
+	case tasks.SeverityError:
+		return "error"
+	case tasks.SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// buildSARIF converts a validation result into a SARIF 2.1.0 log.
+func buildSARIF(path string, result tasks.ValidationResult) sarifLog {
+	driver := sarifDriver{
+		Name:           "stasks",
+		Version:        version,
+		InformationURI: "https://github.com/grokify/structured-tasks",
+		Rules:          []sarifRule{},
+	}
+	ruleIndex := make(map[tasks.Rule]int)
+	for i, rule := range tasks.Rules() {
+		ruleIndex[rule.Code] = i
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   string(rule.Code),
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(rule.Severity)},
+		})
+	}
+
+	results := []sarifResult{}
+	for _, d := range result.Diagnostics() {
+		uri := d.Pos.File
+		if uri == "" {
+			uri = path
+		}
+		loc := sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(uri)},
+		}
+		if d.Pos.IsValid() {
+			loc.Region = &sarifRegion{StartLine: d.Pos.Line, StartColumn: d.Pos.Column}
+		}
+		results = append(results, sarifResult{
+			RuleID:    string(d.Code),
+			RuleIndex: ruleIndex[d.Code],
+			Level:     sarifLevel(d.Severity),
+			Message:   sarifMessage{Text: d.Error()},
+			Locations: []sarifLocation{{PhysicalLocation: loc}},
+		})
+	}
+
+	return sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/grokify/structured-tasks/tasks"
+	"github.com/spf13/cobra"
+)
+
+var (
+	splitBy     string
+	splitDir    string
+	splitOutput string
+	splitForce  bool
+)
+
+var splitCmd = &cobra.Command{
+	Use:   "split <input>",
+	Short: "Split a task list into one file per area",
+	Long: `Split a task list into one file per area, linked by an include list.
+
+Each declared area and its tasks are written to <dir>/<area-id>.<ext>, where
+dir is relative to the output file, which lists them as includes. The input
+file (or --output) is rewritten with the remaining top-level fields, tasks without a declared area, and an
+include entry for each area file. Includes in the input are merged first.
+With --label, only matching tasks are written, so --output is required.
+
+Example usage:
+  stasks split TASKS.json --by area
+  stasks split TASKS.json --by area --dir roadmap`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSplit,
+}
+
+func init() {
+	splitCmd.Flags().StringVar(&splitBy, "by", "area", "Split strategy: area")
+	splitCmd.Flags().StringVar(&splitDir, "dir", "tasks", "Directory for area files, relative to the output file")
+	splitCmd.Flags().StringVarP(&splitOutput, "output", "o", "", "Output file for the top-level task list (default: overwrite input)")
+	splitCmd.Flags().BoolVar(&splitForce, "force", false, "Overwrite existing area files")
+	addLabelFlag(splitCmd)
+}
+
+// unsafeFileChars matches characters not allowed in generated file names.
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func runSplit(cmd *cobra.Command, args []string) error {
+	if splitBy != "area" {
+		return fmt.Errorf("unknown split strategy: %s (supported: area)", splitBy)
+	}
+
+	input := args[0]
+	if !labelFilter().IsEmpty() && splitOutput == "" {
+		// Overwriting the input would drop the tasks left out
+		return fmt.Errorf("--label requires --output")
+	}
+	tl, err := tasks.ParseFile(input)
+	if err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+	tl = filterByLabel(tl)
+
+	format, ext := tasks.FormatFromPath(input), filepath.Ext(input)
+	if format == "" {
+		format, ext = tasks.FormatJSON, ".json"
+	}
+	output := splitOutput
+	if output == "" {
+		output = input
+	}
+
+	rest, parts := tasks.SplitByArea(tl)
+	if len(parts) == 0 {
+		return fmt.Errorf("no areas declared in %s", input)
+	}
+
+	// Resolve all file names first so nothing is written on error
+	baseDir := filepath.Dir(output)
+	files := make([]string, len(parts))
+	seen := make(map[string]bool)
+	for i, part := range parts {
+		name := unsafeFileChars.ReplaceAllString(part.Areas[0].ID, "-") + ext
+		if seen[name] {
+			return fmt.Errorf("areas map to the same file name: %s", name)
+		}
+		seen[name] = true
+		files[i] = filepath.Join(baseDir, splitDir, name)
+		if _, err := os.Stat(files[i]); err == nil && !splitForce {
+			return fmt.Errorf("%s already exists; use --force to overwrite", files[i])
+		}
+		rest.Include = append(rest.Include, filepath.ToSlash(filepath.Join(splitDir, name)))
+	}
+
+	if err := os.MkdirAll(filepath.Join(baseDir, splitDir), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", splitDir, err)
+	}
+	for i, part := range parts {
+		if err := tasks.WriteFileFormat(files[i], part, format); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Wrote %s (%d tasks)\n", files[i], len(part.Tasks))
+	}
+	if err := tasks.WriteFileFormat(output, rest, format); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Wrote %s (%d tasks, %d includes)\n", output, len(rest.Tasks), len(rest.Include))
+	return nil
+}
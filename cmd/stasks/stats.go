@@ -11,9 +11,15 @@ import (
 var statsCmd = &cobra.Command{
 	Use:   "stats <file>",
 	Short: "Show task list statistics",
-	Long:  `Display statistics about tasks, statuses, and categories in a task list.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runStats,
+	Long: `Display statistics about tasks, statuses, and categories in a task list.
+Archived tasks are not counted.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runStats,
+}
+
+func init() {
+	addLabelFlag(statsCmd)
+	addFilterFlag(statsCmd)
 }
 
 func runStats(cmd *cobra.Command, args []string) error {
@@ -24,6 +30,9 @@ func runStats(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
+	if tl, err = selectTasks(tl.WithoutArchived()); err != nil {
+		return err
+	}
 	stats := tl.Stats()
 	out := cmd.OutOrStdout()
 
@@ -32,13 +41,11 @@ func runStats(cmd *cobra.Command, args []string) error {
 
 	// Status breakdown
 	fmt.Fprintln(out, "By Status:")
-	legend := tl.GetLegend()
-	for _, status := range tasks.StatusOrder() {
-		count := stats.ByStatus[status]
+	for _, status := range tl.StatusRegistry().All() {
+		count := stats.ByStatus[status.ID]
 		if count > 0 {
 			pct := float64(count) / float64(stats.Total) * 100
-			entry := legend[status]
-			fmt.Fprintf(out, "  %s %s: %d (%.0f%%)\n", entry.Emoji, entry.Description, count, pct)
+			fmt.Fprintf(out, "  %s %s: %d (%.0f%%)\n", status.Emoji, status.Description, count, pct)
 		}
 	}
 
@@ -88,6 +95,16 @@ func runStats(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Label breakdown, in label order
+	if len(stats.ByLabel) > 0 {
+		fmt.Fprintln(out, "\nBy Label:")
+		for _, label := range tl.LabelIDs() {
+			if count := stats.ByLabel[label]; count > 0 {
+				fmt.Fprintf(out, "  %s: %d\n", tl.LabelName(label), count)
+			}
+		}
+	}
+
 	// Phase breakdown
 	phases := tl.PhaseNumbers()
 	if len(phases) > 0 {
@@ -95,7 +112,7 @@ func runStats(cmd *cobra.Command, args []string) error {
 		tasksByPhase := tl.TasksByPhase()
 		for _, phase := range phases {
 			phaseTasks := tasksByPhase[phase]
-			fmt.Fprintf(out, "  Phase %d: %d tasks\n", phase, len(phaseTasks))
+			fmt.Fprintf(out, "  %s: %d tasks\n", tl.PhaseName(phase), len(phaseTasks))
 		}
 		// Unphased tasks
 		if unphasedTasks := tasksByPhase[0]; len(unphasedTasks) > 0 {
@@ -103,11 +120,11 @@ func runStats(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Progress
-	completedPct := 0.0
-	if stats.Total > 0 {
-		completedPct = float64(stats.CompletedCount()) / float64(stats.Total) * 100
+	// Progress, excluding cancelled tasks
+	fmt.Fprintf(out, "\nProgress: %.0f%% complete", stats.Progress())
+	if stats.Cancelled > 0 {
+		fmt.Fprintf(out, " (%d cancelled excluded)", stats.Cancelled)
 	}
-	fmt.Fprintf(out, "\nProgress: %.0f%% complete\n", completedPct)
+	fmt.Fprintln(out)
 	return nil
 }
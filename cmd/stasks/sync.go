@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/grokify/structured-changelog/changelog"
+	"github.com/grokify/structured-tasks/tasks"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncChangelog string
+	syncCheck     bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [input]",
+	Short: "Fill in task versions from CHANGELOG.json",
+	Long: `Match completed tasks to the changelog releases that mention them and
+fill in their version field.
+
+A changelog entry refers to a task if its issue is the task ID, its
+description marks the task ID as "(#id)" or "[id]", or its description is
+the task title. Tasks whose version differs from the changelog are reported
+but not changed.
+
+With --check, nothing is written and the command fails if any completed task
+is missing its version or has a mismatched one.
+
+Example usage:
+  stasks sync
+  stasks sync TASKS.json --changelog CHANGELOG.json --check`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSync,
+}
+
+func init() {
+	syncCmd.Flags().StringVar(&syncChangelog, "changelog", "CHANGELOG.json", "Changelog file to read")
+	syncCmd.Flags().BoolVar(&syncCheck, "check", false, "Report differences without writing; fail if out of sync")
+	addLabelFlag(syncCmd)
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	input := "TASKS.json"
+	if len(args) > 0 {
+		input = args[0]
+	}
+
+	tl, err := tasks.ParseFile(input)
+	if err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+	if !syncCheck && len(tl.IncludedFiles()) > 0 {
+		return fmt.Errorf("%s uses include; run 'stasks merge' first", input)
+	}
+	cl, err := changelog.LoadFile(syncChangelog)
+	if err != nil {
+		return fmt.Errorf("failed to read changelog: %w", err)
+	}
+
+	result := tasks.SyncVersions(tl, cl, false)
+	if filter := labelFilter(); !filter.IsEmpty() {
+		result = filterSyncResult(tl, result, filter)
+	}
+	if !syncCheck {
+		for _, c := range result.Missing {
+			tl.Tasks[c.Index].Version = c.Release
+		}
+	}
+	out := cmd.ErrOrStderr()
+	for _, c := range result.Missing {
+		if syncCheck {
+			fmt.Fprintf(out, "%s: missing version (changelog: %s)\n", c.TaskID, c.Release)
+		} else {
+			fmt.Fprintf(out, "%s: set version %s\n", c.TaskID, c.Release)
+		}
+	}
+	for _, c := range result.Mismatched {
+		fmt.Fprintf(out, "%s: version %s, but changelog lists it in %s\n", c.TaskID, c.Version, c.Release)
+	}
+
+	if syncCheck {
+		if !result.InSync() {
+			return fmt.Errorf("%d task version(s) out of sync with %s", len(result.Missing)+len(result.Mismatched), syncChangelog)
+		}
+		fmt.Fprintf(out, "Task versions match %s\n", syncChangelog)
+		return nil
+	}
+
+	if len(result.Missing) > 0 {
+		format := tasks.FormatFromPath(input)
+		if format == "" {
+			format = tasks.FormatJSON
+		}
+		if err := tasks.WriteFileFormat(input, tl, format); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(out, "Updated %d task version(s) from %s\n", len(result.Missing), syncChangelog)
+	return nil
+}
+
+// filterSyncResult drops the changes of tasks the filter does not select.
+func filterSyncResult(tl *tasks.TaskList, result tasks.SyncResult, filter tasks.LabelFilter) tasks.SyncResult {
+	var filtered tasks.SyncResult
+	for _, c := range result.Missing {
+		if filter.Match(tl.Tasks[c.Index]) {
+			filtered.Missing = append(filtered.Missing, c)
+		}
+	}
+	for _, c := range result.Mismatched {
+		if filter.Match(tl.Tasks[c.Index]) {
+			filtered.Mismatched = append(filtered.Mismatched, c)
+		}
+	}
+	for _, id := range result.Unmatched {
+		if task, ok := tl.TaskByID(id); !ok || filter.Match(task) {
+			filtered.Unmatched = append(filtered.Unmatched, id)
+		}
+	}
+	return filtered
+}
@@ -1,41 +1,178 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 
+	"github.com/grokify/structured-tasks/portfolio"
 	"github.com/grokify/structured-tasks/tasks"
 	"github.com/spf13/cobra"
 )
 
+var (
+	validateStrict    bool
+	validateDisable   []string
+	validateListRules bool
+	validateOutput    string
+	validateManifest  string
+)
+
 var validateCmd = &cobra.Command{
 	Use:   "validate <file>",
 	Short: "Validate a TASKS.json file",
-	Long:  `Validate a TASKS.json file against the schema and check for errors.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runValidate,
+	Long: `Validate a TASKS.json file against the schema and check for errors.
+
+Findings have a severity (error, warning, info) and a rule code. Only errors
+fail validation unless --strict is set. Use --disable to skip rules and
+--list-rules to see all rule codes.
+
+Use --output json or --output sarif to write machine-readable results to
+stdout, e.g. for GitHub code scanning. A file that cannot be parsed is
+reported as a parse-error finding.
+
+Cross-project dependencies (other-project#task-id) are checked against the
+task lists in --manifest; without one, they are reported as warnings.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if validateListRules {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: runValidate,
+}
+
+func init() {
+	validateCmd.Flags().BoolVar(&validateStrict, "strict", false, "Treat warnings as errors")
+	validateCmd.Flags().StringSliceVar(&validateDisable, "disable", nil, "Disable validation rules by code (repeatable)")
+	validateCmd.Flags().BoolVar(&validateListRules, "list-rules", false, "List validation rules and exit")
+	validateCmd.Flags().StringVar(&validateOutput, "output", "text", "Output format: text, json, sarif")
+	validateCmd.Flags().StringVar(&validateManifest, "manifest", "", "Manifest of sibling task lists for checking project#task-id references")
+	addLabelFlag(validateCmd)
 }
 
 func runValidate(cmd *cobra.Command, args []string) error {
+	if validateListRules {
+		for _, rule := range tasks.Rules() {
+			fmt.Fprintf(cmd.OutOrStdout(), "%-26s %-8s %s\n", rule.Code, rule.Severity, rule.Description)
+		}
+		return nil
+	}
+
 	path := args[0]
 
-	tl, err := tasks.ParseFile(path)
+	opts, err := buildValidateOptions()
 	if err != nil {
+		return err
+	}
+
+	var result tasks.ValidationResult
+	tl, err := tasks.ParseFile(path)
+	switch {
+	case err == nil:
+		result = tasks.ValidateWithOptions(tl, opts)
+	case validateOutput == "text":
 		return fmt.Errorf("failed to read file: %w", err)
+	default:
+		// Report the parse error as a finding so json and sarif output
+		// stay machine-readable
+		result = tasks.ParseFailure(err)
 	}
+	stderr := cmd.ErrOrStderr()
 
-	result := tasks.Validate(tl)
+	switch validateOutput {
+	case "text":
+	case "json", "sarif":
+		var doc any = result
+		if validateOutput == "sarif" {
+			doc = buildSARIF(path, result)
+		}
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(doc); err != nil {
+			return fmt.Errorf("failed to write %s output: %w", validateOutput, err)
+		}
+		if !result.Valid {
+			// Keep stdout machine-readable
+			cmd.SilenceUsage = true
+			return fmt.Errorf("validation failed with %d error(s) and %d warning(s)", len(result.Errors), len(result.Warnings))
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format: %s", validateOutput)
+	}
 
 	if result.Valid {
-		fmt.Fprintf(cmd.ErrOrStderr(), "✅ %s is valid\n", path)
-		fmt.Fprintf(cmd.ErrOrStderr(), "   Project: %s\n", tl.Project)
-		fmt.Fprintf(cmd.ErrOrStderr(), "   Tasks: %d\n", len(tl.Tasks))
-		fmt.Fprintf(cmd.ErrOrStderr(), "   Areas: %d\n", len(tl.Areas))
+		printDiagnostics(stderr, path, result)
+		fmt.Fprintf(stderr, "✅ %s is valid\n", path)
+		fmt.Fprintf(stderr, "   Project: %s\n", tl.Project)
+		fmt.Fprintf(stderr, "   Tasks: %d\n", len(tl.Tasks))
+		fmt.Fprintf(stderr, "   Areas: %d\n", len(tl.Areas))
 		return nil
 	}
 
-	fmt.Fprintf(cmd.ErrOrStderr(), "❌ %s has %d error(s)\n\n", path, len(result.Errors))
-	for _, e := range result.Errors {
-		fmt.Fprintf(cmd.ErrOrStderr(), "  • %s: %s\n", e.Field, e.Message)
+	if len(result.Errors) == 0 {
+		fmt.Fprintf(stderr, "❌ %s has %d warning(s) in strict mode\n\n", path, len(result.Warnings))
+		printDiagnostics(stderr, path, result)
+		return fmt.Errorf("validation failed with %d warning(s) in strict mode", len(result.Warnings))
 	}
+	fmt.Fprintf(stderr, "❌ %s has %d error(s)\n\n", path, len(result.Errors))
+	printDiagnostics(stderr, path, result)
 	return fmt.Errorf("validation failed with %d error(s)", len(result.Errors))
 }
+
+// buildValidateOptions converts validate flags into validation options.
+func buildValidateOptions() (tasks.ValidateOptions, error) {
+	opts := tasks.DefaultValidateOptions().WithStrict(validateStrict)
+	for _, code := range validateDisable {
+		if _, ok := tasks.LookupRule(tasks.Rule(code)); !ok {
+			return opts, fmt.Errorf("unknown rule: %s", code)
+		}
+		opts = opts.WithDisabled(tasks.Rule(code))
+	}
+	if f := labelFilter(); !f.IsEmpty() {
+		opts = opts.WithTasks(f.Match)
+	}
+	if validateManifest != "" {
+		resolver, err := portfolio.NewResolver(validateManifest)
+		if err != nil {
+			return opts, fmt.Errorf("failed to read manifest: %w", err)
+		}
+		opts = opts.WithResolver(resolver)
+	}
+	return opts, nil
+}
+
+// printDiagnostics writes all findings grouped by severity. Each finding is
+// printed in file:line:col form so editors can jump to it.
+func printDiagnostics(w io.Writer, path string, result tasks.ValidationResult) {
+	for _, e := range result.Errors {
+		printDiagnostic(w, path, e)
+	}
+	if len(result.Errors) > 0 && len(result.Warnings)+len(result.Infos) > 0 {
+		fmt.Fprintln(w)
+	}
+	if len(result.Warnings) > 0 {
+		fmt.Fprintf(w, "⚠️  %s has %d warning(s)\n\n", path, len(result.Warnings))
+		for _, e := range result.Warnings {
+			printDiagnostic(w, path, e)
+		}
+		fmt.Fprintln(w)
+	}
+	if len(result.Infos) > 0 {
+		fmt.Fprintf(w, "ℹ️  %s has %d note(s)\n\n", path, len(result.Infos))
+		for _, e := range result.Infos {
+			printDiagnostic(w, path, e)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// printDiagnostic writes a single finding as "file:line:col: severity [code] field: message".
+func printDiagnostic(w io.Writer, path string, e tasks.ValidationError) {
+	pos := e.Pos
+	if pos.File == "" {
+		pos.File = path
+	}
+	fmt.Fprintf(w, "%s: %s [%s] %s: %s\n", pos, e.Severity, e.Code, e.Field, e.Message)
+}
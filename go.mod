@@ -3,8 +3,10 @@ module github.com/grokify/structured-tasks
 go 1.24.0
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/grokify/structured-changelog v0.10.0
 	github.com/spf13/cobra v1.10.2
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
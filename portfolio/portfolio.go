@@ -0,0 +1,287 @@
+// Package portfolio aggregates task lists from several projects into a
+// single portfolio view with combined statistics and reports.
+package portfolio
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/grokify/structured-tasks/tasks"
+)
+
+// IDSeparator separates the project key from a task or area ID in
+// qualified IDs, e.g. "api#auth-tokens". It matches the separator used for
+// cross-project references in dependsOn.
+const IDSeparator = tasks.RefSeparator
+
+// Manifest lists the task list files that make up a portfolio.
+type Manifest struct {
+	Name     string            `json:"name,omitempty"`
+	Projects []ManifestProject `json:"projects"`
+}
+
+// ManifestProject is a project entry in a manifest.
+type ManifestProject struct {
+	Key  string `json:"key,omitempty"` // ID prefix; defaults to a slug of the project name
+	Path string `json:"path"`          // Task list file, relative to the manifest
+}
+
+// Project is a task list loaded into a portfolio.
+type Project struct {
+	Key   string          // Unique ID prefix for the project
+	Path  string          // File the task list was loaded from
+	Tasks *tasks.TaskList // The project's task list, with unqualified IDs
+}
+
+// Name returns the display name of the project.
+func (p Project) Name() string {
+	if p.Tasks.Project != "" {
+		return p.Tasks.Project
+	}
+	return p.Key
+}
+
+// Stats returns the project's task statistics.
+func (p Project) Stats() tasks.Stats {
+	return p.Tasks.Stats()
+}
+
+// Portfolio is a set of projects.
+type Portfolio struct {
+	Name     string
+	Projects []Project
+}
+
+// ReadManifest reads a manifest file.
+func ReadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", tasks.ErrReadFile, err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", tasks.ErrParseJSON, path, err)
+	}
+	return &m, nil
+}
+
+// LoadManifest loads every project listed in a manifest file.
+func LoadManifest(path string) (*Portfolio, error) {
+	m, err := ReadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(path)
+	p := &Portfolio{Name: m.Name}
+	for i, mp := range m.Projects {
+		if mp.Path == "" {
+			return nil, fmt.Errorf("%w: %s: projects[%d].path", tasks.ErrMissingRequiredField, path, i)
+		}
+		file := mp.Path
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, filepath.FromSlash(file))
+		}
+		if err := p.AddFile(file, mp.Key); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Load loads a portfolio from task list files. Project keys are derived
+// from each task list's project name.
+func Load(paths ...string) (*Portfolio, error) {
+	p := &Portfolio{}
+	for _, path := range paths {
+		if err := p.AddFile(path, ""); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// AddFile parses a task list file and adds it to the portfolio. If key is
+// empty, it is derived from the project name or, failing that, the file's
+// directory name.
+func (p *Portfolio) AddFile(path, key string) error {
+	tl, err := tasks.ParseFile(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if key == "" {
+		key = Slug(tl.Project)
+	}
+	if key == "" {
+		key = Slug(filepath.Base(filepath.Dir(path)))
+	}
+	return p.Add(Project{Key: key, Path: path, Tasks: tl})
+}
+
+// Add adds a project to the portfolio. Project keys must be unique and
+// must not contain the ID separator.
+func (p *Portfolio) Add(project Project) error {
+	if project.Key == "" {
+		return fmt.Errorf("%w: project key", tasks.ErrMissingRequiredField)
+	}
+	if strings.Contains(project.Key, IDSeparator) {
+		return fmt.Errorf("%w: project key must not contain %q: %s", tasks.ErrInvalidFormat, IDSeparator, project.Key)
+	}
+	if _, ok := p.Project(project.Key); ok {
+		return fmt.Errorf("%w: project key: %s", tasks.ErrDuplicateID, project.Key)
+	}
+	p.Projects = append(p.Projects, project)
+	return nil
+}
+
+// Project returns the project with the given key.
+func (p *Portfolio) Project(key string) (Project, bool) {
+	for _, project := range p.Projects {
+		if project.Key == key {
+			return project, true
+		}
+	}
+	return Project{}, false
+}
+
+// QualifyID prefixes an ID with a project key.
+func QualifyID(key, id string) string {
+	return key + IDSeparator + id
+}
+
+// SplitID splits a qualified ID into its project key and local ID. ok is
+// false if the ID is not qualified.
+func SplitID(id string) (key, local string, ok bool) {
+	ref, ok := tasks.ParseRef(id)
+	if !ok {
+		return "", id, false
+	}
+	return ref.Project, ref.ID, true
+}
+
+// ResolveProject returns the task list of the project with the given key.
+// It implements tasks.ProjectResolver.
+func (p *Portfolio) ResolveProject(key string) (*tasks.TaskList, error) {
+	project, ok := p.Project(key)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown project: %s", tasks.ErrUnresolvedReference, key)
+	}
+	return project.Tasks, nil
+}
+
+// Combined returns a single task list holding every project's areas,
+// milestones, external dependencies and tasks, with IDs and references
+// prefixed by the project key. Area names are prefixed with the project
+// name, and custom statuses and labels are merged, the first project to
+// declare one winning. Phase numbers are only meaningful within a project,
+// so combined tasks have no phase. The combined project is named after the
+// portfolio, or "Portfolio" if it has no name.
+func (p *Portfolio) Combined() *tasks.TaskList {
+	combined := &tasks.TaskList{
+		IRVersion: "1.0",
+		Project:   p.Name,
+	}
+	if combined.Project == "" {
+		combined.Project = "Portfolio"
+	}
+	declared := make(map[tasks.Status]bool)
+	declaredLabels := make(map[string]bool)
+	for _, project := range p.Projects {
+		for _, status := range project.Tasks.Statuses {
+			if !declared[status.ID] {
+				declared[status.ID] = true
+				combined.Statuses = append(combined.Statuses, status)
+			}
+		}
+		for _, label := range project.Tasks.Labels {
+			if !declaredLabels[label.ID] {
+				declaredLabels[label.ID] = true
+				combined.Labels = append(combined.Labels, label)
+			}
+		}
+		for _, area := range project.Tasks.Areas {
+			combined.Areas = append(combined.Areas, tasks.Area{
+				ID:   QualifyID(project.Key, area.ID),
+				Name: project.Name() + ": " + area.Name,
+			})
+		}
+		for _, m := range project.Tasks.Milestones {
+			m.Version = QualifyID(project.Key, m.Version)
+			combined.Milestones = append(combined.Milestones, m)
+		}
+		if deps := project.Tasks.Dependencies; deps != nil && len(deps.External) > 0 {
+			if combined.Dependencies == nil {
+				combined.Dependencies = &tasks.Dependencies{}
+			}
+			for _, ext := range deps.External {
+				ext.Name = QualifyID(project.Key, ext.Name)
+				combined.Dependencies.External = append(combined.Dependencies.External, ext)
+			}
+		}
+		for _, task := range project.Tasks.Tasks {
+			task.ID = QualifyID(project.Key, task.ID)
+			if task.Area != "" {
+				task.Area = QualifyID(project.Key, task.Area)
+			}
+			if task.Milestone != "" {
+				task.Milestone = QualifyID(project.Key, task.Milestone)
+			}
+			if task.BlockedBy != "" {
+				task.BlockedBy = qualifyRefs(project.Key, []string{task.BlockedBy})[0]
+			}
+			task.Phase = 0
+			task.DependsOn = qualifyRefs(project.Key, task.DependsOn)
+			task.Blocks = qualifyRefs(project.Key, task.Blocks)
+			task.WaitsOn = qualifyRefs(project.Key, task.WaitsOn)
+			combined.Tasks = append(combined.Tasks, task)
+		}
+	}
+	return combined
+}
+
+// qualifyRefs prefixes local task and external dependency references;
+// already qualified references are kept as is.
+func qualifyRefs(key string, refs []string) []string {
+	if refs == nil {
+		return nil
+	}
+	out := make([]string, len(refs))
+	for i, ref := range refs {
+		if _, _, ok := SplitID(ref); ok {
+			out[i] = ref
+		} else {
+			out[i] = QualifyID(key, ref)
+		}
+	}
+	return out
+}
+
+// Stats holds cross-project statistics.
+type Stats struct {
+	Projects int
+	tasks.Stats
+	ByProject map[string]tasks.Stats
+}
+
+// Stats returns statistics across all projects.
+func (p *Portfolio) Stats() Stats {
+	stats := Stats{
+		Projects:  len(p.Projects),
+		Stats:     p.Combined().Stats(),
+		ByProject: make(map[string]tasks.Stats, len(p.Projects)),
+	}
+	for _, project := range p.Projects {
+		stats.ByProject[project.Key] = project.Stats()
+	}
+	return stats
+}
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slug converts a project name into a project key, e.g. "My API" to "my-api".
+func Slug(name string) string {
+	return strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
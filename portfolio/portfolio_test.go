@@ -0,0 +1,340 @@
+package portfolio
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-tasks/tasks"
+)
+
+const apiTasks = `{
+  "irVersion": "1.0",
+  "project": "API Server",
+  "areas": [{"id": "core", "name": "Core"}],
+  "tasks": [
+    {"id": "auth", "title": "Auth tokens", "status": "completed", "area": "core", "type": "Added"},
+    {"id": "rate", "title": "Rate limiting", "status": "inProgress", "phase": 1, "area": "core", "type": "Added", "dependsOn": ["auth"]},
+    {"id": "docs", "title": "API docs", "status": "planned", "type": "Documentation"}
+  ]
+}`
+
+const webTasks = `{
+  "irVersion": "1.0",
+  "project": "Web App",
+  "tasks": [
+    {"id": "login", "title": "Login page", "status": "planned", "phase": 1, "dependsOn": ["api-server#auth"]},
+    {"id": "auth", "title": "Auth flow <v2>", "status": "completed", "type": "Added"}
+  ]
+}`
+
+func writePortfolio(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"api/TASKS.json": apiTasks,
+		"web/TASKS.json": webTasks,
+		"portfolio.json": `{"name": "Platform", "projects": [{"path": "api/TASKS.json"}, {"key": "web", "path": "web/TASKS.json"}]}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadManifest(t *testing.T) {
+	dir := writePortfolio(t)
+	p, err := LoadManifest(filepath.Join(dir, "portfolio.json"))
+	if err != nil {
+		t.Fatalf("LoadManifest() error = %v", err)
+	}
+	if p.Name != "Platform" || len(p.Projects) != 2 {
+		t.Fatalf("Unexpected portfolio: %+v", p)
+	}
+	if p.Projects[0].Key != "api-server" || p.Projects[1].Key != "web" {
+		t.Errorf("Keys = %s, %s", p.Projects[0].Key, p.Projects[1].Key)
+	}
+
+	// Duplicate keys are rejected
+	if err := p.AddFile(filepath.Join(dir, "web", "TASKS.json"), "web"); !errors.Is(err, tasks.ErrDuplicateID) {
+		t.Errorf("Expected ErrDuplicateID, got %v", err)
+	}
+	if err := p.Add(Project{Key: "a#b", Tasks: &tasks.TaskList{}}); !errors.Is(err, tasks.ErrInvalidFormat) {
+		t.Errorf("Expected ErrInvalidFormat, got %v", err)
+	}
+}
+
+func TestCombined(t *testing.T) {
+	dir := writePortfolio(t)
+	p, err := Load(filepath.Join(dir, "api", "TASKS.json"), filepath.Join(dir, "web", "TASKS.json"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	combined := p.Combined()
+	if len(combined.Tasks) != 5 || len(combined.Areas) != 1 {
+		t.Fatalf("Unexpected combined list: %+v", combined)
+	}
+	rate := combined.Tasks[1]
+	if rate.ID != "api-server#rate" || rate.Area != "api-server#core" || rate.DependsOn[0] != "api-server#auth" {
+		t.Errorf("Unexpected qualified task: %+v", rate)
+	}
+	if got := combined.Tasks[3].DependsOn[0]; got != "api-server#auth" {
+		t.Errorf("Qualified reference changed: %s", got)
+	}
+	if combined.Areas[0].Name != "API Server: Core" {
+		t.Errorf("Area name = %s", combined.Areas[0].Name)
+	}
+
+	// IDs that collide locally are unique once qualified
+	if result := tasks.Validate(combined); len(result.Errors) != 0 {
+		t.Errorf("Combined list has errors: %v", result.Errors)
+	}
+
+	stats := p.Stats()
+	if stats.Projects != 2 || stats.Total != 5 || stats.CompletedCount() != 2 || stats.ByType["Added"] != 3 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if got := stats.ByProject["api-server"].Progress(); got < 33 || got > 34 {
+		t.Errorf("Progress = %.1f", got)
+	}
+}
+
+func TestCombinedReferences(t *testing.T) {
+	p := &Portfolio{}
+	for _, key := range []string{"api", "web"} {
+		tl := &tasks.TaskList{
+			IRVersion:    "1.0",
+			Project:      key,
+			Phases:       []tasks.Phase{{Number: 1, Name: key + " foundation"}},
+			Milestones:   []tasks.Milestone{{Version: "1.0"}},
+			Dependencies: &tasks.Dependencies{External: []tasks.ExternalDependency{{Name: "stripe"}}},
+			Tasks: []tasks.Task{
+				{ID: "a", Title: "A", Status: tasks.StatusPlanned, Phase: 1, Milestone: "1.0"},
+				{ID: "b", Title: "B", Status: tasks.StatusBlocked, BlockedBy: "a", BlockedReason: "Needs A", WaitsOn: []string{"stripe"}},
+			},
+		}
+		if err := p.Add(Project{Key: key, Tasks: tl}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	combined := p.Combined()
+	if len(combined.Milestones) != 2 || combined.Milestones[1].Version != "web#1.0" {
+		t.Errorf("Expected milestones qualified per project, got %+v", combined.Milestones)
+	}
+	if ext := combined.Dependencies.External; len(ext) != 2 || ext[0].Name != "api#stripe" {
+		t.Errorf("Expected external dependencies qualified per project, got %+v", ext)
+	}
+	a, b := combined.Tasks[2], combined.Tasks[3]
+	if a.Milestone != "web#1.0" || a.Phase != 0 || len(combined.Phases) != 0 {
+		t.Errorf("Expected qualified milestone and no phase, got %+v", a)
+	}
+	if b.BlockedBy != "web#a" || b.WaitsOn[0] != "web#stripe" {
+		t.Errorf("Expected qualified blockedBy and waitsOn, got %+v", b)
+	}
+	if result := tasks.Validate(combined); len(result.Errors) != 0 {
+		t.Errorf("Combined list has errors: %v", result.Errors)
+	}
+}
+
+func TestSplitID(t *testing.T) {
+	for _, tc := range []struct {
+		id, key, local string
+		ok             bool
+	}{
+		{"api#auth", "api", "auth", true},
+		{"auth", "", "auth", false},
+		{"#auth", "", "#auth", false},
+	} {
+		key, local, ok := SplitID(tc.id)
+		if key != tc.key || local != tc.local || ok != tc.ok {
+			t.Errorf("SplitID(%q) = %q, %q, %v", tc.id, key, local, ok)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	dir := writePortfolio(t)
+	p, err := LoadManifest(filepath.Join(dir, "portfolio.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	md := RenderMarkdown(p, DefaultOptions())
+	for _, want := range []string{
+		"# Portfolio\n",
+		"**Portfolio:** Platform",
+		"**Projects:** 2 · **Tasks:** 5 · **Progress:** 40% complete",
+		"| [API Server](#api-server) | 3 | 1 | 0 | 1 | 0 | 1 | 0 | 33% |",
+		"| **Total** | 5 | 1 | 0 | 2 | 0 | 2 | 0 | 40% |",
+		"| API Server | 1 | Rate limiting (`api-server#rate`) | 🚧 | Core |",
+		"| Web App | 1 | Login page (`web#login`) | 📋 | - |",
+		"| Added | 3 |",
+		"## Web App\n",
+		"- 🚧 Rate limiting (`api-server#rate`)",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "Auth tokens") {
+		t.Error("Completed tasks should be hidden from the overview by default")
+	}
+	if md := RenderMarkdown(p, DefaultOptions().WithCompleted(true)); !strings.Contains(md, "Auth tokens") {
+		t.Error("Expected completed tasks with WithCompleted(true)")
+	}
+
+	html, err := RenderHTML(p, DefaultOptions().WithCompleted(true))
+	if err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		`<a href="#api-server">API Server</a>`,
+		`<h2 id="web-app">Web App</h2>`,
+		"Auth flow &lt;v2&gt;",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML missing %q", want)
+		}
+	}
+}
+
+func TestResolver(t *testing.T) {
+	dir := writePortfolio(t)
+	manifest := filepath.Join(dir, "siblings.json")
+	content := `{"projects": [{"path": "api/TASKS.json"}, {"key": "billing", "path": "billing/TASKS.json"}]}`
+	if err := os.WriteFile(manifest, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewResolver(manifest)
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+	if tl, err := r.ResolveProject("api-server"); err != nil || tl.Project != "API Server" {
+		t.Errorf("ResolveProject(api-server) = %v, %v", tl, err)
+	}
+	if _, err := r.ResolveProject("billing"); !errors.Is(err, tasks.ErrReadFile) {
+		t.Errorf("Expected read error for missing project file, got %v", err)
+	}
+	if _, err := r.ResolveProject("unknown"); !errors.Is(err, tasks.ErrUnresolvedReference) {
+		t.Errorf("Expected ErrUnresolvedReference, got %v", err)
+	}
+
+	// Web depends on api-server#auth, which resolves
+	web, err := tasks.ParseFile(filepath.Join(dir, "web", "TASKS.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := tasks.ValidateWithOptions(web, tasks.DefaultValidateOptions().WithResolver(r))
+	if len(result.Errors)+len(result.Warnings) != 0 {
+		t.Errorf("Expected no findings, got %+v", result)
+	}
+
+	// A portfolio resolves its own projects
+	p, err := LoadManifest(filepath.Join(dir, "portfolio.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.ResolveProject("web"); err != nil {
+		t.Errorf("Portfolio.ResolveProject(web) error = %v", err)
+	}
+}
+
+func TestCustomStatuses(t *testing.T) {
+	p := &Portfolio{Name: "Platform"}
+	for _, tl := range []*tasks.TaskList{
+		{Project: "API", Statuses: []tasks.StatusDef{{ID: "wontDo", Emoji: "🚫", Description: "Won't Do", Done: true}},
+			Tasks: []tasks.Task{{ID: "a", Title: "A", Status: "wontDo"}, {ID: "b", Title: "B", Status: tasks.StatusPlanned}}},
+		{Project: "Web", Statuses: []tasks.StatusDef{{ID: "wontDo", Emoji: "❌", Description: "Dropped"}},
+			Tasks: []tasks.Task{{ID: "c", Title: "C", Status: tasks.StatusCompleted}}},
+	} {
+		if err := p.Add(Project{Key: Slug(tl.Project), Tasks: tl}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	combined := p.Combined()
+	if len(combined.Statuses) != 1 || combined.Statuses[0].Emoji != "🚫" {
+		t.Errorf("Expected first declaration of wontDo, got %+v", combined.Statuses)
+	}
+	if got := p.Stats().Progress(); got < 66 || got > 67 {
+		t.Errorf("Progress = %.1f, want done statuses counted", got)
+	}
+
+	md := RenderMarkdown(p, DefaultOptions())
+	for _, want := range []string{"🚫", "| **Total** | 3 | 0 | 0 | 1 | 0 | 1 | 0 | 1 | 67% |"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	// Custom statuses that are neither done nor cancelled count as in progress
+	p = &Portfolio{Name: "Platform"}
+	review := &tasks.TaskList{Project: "API", Statuses: []tasks.StatusDef{{ID: "inReview", Started: true}, {ID: "onHold"}},
+		Tasks: []tasks.Task{{ID: "r", Title: "Review me", Status: "inReview"}, {ID: "w", Title: "Wait", Status: tasks.StatusBlocked},
+			{ID: "h", Title: "Hold", Status: "onHold"}}}
+	if err := p.Add(Project{Key: "api", Tasks: review}); err != nil {
+		t.Fatal(err)
+	}
+	opts := DefaultOptions()
+	opts.ShowProjectSections = true
+	md = RenderMarkdown(p, opts)
+	section := md[strings.Index(md, "## API\n"):]
+	if !strings.Contains(section, "Review me (`api#r`)") || strings.Contains(section, "Wait") || strings.Contains(section, "Hold") {
+		t.Errorf("Expected only the started inReview task listed as in progress:\n%s", md)
+	}
+}
+
+func TestArchivedTasks(t *testing.T) {
+	p := &Portfolio{Name: "Platform"}
+	tl := &tasks.TaskList{Project: "API", Tasks: []tasks.Task{
+		{ID: "a", Title: "Shipped parser", Status: tasks.StatusCompleted, Archived: true},
+		{ID: "b", Title: "Renderer", Status: tasks.StatusCompleted},
+		{ID: "c", Title: "Docs", Status: tasks.StatusPlanned},
+	}}
+	if err := p.Add(Project{Key: "api", Tasks: tl}); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := p.Stats()
+	if stats.Total != 2 || stats.ByProject["api"].Total != 2 || stats.Progress() != 50 {
+		t.Errorf("Expected archived task excluded from stats, got %+v", stats)
+	}
+	md := RenderMarkdown(p, DefaultOptions().WithCompleted(true))
+	if strings.Contains(md, "Shipped parser") || !strings.Contains(md, "**Tasks:** 2 ") {
+		t.Errorf("Expected archived task excluded:\n%s", md)
+	}
+}
+
+func TestRenderEscaping(t *testing.T) {
+	p := &Portfolio{}
+	tl := &tasks.TaskList{Project: "Ops | Infra_Team", Tasks: []tasks.Task{
+		{ID: "a", Title: "Read | write split", Status: tasks.StatusInProgress},
+	}}
+	if err := p.Add(Project{Key: "ops", Tasks: tl}); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := DefaultOptions()
+	opts.ShowProjectSections = true
+	md := RenderMarkdown(p, opts)
+	for _, want := range []string{
+		"| [Ops \\| Infra_Team](#ops-infra-team) | 1 |",
+		"| Ops \\| Infra_Team | - | Read \\| write split (`ops#a`) |",
+		"<a id=\"ops-infra-team\"></a>\n\n## Ops | Infra_Team\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown missing %q:\n%s", want, md)
+		}
+	}
+}
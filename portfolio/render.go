@@ -0,0 +1,406 @@
+package portfolio
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+
+	"github.com/grokify/structured-tasks/tasks"
+)
+
+// Options controls how a portfolio report is rendered.
+type Options struct {
+	// Title is the report heading.
+	Title string
+
+	// ShowCompleted includes completed and cancelled tasks in the overview
+	// table.
+	ShowCompleted bool
+
+	// ShowProjectSections renders a section per project with its progress
+	// and in-progress tasks.
+	ShowProjectSections bool
+}
+
+// DefaultOptions returns sensible default report options.
+func DefaultOptions() Options {
+	return Options{
+		Title:               "Portfolio",
+		ShowCompleted:       false,
+		ShowProjectSections: true,
+	}
+}
+
+// WithTitle sets the report heading.
+func (o Options) WithTitle(title string) Options {
+	o.Title = title
+	return o
+}
+
+// WithCompleted enables or disables completed tasks in the overview table.
+func (o Options) WithCompleted(enabled bool) Options {
+	o.ShowCompleted = enabled
+	return o
+}
+
+// WithProjectSections enables or disables per-project sections.
+func (o Options) WithProjectSections(enabled bool) Options {
+	o.ShowProjectSections = enabled
+	return o
+}
+
+// report is the format-independent content of a portfolio report.
+type report struct {
+	Title    string
+	Name     string
+	Statuses []statusColumn
+	Projects []projectRow
+	Total    projectRow
+	Overview []overviewRow
+	Types    []typeCount
+}
+
+type statusColumn struct {
+	Status      tasks.Status
+	Emoji       string
+	Description string
+}
+
+type projectRow struct {
+	Key        string
+	Name       string
+	Path       string
+	Slug       string
+	Total      int
+	Active     int // Tasks counted towards progress, excluding cancelled
+	Completed  int
+	ByStatus   []int // Counts in Statuses order
+	Progress   string
+	InProgress []overviewRow
+}
+
+type overviewRow struct {
+	Project string
+	Phase   string
+	ID      string
+	Title   string
+	Status  string // Status emoji
+	Area    string
+
+	status tasks.Status
+}
+
+type typeCount struct {
+	Type  string
+	Count int
+}
+
+func buildReport(p *Portfolio, opts Options) report {
+	r := report{Title: opts.Title, Name: p.Name}
+	if r.Title == "" {
+		r.Title = "Portfolio"
+	}
+	statuses := p.Combined().StatusRegistry()
+	for _, status := range statuses.All() {
+		r.Statuses = append(r.Statuses, statusColumn{Status: status.ID, Emoji: status.Emoji, Description: status.Description})
+	}
+
+	stats := p.Stats()
+	r.Total = projectRow{
+		Name:      "Total",
+		Total:     stats.Total,
+		Active:    stats.ProgressTotal(),
+		Completed: stats.Done,
+		Progress:  fmt.Sprintf("%.0f%%", stats.Stats.Progress()),
+	}
+	for _, status := range statuses.Order() {
+		r.Total.ByStatus = append(r.Total.ByStatus, stats.ByStatus[status])
+	}
+
+	for _, project := range p.Projects {
+		ps := stats.ByProject[project.Key]
+		row := projectRow{
+			Key:       project.Key,
+			Name:      project.Name(),
+			Path:      project.Path,
+			Slug:      Slug(project.Name()),
+			Total:     ps.Total,
+			Active:    ps.ProgressTotal(),
+			Completed: ps.Done,
+			Progress:  fmt.Sprintf("%.0f%%", ps.Progress()),
+		}
+		for _, status := range statuses.Order() {
+			row.ByStatus = append(row.ByStatus, ps.ByStatus[status])
+		}
+		rows := overviewRows(project, opts)
+		projectStatuses := project.Tasks.StatusRegistry()
+		for _, or := range rows {
+			if projectStatuses.IsInProgress(or.status) {
+				row.InProgress = append(row.InProgress, or)
+			}
+		}
+		r.Projects = append(r.Projects, row)
+		r.Overview = append(r.Overview, rows...)
+	}
+
+	for t, count := range stats.ByType {
+		r.Types = append(r.Types, typeCount{Type: t, Count: count})
+	}
+	sort.Slice(r.Types, func(i, j int) bool {
+		if r.Types[i].Count != r.Types[j].Count {
+			return r.Types[i].Count > r.Types[j].Count
+		}
+		return r.Types[i].Type < r.Types[j].Type
+	})
+	return r
+}
+
+// overviewRows returns a project's unarchived tasks sorted by phase
+// (unphased last), then status, then title.
+func overviewRows(project Project, opts Options) []overviewRow {
+	tl := project.Tasks
+	areaNames := make(map[string]string)
+	for _, area := range tl.Areas {
+		areaNames[area.ID] = area.Name
+	}
+
+	statuses := tl.StatusRegistry()
+	sorted := make([]tasks.Task, 0, len(tl.Tasks))
+	for _, task := range tl.WithoutArchived().Tasks {
+		if !opts.ShowCompleted && (statuses.IsDone(task.Status) || statuses.IsCancelled(task.Status)) {
+			continue
+		}
+		sorted = append(sorted, task)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		iPhase, jPhase := sortPhase(sorted[i].Phase), sortPhase(sorted[j].Phase)
+		if iPhase != jPhase {
+			return iPhase < jPhase
+		}
+		iOrder, jOrder := statuses.Rank(sorted[i].Status), statuses.Rank(sorted[j].Status)
+		if iOrder != jOrder {
+			return iOrder < jOrder
+		}
+		return sorted[i].Title < sorted[j].Title
+	})
+
+	rows := make([]overviewRow, 0, len(sorted))
+	for _, task := range sorted {
+		row := overviewRow{
+			Project: project.Name(),
+			Phase:   "-",
+			ID:      QualifyID(project.Key, task.ID),
+			Title:   task.Title,
+			Status:  tl.GetStatusEmoji(task.Status),
+			Area:    "-",
+			status:  task.Status,
+		}
+		if p, ok := tl.Phase(task.Phase); ok && p.Name != "" {
+			row.Phase = p.Name
+		} else if task.Phase > 0 {
+			row.Phase = fmt.Sprintf("%d", task.Phase)
+		}
+		if name := areaNames[task.Area]; name != "" {
+			row.Area = name
+		} else if task.Area != "" {
+			row.Area = task.Area
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func sortPhase(phase int) int {
+	if phase == 0 {
+		return 1 << 30
+	}
+	return phase
+}
+
+// escapeCell escapes pipes so text can be used in a Markdown table cell.
+func escapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// RenderMarkdown renders a portfolio report as Markdown.
+func RenderMarkdown(p *Portfolio, opts Options) string {
+	r := buildReport(p, opts)
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# %s\n\n", r.Title)
+	if r.Name != "" {
+		fmt.Fprintf(&sb, "**Portfolio:** %s\n\n", r.Name)
+	}
+	fmt.Fprintf(&sb, "**Projects:** %d · **Tasks:** %d · **Progress:** %s complete\n\n", len(r.Projects), r.Total.Total, r.Total.Progress)
+
+	// Per-project progress
+	sb.WriteString("## Projects\n\n")
+	sb.WriteString("| Project | Tasks |")
+	for _, s := range r.Statuses {
+		fmt.Fprintf(&sb, " %s |", s.Emoji)
+	}
+	sb.WriteString(" Progress |\n|---------|-------|")
+	for range r.Statuses {
+		sb.WriteString("----|")
+	}
+	sb.WriteString("----------|\n")
+	for _, row := range r.Projects {
+		name := escapeCell(row.Name)
+		if opts.ShowProjectSections {
+			name = fmt.Sprintf("[%s](#%s)", name, row.Slug)
+		}
+		writeMarkdownProjectRow(&sb, name, row)
+	}
+	writeMarkdownProjectRow(&sb, "**Total**", r.Total)
+	sb.WriteString("\n")
+
+	// Combined overview
+	sb.WriteString("## Overview\n\n")
+	if len(r.Overview) == 0 {
+		sb.WriteString("No open tasks.\n\n")
+	} else {
+		sb.WriteString("| Project | Phase | Task | Status | Area |\n")
+		sb.WriteString("|---------|-------|------|--------|------|\n")
+		for _, row := range r.Overview {
+			fmt.Fprintf(&sb, "| %s | %s | %s (`%s`) | %s | %s |\n", escapeCell(row.Project), escapeCell(row.Phase), escapeCell(row.Title),
+				escapeCell(row.ID), row.Status, escapeCell(row.Area))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Cross-project stats
+	sb.WriteString("## Statistics\n\n")
+	sb.WriteString("| Status | Tasks |\n|--------|-------|\n")
+	for i, s := range r.Statuses {
+		fmt.Fprintf(&sb, "| %s %s | %d |\n", s.Emoji, escapeCell(s.Description), r.Total.ByStatus[i])
+	}
+	sb.WriteString("\n")
+	if len(r.Types) > 0 {
+		sb.WriteString("| Type | Tasks |\n|------|-------|\n")
+		for _, t := range r.Types {
+			fmt.Fprintf(&sb, "| %s | %d |\n", escapeCell(t.Type), t.Count)
+		}
+		sb.WriteString("\n")
+	}
+
+	if opts.ShowProjectSections {
+		for _, row := range r.Projects {
+			// Explicit anchor, since heading anchors vary between Markdown renderers
+			fmt.Fprintf(&sb, "<a id=\"%s\"></a>\n\n## %s\n\n", row.Slug, row.Name)
+			if row.Path != "" {
+				fmt.Fprintf(&sb, "**Source:** `%s`\n\n", row.Path)
+			}
+			fmt.Fprintf(&sb, "**Progress:** %d/%d tasks complete (%s)\n\n", row.Completed, row.Active, row.Progress)
+			for _, task := range row.InProgress {
+				fmt.Fprintf(&sb, "- %s %s (`%s`)\n", task.Status, task.Title, task.ID)
+			}
+			if len(row.InProgress) > 0 {
+				sb.WriteString("\n")
+			}
+		}
+	}
+
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+func writeMarkdownProjectRow(sb *strings.Builder, name string, row projectRow) {
+	fmt.Fprintf(sb, "| %s | %d |", name, row.Total)
+	for _, count := range row.ByStatus {
+		fmt.Fprintf(sb, " %d |", count)
+	}
+	fmt.Fprintf(sb, " %s |\n", row.Progress)
+}
+
+var htmlTemplate = template.Must(template.New("portfolio").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Report.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem; color: #24292f; }
+table { border-collapse: collapse; margin-bottom: 1.5rem; }
+th, td { border: 1px solid #d0d7de; padding: 0.3rem 0.6rem; text-align: left; }
+th { background: #f6f8fa; }
+tr.total td { font-weight: bold; }
+progress { width: 8rem; }
+code { font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>{{.Report.Title}}</h1>
+{{- if .Report.Name}}
+<p><strong>Portfolio:</strong> {{.Report.Name}}</p>
+{{- end}}
+<p><strong>Projects:</strong> {{len .Report.Projects}} · <strong>Tasks:</strong> {{.Report.Total.Total}} · <strong>Progress:</strong> {{.Report.Total.Progress}} complete</p>
+
+<h2>Projects</h2>
+<table>
+<tr><th>Project</th><th>Tasks</th>{{range .Report.Statuses}}<th title="{{.Description}}">{{.Emoji}}</th>{{end}}<th>Progress</th></tr>
+{{- range .Report.Projects}}
+<tr><td>{{if $.Options.ShowProjectSections}}<a href="#{{.Slug}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}</td><td>{{.Total}}</td>{{range .ByStatus}}<td>{{.}}</td>{{end}}<td><progress max="{{.Active}}" value="{{.Completed}}"></progress> {{.Progress}}</td></tr>
+{{- end}}
+<tr class="total"><td>Total</td><td>{{.Report.Total.Total}}</td>{{range .Report.Total.ByStatus}}<td>{{.}}</td>{{end}}<td>{{.Report.Total.Progress}}</td></tr>
+</table>
+
+<h2>Overview</h2>
+{{- if .Report.Overview}}
+<table>
+<tr><th>Project</th><th>Phase</th><th>Task</th><th>Status</th><th>Area</th></tr>
+{{- range .Report.Overview}}
+<tr><td>{{.Project}}</td><td>{{.Phase}}</td><td>{{.Title}} <code>{{.ID}}</code></td><td>{{.Status}}</td><td>{{.Area}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No open tasks.</p>
+{{- end}}
+
+<h2>Statistics</h2>
+<table>
+<tr><th>Status</th><th>Tasks</th></tr>
+{{- range $i, $s := .Report.Statuses}}
+<tr><td>{{$s.Emoji}} {{$s.Description}}</td><td>{{index $.Report.Total.ByStatus $i}}</td></tr>
+{{- end}}
+</table>
+{{- if .Report.Types}}
+<table>
+<tr><th>Type</th><th>Tasks</th></tr>
+{{- range .Report.Types}}
+<tr><td>{{.Type}}</td><td>{{.Count}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Options.ShowProjectSections}}
+{{- range .Report.Projects}}
+
+<h2 id="{{.Slug}}">{{.Name}}</h2>
+{{- if .Path}}
+<p><strong>Source:</strong> <code>{{.Path}}</code></p>
+{{- end}}
+<p><strong>Progress:</strong> {{.Completed}}/{{.Active}} tasks complete ({{.Progress}})</p>
+{{- if .InProgress}}
+<ul>
+{{- range .InProgress}}
+<li>{{.Status}} {{.Title}} <code>{{.ID}}</code></li>
+{{- end}}
+</ul>
+{{- end}}
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// RenderHTML renders a portfolio report as a standalone HTML document.
+func RenderHTML(p *Portfolio, opts Options) (string, error) {
+	var buf bytes.Buffer
+	data := struct {
+		Report  report
+		Options Options
+	}{buildReport(p, opts), opts}
+	if err := htmlTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
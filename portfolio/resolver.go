@@ -0,0 +1,66 @@
+package portfolio
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/grokify/structured-tasks/tasks"
+)
+
+// Resolver resolves cross-project references through a manifest of sibling
+// task lists. Unlike LoadManifest, it tolerates projects that cannot be
+// loaded: references to them are reported as unavailable instead of
+// failing. It implements tasks.ProjectResolver.
+type Resolver struct {
+	projects map[string]*tasks.TaskList
+	errs     map[string]error
+}
+
+// NewResolver reads a manifest and loads the projects it lists. Keys default
+// as in LoadManifest; if a project without an explicit key fails to load,
+// the key is derived from its directory name.
+func NewResolver(manifestPath string) (*Resolver, error) {
+	m, err := ReadManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	r := &Resolver{
+		projects: make(map[string]*tasks.TaskList),
+		errs:     make(map[string]error),
+	}
+	dir := filepath.Dir(manifestPath)
+	for _, mp := range m.Projects {
+		if mp.Path == "" {
+			continue
+		}
+		file := mp.Path
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, filepath.FromSlash(file))
+		}
+		key := mp.Key
+		tl, err := tasks.ParseFile(file)
+		if key == "" && err == nil {
+			key = Slug(tl.Project)
+		}
+		if key == "" {
+			key = Slug(filepath.Base(filepath.Dir(file)))
+		}
+		if err != nil {
+			r.errs[key] = fmt.Errorf("%s: %w", file, err)
+			continue
+		}
+		r.projects[key] = tl
+	}
+	return r, nil
+}
+
+// ResolveProject returns the task list of the project with the given key.
+func (r *Resolver) ResolveProject(key string) (*tasks.TaskList, error) {
+	if tl, ok := r.projects[key]; ok {
+		return tl, nil
+	}
+	if err, ok := r.errs[key]; ok {
+		return nil, err
+	}
+	return nil, fmt.Errorf("%w: project not in manifest: %s", tasks.ErrUnresolvedReference, key)
+}
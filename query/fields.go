@@ -0,0 +1,95 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/grokify/structured-tasks/tasks"
+)
+
+// kind determines how a field's values are compared.
+type kind int
+
+const (
+	kindText     kind = iota // Case-insensitive text
+	kindNumber               // Integers; 0 is unset
+	kindPriority             // Priorities; more urgent is greater
+	kindDate                 // YYYY-MM-DD dates
+	kindBool                 // true or false
+)
+
+// field is a task field that expressions can refer to. List fields return
+// one value per element; unset fields return no values.
+type field struct {
+	name string
+	kind kind
+	list bool
+	get  func(tasks.Task) []string
+}
+
+var fields = []field{
+	{"id", kindText, false, text(func(t tasks.Task) string { return t.ID })},
+	{"title", kindText, false, text(func(t tasks.Task) string { return t.Title })},
+	{"description", kindText, false, text(func(t tasks.Task) string { return t.Description })},
+	{"status", kindText, false, text(func(t tasks.Task) string { return string(t.Status) })},
+	{"priority", kindPriority, false, text(func(t tasks.Task) string { return string(t.Priority) })},
+	{"dueDate", kindDate, false, text(func(t tasks.Task) string { return t.DueDate })},
+	{"phase", kindNumber, false, number(func(t tasks.Task) int { return t.Phase })},
+	{"area", kindText, false, text(func(t tasks.Task) string { return t.Area })},
+	{"type", kindText, false, text(func(t tasks.Task) string { return t.Type })},
+	{"labels", kindText, true, func(t tasks.Task) []string { return t.Labels }},
+	{"version", kindText, false, text(func(t tasks.Task) string { return t.Version })},
+	{"milestone", kindText, false, text(func(t tasks.Task) string { return t.Milestone })},
+	{"dependsOn", kindText, true, func(t tasks.Task) []string { return t.DependsOn }},
+	{"blocks", kindText, true, func(t tasks.Task) []string { return t.Blocks }},
+	{"waitsOn", kindText, true, func(t tasks.Task) []string { return t.WaitsOn }},
+	{"blockedBy", kindText, false, text(func(t tasks.Task) string { return t.BlockedBy })},
+	{"archived", kindBool, false, func(t tasks.Task) []string { return []string{strconv.FormatBool(t.Archived)} }},
+}
+
+// aliases are alternative field names.
+var aliases = map[string]string{
+	"label": "labels",
+}
+
+// lookupField returns the field with the given name, ignoring case.
+func lookupField(name string) (*field, bool) {
+	name = strings.ToLower(name)
+	if alias, ok := aliases[name]; ok {
+		name = alias
+	}
+	for i := range fields {
+		if strings.ToLower(fields[i].name) == name {
+			return &fields[i], true
+		}
+	}
+	return nil, false
+}
+
+// Fields returns the names of the fields filters and projections can refer
+// to.
+func Fields() []string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.name
+	}
+	return names
+}
+
+func text(get func(tasks.Task) string) func(tasks.Task) []string {
+	return func(t tasks.Task) []string {
+		if v := get(t); v != "" {
+			return []string{v}
+		}
+		return nil
+	}
+}
+
+func number(get func(tasks.Task) int) func(tasks.Task) []string {
+	return func(t tasks.Task) []string {
+		if v := get(t); v != 0 {
+			return []string{strconv.Itoa(v)}
+		}
+		return nil
+	}
+}
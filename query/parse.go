@@ -0,0 +1,274 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp // = == != < <= > >= ~
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	col  int // 1-based column of the first character
+}
+
+// isWordRune returns true for characters that can appear in unquoted values
+// such as IDs, dates and versions.
+func isWordRune(r rune) bool {
+	return !unicode.IsSpace(r) && !strings.ContainsRune(`()!,=<>&|~"'`, r)
+}
+
+func lex(expr string) ([]token, error) {
+	var toks []token
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		col := i + 1
+		two := ""
+		if i+1 < len(runes) {
+			two = string(runes[i : i+2])
+		}
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case two == "&&":
+			toks = append(toks, token{tokAnd, two, col})
+			i += 2
+		case two == "||":
+			toks = append(toks, token{tokOr, two, col})
+			i += 2
+		case two == "==" || two == "!=" || two == "<=" || two == ">=":
+			toks = append(toks, token{tokOp, two, col})
+			i += 2
+		case r == '=' || r == '<' || r == '>' || r == '~':
+			toks = append(toks, token{tokOp, string(r), col})
+			i++
+		case r == '!':
+			toks = append(toks, token{tokNot, "!", col})
+			i++
+		case r == '(':
+			toks = append(toks, token{tokLParen, "(", col})
+			i++
+		case r == ')':
+			toks = append(toks, token{tokRParen, ")", col})
+			i++
+		case r == ',':
+			toks = append(toks, token{tokComma, ",", col})
+			i++
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(runes) && runes[j] != r {
+				j++
+			}
+			if j == len(runes) {
+				return nil, syntaxError(col, "unterminated string")
+			}
+			toks = append(toks, token{tokString, string(runes[i+1 : j]), col})
+			i = j + 1
+		case isWordRune(r):
+			j := i
+			for j < len(runes) && isWordRune(runes[j]) {
+				j++
+			}
+			toks = append(toks, token{tokWord, string(runes[i:j]), col})
+			i = j
+		default:
+			return nil, syntaxError(col, fmt.Sprintf("unexpected %q", r))
+		}
+	}
+	return append(toks, token{tokEOF, "", len(runes) + 1}), nil
+}
+
+func syntaxError(col int, msg string) error {
+	return fmt.Errorf("%w at column %d: %s", ErrInvalidFilter, col, msg)
+}
+
+// parser is a recursive descent parser for:
+//
+//	or         = and { ("||" | "or") and }
+//	and        = unary { ("&&" | "and") unary }
+//	unary      = ("!" | "not") unary | "(" or ")" | comparison
+//	comparison = field op value | field ["not"] "in" "(" value { "," value } ")"
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.toks[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// isKeyword returns true if tok is the unquoted keyword kw, ignoring case.
+func isKeyword(tok token, kw string) bool {
+	return tok.kind == tokWord && strings.EqualFold(tok.text, kw)
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); tok.kind == tokOr || isKeyword(tok, "or"); tok = p.peek() {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); tok.kind == tokAnd || isKeyword(tok, "and"); tok = p.peek() {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	tok := p.peek()
+	switch {
+	case tok.kind == tokNot || isKeyword(tok, "not"):
+		p.next()
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{n}, nil
+	case tok.kind == tokLParen:
+		p.next()
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != tokRParen {
+			return nil, syntaxError(tok.col, fmt.Sprintf("expected ) but found %s", describe(tok)))
+		}
+		return n, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	tok := p.next()
+	if tok.kind != tokWord {
+		return nil, syntaxError(tok.col, fmt.Sprintf("expected a field but found %s", describe(tok)))
+	}
+	f, ok := lookupField(tok.text)
+	if !ok {
+		return nil, syntaxError(tok.col, fmt.Sprintf("unknown field %q (expected one of %s)", tok.text, strings.Join(Fields(), ", ")))
+	}
+
+	opTok := p.next()
+	var op string
+	switch {
+	case opTok.kind == tokOp:
+		op = opTok.text
+		if op == "==" {
+			op = "="
+		}
+	case isKeyword(opTok, "in"):
+		op = "in"
+	case isKeyword(opTok, "not") && isKeyword(p.peek(), "in"):
+		p.next()
+		op = "not in"
+	default:
+		return nil, syntaxError(opTok.col, fmt.Sprintf("expected an operator after %s but found %s", tok.text, describe(opTok)))
+	}
+	if err := f.checkOp(op); err != nil {
+		return nil, syntaxError(opTok.col, err.Error())
+	}
+
+	var values []string
+	if op == "in" || op == "not in" {
+		list, err := p.parseList(f, op)
+		if err != nil {
+			return nil, err
+		}
+		values = list
+	} else {
+		v, err := p.parseValue(f, op)
+		if err != nil {
+			return nil, err
+		}
+		values = []string{v}
+	}
+	return comparison{field: f, op: op, values: values}, nil
+}
+
+func (p *parser) parseList(f *field, op string) ([]string, error) {
+	if tok := p.next(); tok.kind != tokLParen {
+		return nil, syntaxError(tok.col, fmt.Sprintf("expected ( after %s but found %s", op, describe(tok)))
+	}
+	var values []string
+	for {
+		v, err := p.parseValue(f, op)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		switch tok := p.next(); tok.kind {
+		case tokComma:
+		case tokRParen:
+			return values, nil
+		default:
+			return nil, syntaxError(tok.col, fmt.Sprintf("expected , or ) but found %s", describe(tok)))
+		}
+	}
+}
+
+func (p *parser) parseValue(f *field, op string) (string, error) {
+	tok := p.next()
+	if tok.kind != tokWord && tok.kind != tokString {
+		return "", syntaxError(tok.col, fmt.Sprintf("expected a value but found %s", describe(tok)))
+	}
+	v, err := f.literal(op, tok.text)
+	if err != nil {
+		return "", syntaxError(tok.col, err.Error())
+	}
+	return v, nil
+}
+
+// describe names a token in error messages.
+func describe(tok token) string {
+	switch tok.kind {
+	case tokEOF:
+		return "end of filter"
+	case tokString:
+		return fmt.Sprintf("%q", tok.text)
+	}
+	return tok.text
+}
@@ -0,0 +1,128 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/grokify/structured-tasks/tasks"
+)
+
+// DefaultFields are the fields projected when none are given.
+var DefaultFields = []string{"id", "title", "status", "area", "phase"}
+
+// Projection selects which task fields to output, in order.
+type Projection struct {
+	fields []*field
+}
+
+// NewProjection returns a projection of the named fields (see Fields),
+// ignoring case. An empty list projects DefaultFields.
+func NewProjection(names []string) (Projection, error) {
+	if len(names) == 0 {
+		names = DefaultFields
+	}
+	var p Projection
+	for _, name := range names {
+		f, ok := lookupField(strings.TrimSpace(name))
+		if !ok {
+			return Projection{}, fmt.Errorf("%w: %q (expected one of %s)", ErrUnknownField, name, strings.Join(Fields(), ", "))
+		}
+		p.fields = append(p.fields, f)
+	}
+	return p, nil
+}
+
+// Fields returns the names of the projected fields.
+func (p Projection) Fields() []string {
+	names := make([]string, len(p.fields))
+	for i, f := range p.fields {
+		names[i] = f.name
+	}
+	return names
+}
+
+// Strings returns the projected field values of a task as text, such as
+// for tables and CSV. List values are joined with commas; unset values are
+// empty.
+func (p Projection) Strings(task tasks.Task) []string {
+	values := make([]string, len(p.fields))
+	for i, f := range p.fields {
+		values[i] = strings.Join(f.get(task), ",")
+	}
+	return values
+}
+
+// Record returns the projected field values of a task with their JSON
+// types: strings, numbers, booleans and string arrays. Unset numbers, such
+// as the phase of an unphased task, are nil and omitted from JSON.
+func (p Projection) Record(task tasks.Task) Record {
+	r := Record{Fields: p.Fields(), Values: make([]any, len(p.fields))}
+	for i, f := range p.fields {
+		r.Values[i] = f.value(task)
+	}
+	return r
+}
+
+// Record is a projected task. It marshals to a JSON object with its fields
+// in projection order, leaving out nil values.
+type Record struct {
+	Fields []string
+	Values []any
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r Record) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, name := range r.Fields {
+		if r.Values[i] == nil {
+			continue
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(r.Values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// value returns the field value of a task with its JSON type. Unset list
+// fields are empty arrays and unset numbers are nil.
+func (f *field) value(task tasks.Task) any {
+	values := f.get(task)
+	if f.list {
+		if values == nil {
+			return []string{}
+		}
+		return values
+	}
+	v := ""
+	if len(values) > 0 {
+		v = values[0]
+	}
+	switch f.kind {
+	case kindNumber:
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil
+		}
+		return n
+	case kindBool:
+		return v == "true"
+	}
+	return v
+}
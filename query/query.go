@@ -0,0 +1,237 @@
+// Package query provides a small filter expression language over tasks,
+// used to render different views of the same task list, for example:
+//
+//	status != completed && area in (api, core) && phase <= 2
+//
+// A comparison is a field name (see Fields), an operator and a value:
+//
+//	=, ==, !=        equal, not equal
+//	<, <=, >, >=     ordering, for phase, priority and dueDate
+//	~                contains, for text fields
+//	in, not in       one of a parenthesized, comma-separated list
+//
+// Comparisons combine with && (and), || (or), ! (not) and parentheses.
+// Values are unquoted words or "quoted strings"; text comparisons ignore
+// case. Priorities order by urgency, so priority >= high selects critical
+// and high tasks. An empty value ("") tests whether a field is unset, and
+// ordering comparisons never match unset fields. On list fields such as
+// labels and dependsOn, a comparison matches if any element matches, and
+// != and not in match if no element does.
+//
+// A Projection selects the fields of matching tasks to output, as text or
+// as JSON records.
+package query
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grokify/structured-tasks/tasks"
+)
+
+// Sentinel errors for filters and projections.
+var (
+	// ErrInvalidFilter indicates a filter expression that cannot be parsed.
+	ErrInvalidFilter = errors.New("invalid filter")
+
+	// ErrUnknownField indicates a projected field that does not exist.
+	ErrUnknownField = errors.New("unknown field")
+)
+
+// Filter is a parsed filter expression.
+type Filter struct {
+	expr string
+	root node
+}
+
+// Parse parses a filter expression.
+func Parse(expr string) (*Filter, error) {
+	toks, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+	if toks[0].kind == tokEOF {
+		return nil, fmt.Errorf("%w: empty expression", ErrInvalidFilter)
+	}
+	p := &parser{toks: toks}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, syntaxError(tok.col, fmt.Sprintf("unexpected %s", describe(tok)))
+	}
+	return &Filter{expr: expr, root: root}, nil
+}
+
+// MustParse is like Parse but panics if the expression is invalid.
+func MustParse(expr string) *Filter {
+	f, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// Match returns true if the task satisfies the filter. A nil filter
+// matches every task.
+func (f *Filter) Match(task tasks.Task) bool {
+	return f == nil || f.root.match(task)
+}
+
+// String returns the source expression of the filter.
+func (f *Filter) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.expr
+}
+
+type node interface {
+	match(task tasks.Task) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) match(task tasks.Task) bool { return n.left.match(task) && n.right.match(task) }
+
+type orNode struct{ left, right node }
+
+func (n orNode) match(task tasks.Task) bool { return n.left.match(task) || n.right.match(task) }
+
+type notNode struct{ operand node }
+
+func (n notNode) match(task tasks.Task) bool { return !n.operand.match(task) }
+
+// comparison compares a field against one value, or a list of values for
+// in and not in.
+type comparison struct {
+	field  *field
+	op     string
+	values []string
+}
+
+func (c comparison) match(task tasks.Task) bool {
+	values := c.field.get(task)
+	if len(values) == 0 {
+		values = []string{""}
+	}
+	switch c.op {
+	case "!=":
+		return !c.any(values, "=")
+	case "not in":
+		return !c.any(values, "in")
+	}
+	return c.any(values, c.op)
+}
+
+func (c comparison) any(values []string, op string) bool {
+	for _, v := range values {
+		if c.test(op, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c comparison) test(op, v string) bool {
+	switch op {
+	case "=":
+		return strings.EqualFold(v, c.values[0])
+	case "in":
+		for _, want := range c.values {
+			if strings.EqualFold(v, want) {
+				return true
+			}
+		}
+		return false
+	case "~":
+		return strings.Contains(strings.ToLower(v), strings.ToLower(c.values[0]))
+	}
+	if v == "" {
+		return false
+	}
+	n := c.field.compare(v, c.values[0])
+	switch op {
+	case "<":
+		return n < 0
+	case "<=":
+		return n <= 0
+	case ">":
+		return n > 0
+	default: // ">="
+		return n >= 0
+	}
+}
+
+func isOrdering(op string) bool {
+	return op == "<" || op == "<=" || op == ">" || op == ">="
+}
+
+// checkOp returns an error if the operator does not apply to the field.
+func (f *field) checkOp(op string) error {
+	switch {
+	case isOrdering(op) && f.kind != kindNumber && f.kind != kindPriority && f.kind != kindDate:
+		return fmt.Errorf("operator %s is not supported for %s", op, f.name)
+	case op == "~" && f.kind != kindText:
+		return fmt.Errorf("operator ~ is not supported for %s", f.name)
+	}
+	return nil
+}
+
+// literal checks and normalizes a value compared against the field.
+func (f *field) literal(op, v string) (string, error) {
+	if v == "" {
+		if isOrdering(op) || f.kind == kindBool {
+			return "", fmt.Errorf("%s %s needs a value", f.name, op)
+		}
+		return "", nil
+	}
+	switch f.kind {
+	case kindNumber:
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return "", fmt.Errorf("%s expects a number, got %q", f.name, v)
+		}
+		if n == 0 {
+			// 0 is how the IR leaves a number unset
+			return "", fmt.Errorf(`%s 0 means no %s; use %s = "" to select tasks without one`, f.name, f.name, f.name)
+		}
+		return strconv.Itoa(n), nil
+	case kindPriority:
+		p := tasks.Priority(strings.ToLower(v))
+		if !p.IsValid() {
+			return "", fmt.Errorf("invalid priority %q (expected critical, high, medium or low)", v)
+		}
+		return string(p), nil
+	case kindDate:
+		if _, err := time.Parse("2006-01-02", v); err != nil {
+			return "", fmt.Errorf("%s expects a date (YYYY-MM-DD), got %q", f.name, v)
+		}
+	case kindBool:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return "", fmt.Errorf("%s expects true or false, got %q", f.name, v)
+		}
+		return strconv.FormatBool(b), nil
+	}
+	return v, nil
+}
+
+// compare orders a task value against a literal of the field's kind.
+func (f *field) compare(v, literal string) int {
+	switch f.kind {
+	case kindNumber:
+		a, _ := strconv.Atoi(v)
+		b, _ := strconv.Atoi(literal)
+		return cmp.Compare(a, b)
+	case kindPriority:
+		// Lower ranks are more urgent and compare greater.
+		return cmp.Compare(tasks.Priority(literal).Rank(), tasks.Priority(strings.ToLower(v)).Rank())
+	}
+	return strings.Compare(v, literal)
+}
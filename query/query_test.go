@@ -0,0 +1,138 @@
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-tasks/tasks"
+)
+
+var testTasks = []tasks.Task{
+	{ID: "auth", Title: "Auth hardening", Status: tasks.StatusInProgress, Area: "api", Phase: 1, Priority: tasks.PriorityCritical, Labels: []string{"security"}, DueDate: "2026-03-01"},
+	{ID: "cache", Title: "Response cache", Status: tasks.StatusPlanned, Area: "core", Phase: 2, Priority: tasks.PriorityMedium, Labels: []string{"perf", "security"}, DependsOn: []string{"auth"}},
+	{ID: "cli", Title: "CLI polish", Status: tasks.StatusCompleted, Area: "cli", Phase: 1},
+	{ID: "docs", Title: "Docs site", Status: tasks.StatusPlanned, Area: "api", Phase: 3, Priority: tasks.PriorityLow, DueDate: "2026-06-01"},
+}
+
+func matchingIDs(f *Filter) []string {
+	var ids []string
+	for _, task := range testTasks {
+		if f.Match(task) {
+			ids = append(ids, task.ID)
+		}
+	}
+	return ids
+}
+
+func TestFilterMatch(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{`status != completed && area in (api, core) && phase <= 2`, []string{"auth", "cache"}},
+		{`status == planned`, []string{"cache", "docs"}},
+		{`STATUS = Planned`, []string{"cache", "docs"}},
+		{`area not in (api)`, []string{"cache", "cli"}},
+		{`phase > 1 || area = cli`, []string{"cache", "cli", "docs"}},
+		{`!(phase > 1) and not status = completed`, []string{"auth"}},
+		{`priority >= high`, []string{"auth"}},
+		{`priority < critical`, []string{"cache", "docs"}},
+		{`priority = ""`, []string{"cli"}},
+		{`labels = security`, []string{"auth", "cache"}},
+		{`label != security`, []string{"cli", "docs"}},
+		{`labels = ""`, []string{"cli", "docs"}},
+		{`dependsOn in (auth, cli)`, []string{"cache"}},
+		{`title ~ "CACHE"`, []string{"cache"}},
+		{`dueDate < 2026-04-01`, []string{"auth"}},
+		{`dueDate != ""`, []string{"auth", "docs"}},
+		{`archived = false`, []string{"auth", "cache", "cli", "docs"}},
+	}
+	for _, tt := range tests {
+		f, err := Parse(tt.expr)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.expr, err)
+			continue
+		}
+		if got := matchingIDs(f); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) matched %v, want %v", tt.expr, got, tt.want)
+		}
+		if f.String() != tt.expr {
+			t.Errorf("String() = %q, want %q", f.String(), tt.expr)
+		}
+	}
+
+	var none *Filter
+	if !none.Match(testTasks[0]) {
+		t.Error("Expected nil filter to match every task")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`statu = planned`,
+		`status planned`,
+		`status =`,
+		`status = planned &&`,
+		`(status = planned`,
+		`status = planned)`,
+		`area in api`,
+		`area in (api, core`,
+		`phase <= two`,
+		`phase = 0`,
+		`priority = urgent`,
+		`status < planned`,
+		`phase ~ 1`,
+		`dueDate > 2026-13-01`,
+		`dueDate < ""`,
+		`archived = maybe`,
+		`title = "unterminated`,
+	} {
+		if _, err := Parse(expr); !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalidFilter", expr, err)
+		}
+	}
+
+	_, err := Parse(`status = planned && phas <= 2`)
+	if err == nil || !strings.HasPrefix(err.Error(), "invalid filter at column 21: unknown field \"phas\"") {
+		t.Errorf("Expected column in error, got %v", err)
+	}
+}
+
+func TestProjection(t *testing.T) {
+	p, err := NewProjection([]string{"id", " Phase", "label", "archived", "dueDate"})
+	if err != nil {
+		t.Fatalf("NewProjection() error: %v", err)
+	}
+	if got, want := p.Fields(), []string{"id", "phase", "labels", "archived", "dueDate"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields() = %v, want %v", got, want)
+	}
+	if got, want := p.Strings(testTasks[1]), []string{"cache", "2", "perf,security", "false", ""}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Strings() = %q, want %q", got, want)
+	}
+
+	data, err := json.Marshal(p.Record(testTasks[2]))
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	if want := `{"id":"cli","phase":1,"labels":[],"archived":false,"dueDate":""}`; string(data) != want {
+		t.Errorf("Record JSON = %s, want %s", data, want)
+	}
+	data, err = json.Marshal(p.Record(tasks.Task{ID: "unphased"}))
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	if want := `{"id":"unphased","labels":[],"archived":false,"dueDate":""}`; string(data) != want {
+		t.Errorf("Record JSON = %s, want %s", data, want)
+	}
+
+	if p, err := NewProjection(nil); err != nil || !reflect.DeepEqual(p.Fields(), DefaultFields) {
+		t.Errorf("NewProjection(nil) = %v, %v", p.Fields(), err)
+	}
+	if _, err := NewProjection([]string{"id", "owner"}); !errors.Is(err, ErrUnknownField) {
+		t.Errorf("Expected ErrUnknownField, got %v", err)
+	}
+}
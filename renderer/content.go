@@ -0,0 +1,88 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grokify/structured-tasks/tasks"
+)
+
+// renderSections writes freeform document sections.
+func renderSections(sb *strings.Builder, sections []tasks.Section, tl *tasks.TaskList, opts Options) {
+	for _, section := range sections {
+		renderSectionHeading(sb, section.Title, tl.Project, opts)
+		renderContent(sb, section.Content)
+
+		if opts.HorizontalRules {
+			sb.WriteString("---\n\n")
+		}
+	}
+}
+
+// renderContent writes content blocks as Markdown.
+func renderContent(sb *strings.Builder, blocks []tasks.ContentBlock) {
+	for _, b := range blocks {
+		switch b.Type {
+		case tasks.ContentText:
+			sb.WriteString(strings.TrimRight(b.Value, "\n") + "\n\n")
+		case tasks.ContentCode:
+			writeFence(sb, b.Language, b.Value)
+		case tasks.ContentDiagram:
+			lang := "text"
+			if b.Format == "mermaid" {
+				lang = "mermaid"
+			}
+			writeFence(sb, lang, b.Value)
+		case tasks.ContentBlockquote:
+			for _, line := range strings.Split(strings.TrimRight(b.Value, "\n"), "\n") {
+				sb.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+			}
+			sb.WriteString("\n")
+		case tasks.ContentList:
+			for _, item := range b.Items {
+				sb.WriteString("- " + item + "\n")
+			}
+			sb.WriteString("\n")
+		case tasks.ContentTable:
+			writeTable(sb, b.Headers, b.Rows)
+		}
+	}
+}
+
+// writeFence writes a fenced code block, lengthening the fence if the code
+// contains one.
+func writeFence(sb *strings.Builder, lang, code string) {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	fmt.Fprintf(sb, "%s%s\n%s\n%s\n\n", fence, lang, strings.TrimRight(code, "\n"), fence)
+}
+
+func writeTable(sb *strings.Builder, headers []string, rows [][]string) {
+	if len(headers) == 0 {
+		return
+	}
+	writeTableRow(sb, headers)
+	sep := make([]string, len(headers))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	writeTableRow(sb, sep)
+	for _, row := range rows {
+		cells := make([]string, len(headers))
+		copy(cells, row)
+		writeTableRow(sb, cells)
+	}
+	sb.WriteString("\n")
+}
+
+func writeTableRow(sb *strings.Builder, cells []string) {
+	sb.WriteString("|")
+	for _, cell := range cells {
+		cell = strings.ReplaceAll(cell, "|", `\|`)
+		cell = strings.ReplaceAll(cell, "\n", "<br>")
+		sb.WriteString(" " + cell + " |")
+	}
+	sb.WriteString("\n")
+}
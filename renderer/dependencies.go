@@ -0,0 +1,115 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grokify/structured-tasks/tasks"
+)
+
+// externalStatusLabels maps external dependency statuses to emoji and labels.
+var externalStatusLabels = map[tasks.ExternalStatus][2]string{
+	tasks.ExternalAvailable:   {"✅", "Available"},
+	tasks.ExternalBuildClient: {"🔨", "Build Client"},
+	tasks.ExternalPlanned:     {"📋", "Planned"},
+}
+
+// externalStatusLabel returns the display label of an external dependency
+// status, with its emoji if enabled.
+func externalStatusLabel(s tasks.ExternalStatus, opts Options) string {
+	label, ok := externalStatusLabels[s]
+	if !ok {
+		return string(s)
+	}
+	if opts.UseEmoji {
+		return label[0] + " " + label[1]
+	}
+	return label[1]
+}
+
+// renderDependencies writes the "Dependencies" section: a table of external
+// dependencies with the tasks waiting on them, and the internal package
+// graph as a list.
+func renderDependencies(sb *strings.Builder, tl *tasks.TaskList, opts Options) {
+	deps := tl.Dependencies
+	renderSectionHeading(sb, "Dependencies", tl.Project, opts)
+
+	if len(deps.External) > 0 {
+		sb.WriteString("### External\n\n")
+		sb.WriteString("| Dependency | Status | Note | Waiting Tasks |\n")
+		sb.WriteString("|------------|--------|------|---------------|\n")
+		for _, d := range deps.External {
+			var waiting []string
+			for _, task := range tl.TasksWaitingOn(d.Name) {
+				waiting = append(waiting, fmt.Sprintf("[%s](#%s)", task.Title, taskSlug(task)))
+			}
+			fmt.Fprintf(sb, "| %s | %s | %s | %s |\n", d.Name, externalStatusLabel(d.Status, opts), d.Note, strings.Join(waiting, ", "))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(deps.Internal) > 0 {
+		sb.WriteString("### Internal\n\n")
+		for _, d := range deps.Internal {
+			if len(d.DependsOn) == 0 {
+				fmt.Fprintf(sb, "- `%s`\n", d.Package)
+				continue
+			}
+			fmt.Fprintf(sb, "- `%s` → `%s`\n", d.Package, strings.Join(d.DependsOn, "`, `"))
+		}
+		sb.WriteString("\n")
+	}
+
+	if opts.HorizontalRules {
+		sb.WriteString("---\n\n")
+	}
+}
+
+// hasDependencies returns true if the task list declares any dependencies.
+func hasDependencies(tl *tasks.TaskList) bool {
+	return tl.Dependencies != nil && (len(tl.Dependencies.External) > 0 || len(tl.Dependencies.Internal) > 0)
+}
+
+// waitsOnLabel returns the "Waits on" line for a task, naming each external
+// dependency with its status.
+func waitsOnLabel(task tasks.Task, tl *tasks.TaskList, opts Options) string {
+	names := make([]string, 0, len(task.WaitsOn))
+	for _, name := range task.WaitsOn {
+		if d, ok := tl.ExternalDependency(name); ok && d.Status != "" {
+			name = fmt.Sprintf("%s (%s)", name, externalStatusLabel(d.Status, opts))
+		}
+		names = append(names, name)
+	}
+	return "Waits on: " + strings.Join(names, ", ")
+}
+
+// PackageGraph returns the internal package graph of a task list as a task
+// list and dependency graph that the graph renderers can draw. Each package
+// is a node whose ID and title are its path, and each edge points from a
+// package to a package it depends on. Packages that are only depended on
+// are included as nodes.
+func PackageGraph(tl *tasks.TaskList) (*tasks.TaskList, DepsResult) {
+	graph := &tasks.TaskList{IRVersion: tl.IRVersion, Project: tl.Project}
+	result := DepsResult{TaskMap: make(map[string]tasks.Task)}
+	if tl.Dependencies == nil {
+		return graph, result
+	}
+	add := func(pkg string) {
+		if _, ok := result.TaskMap[pkg]; ok {
+			return
+		}
+		node := tasks.Task{ID: pkg, Title: pkg}
+		result.TaskMap[pkg] = node
+		graph.Tasks = append(graph.Tasks, node)
+	}
+	for _, d := range tl.Dependencies.Internal {
+		add(d.Package)
+	}
+	for _, d := range tl.Dependencies.Internal {
+		for _, dep := range d.DependsOn {
+			add(dep)
+			result.Edges = append(result.Edges, Edge{From: d.Package, To: dep})
+		}
+	}
+	return graph, result
+}
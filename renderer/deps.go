@@ -3,6 +3,7 @@ package renderer
 import (
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 
 	"github.com/grokify/structured-tasks/tasks"
@@ -10,7 +11,8 @@ import (
 
 // Edge represents a dependency relationship between two tasks.
 type Edge struct {
-	From, To string
+	From string `json:"from"`
+	To   string `json:"to"`
 }
 
 // DepsResult contains the dependency graph data.
@@ -37,29 +39,342 @@ func BuildDependencyGraph(tl *tasks.TaskList) DepsResult {
 	}
 }
 
+// ClusterBy specifies how graph nodes are grouped into subgraphs.
+type ClusterBy string
+
+const (
+	ClusterNone    ClusterBy = ""
+	ClusterByArea  ClusterBy = "area"
+	ClusterByPhase ClusterBy = "phase"
+)
+
+// Direction specifies the layout direction of a graph.
+type Direction string
+
+const (
+	DirectionTD Direction = "TD"
+	DirectionLR Direction = "LR"
+)
+
+// DefaultLinkTemplate links each node to its task anchor in the generated Markdown.
+const DefaultLinkTemplate = "#{slug}"
+
+// GraphOptions controls how dependency graphs are rendered.
+type GraphOptions struct {
+	// ClusterBy groups nodes into subgraphs by area or phase.
+	ClusterBy ClusterBy
+
+	// IncludeIsolated includes tasks that have no dependency edges.
+	IncludeIsolated bool
+
+	// Direction sets the layout direction. If empty, uses the format default
+	// (TD for Mermaid, LR for DOT).
+	Direction Direction
+
+	// NodeLinks makes nodes clickable.
+	NodeLinks bool
+
+	// LinkTemplate is the link target for clickable nodes. The placeholders
+	// {id} and {slug} are replaced with the task ID and anchor slug.
+	// If empty, uses DefaultLinkTemplate.
+	LinkTemplate string
+
+	// ShowLegend renders a legend explaining node shapes and colors.
+	ShowLegend bool
+
+	// Resolver labels cross-project dependencies (project#task-id) with the
+	// title and status of the referenced task. If nil, external nodes are
+	// labeled with the reference itself.
+	Resolver tasks.ProjectResolver
+}
+
+// DefaultGraphOptions returns the default graph rendering options.
+func DefaultGraphOptions() GraphOptions {
+	return GraphOptions{}
+}
+
+// WithClusterBy sets the node clustering strategy.
+func (o GraphOptions) WithClusterBy(c ClusterBy) GraphOptions {
+	o.ClusterBy = c
+	return o
+}
+
+// WithIsolated enables or disables rendering of tasks without edges.
+func (o GraphOptions) WithIsolated(enabled bool) GraphOptions {
+	o.IncludeIsolated = enabled
+	return o
+}
+
+// WithDirection sets the layout direction.
+func (o GraphOptions) WithDirection(d Direction) GraphOptions {
+	o.Direction = d
+	return o
+}
+
+// WithNodeLinks enables clickable nodes using the given link template.
+func (o GraphOptions) WithNodeLinks(template string) GraphOptions {
+	o.NodeLinks = true
+	o.LinkTemplate = template
+	return o
+}
+
+// WithLegend enables or disables the graph legend.
+func (o GraphOptions) WithLegend(enabled bool) GraphOptions {
+	o.ShowLegend = enabled
+	return o
+}
+
+// WithResolver sets the resolver used to label cross-project dependencies.
+func (o GraphOptions) WithResolver(r tasks.ProjectResolver) GraphOptions {
+	o.Resolver = r
+	return o
+}
+
+// nodeCluster is a named group of node IDs.
+type nodeCluster struct {
+	ID    string
+	Title string
+	Nodes []string
+}
+
+// graphNodes returns the node IDs to render, in order of first appearance in
+// the edge list followed by isolated tasks in array order. Isolated tasks are
+// limited to those in the task map so that focused subgraphs stay focused.
+func graphNodes(tl *tasks.TaskList, deps DepsResult, gopts GraphOptions) []string {
+	var nodes []string
+	seen := make(map[string]bool)
+	for _, e := range deps.Edges {
+		for _, id := range []string{e.From, e.To} {
+			if !seen[id] {
+				nodes = append(nodes, id)
+				seen[id] = true
+			}
+		}
+	}
+	if gopts.IncludeIsolated {
+		for _, task := range tl.Tasks {
+			if _, ok := deps.TaskMap[task.ID]; !ok {
+				continue
+			}
+			if task.ID != "" && !seen[task.ID] {
+				nodes = append(nodes, task.ID)
+				seen[task.ID] = true
+			}
+		}
+	}
+	return nodes
+}
+
+// isExternal reports whether a node is a cross-project reference rather
+// than a task in this list.
+func isExternal(id string, deps DepsResult) bool {
+	if _, ok := deps.TaskMap[id]; ok {
+		return false
+	}
+	_, ok := tasks.ParseRef(id)
+	return ok
+}
+
+// graphTask returns the task drawn for a node. External nodes are labeled
+// "project: title" when the resolver finds the referenced task, and with
+// the reference itself otherwise. External tasks have no ID, so they are
+// never linked.
+func graphTask(id string, deps DepsResult, gopts GraphOptions) (task tasks.Task, external bool) {
+	if !isExternal(id, deps) {
+		return deps.TaskMap[id], false
+	}
+	ref, _ := tasks.ParseRef(id)
+	task = tasks.Task{Title: id}
+	if gopts.Resolver != nil {
+		if target, found, _ := tasks.ResolveRef(gopts.Resolver, ref); found {
+			task.Title = ref.Project + ": " + target.Title
+			task.Status = target.Status
+		}
+	}
+	return task, true
+}
+
+// hasExternal reports whether any node is external.
+func hasExternal(nodes []string, deps DepsResult) bool {
+	for _, id := range nodes {
+		if isExternal(id, deps) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeKey returns a graph-safe identifier for a node. External nodes are
+// prefixed so they cannot collide with local task IDs.
+func nodeKey(id string, deps DepsResult) string {
+	if isExternal(id, deps) {
+		return "ext_" + graphID(id)
+	}
+	return graphID(id)
+}
+
+// mermaidID returns the Mermaid identifier for a node. Local task IDs are
+// used as is unless they contain characters Mermaid rejects, such as the
+// slashes in package paths; external references always do.
+func mermaidID(id string, deps DepsResult) string {
+	if isExternal(id, deps) || strings.ContainsFunc(id, isMermaidUnsafe) {
+		return nodeKey(id, deps)
+	}
+	return id
+}
+
+// uniqueKeys maps each node to the identifier given by key, adding a numeric
+// suffix where distinct IDs would otherwise share one, such as "task-1" and
+// "task_1" once sanitized. Nodes keep their plain key where it is unique, so
+// suffixes are assigned in node order to the later duplicates.
+func uniqueKeys(nodes []string, key func(string) string) map[string]string {
+	base := make(map[string]string, len(nodes))
+	count := make(map[string]int)
+	for _, id := range nodes {
+		base[id] = key(id)
+		count[base[id]]++
+	}
+	keys := make(map[string]string, len(nodes))
+	taken := make(map[string]bool)
+	for _, id := range nodes {
+		k := base[id]
+		if taken[k] {
+			for n := 2; ; n++ {
+				candidate := fmt.Sprintf("%s_%d", k, n)
+				if !taken[candidate] && count[candidate] == 0 {
+					k = candidate
+					break
+				}
+			}
+		}
+		keys[id] = k
+		taken[k] = true
+	}
+	return keys
+}
+
+func isMermaidUnsafe(r rune) bool {
+	return r != '-' && r != '_' && !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9')
+}
+
+// clusterNodes groups nodes by the clustering strategy. Clusters follow area
+// order or phase order, with unassigned nodes in a trailing cluster.
+// External nodes are grouped in a final "External" cluster.
+func clusterNodes(tl *tasks.TaskList, deps DepsResult, nodes []string, clusterBy ClusterBy) []nodeCluster {
+	var clusters []nodeCluster
+
+	var external []string
+	if clusterBy != ClusterNone {
+		local := make([]string, 0, len(nodes))
+		for _, id := range nodes {
+			if isExternal(id, deps) {
+				external = append(external, id)
+			} else {
+				local = append(local, id)
+			}
+		}
+		nodes = local
+	}
+
+	switch clusterBy {
+	case ClusterByArea:
+		byArea := make(map[string][]string)
+		for _, id := range nodes {
+			byArea[deps.TaskMap[id].Area] = append(byArea[deps.TaskMap[id].Area], id)
+		}
+		for _, area := range tl.Areas {
+			if ids := byArea[area.ID]; len(ids) > 0 {
+				clusters = append(clusters, nodeCluster{ID: "area_" + graphID(area.ID), Title: area.Name, Nodes: ids})
+				delete(byArea, area.ID)
+			}
+		}
+		// Areas referenced by tasks but not declared, then unassigned tasks
+		var undeclared []string
+		for area := range byArea {
+			if area != "" {
+				undeclared = append(undeclared, area)
+			}
+		}
+		sort.Strings(undeclared)
+		for _, area := range undeclared {
+			clusters = append(clusters, nodeCluster{ID: "area_" + graphID(area), Title: area, Nodes: byArea[area]})
+		}
+		if ids := byArea[""]; len(ids) > 0 {
+			clusters = append(clusters, nodeCluster{ID: "area_other", Title: "Other", Nodes: ids})
+		}
+
+	case ClusterByPhase:
+		byPhase := make(map[int][]string)
+		for _, id := range nodes {
+			byPhase[deps.TaskMap[id].Phase] = append(byPhase[deps.TaskMap[id].Phase], id)
+		}
+		phases := make([]int, 0, len(byPhase))
+		for phase := range byPhase {
+			if phase > 0 {
+				phases = append(phases, phase)
+			}
+		}
+		sort.Ints(phases)
+		for _, phase := range phases {
+			clusters = append(clusters, nodeCluster{ID: fmt.Sprintf("phase_%d", phase), Title: tl.PhaseName(phase), Nodes: byPhase[phase]})
+		}
+		if ids := byPhase[0]; len(ids) > 0 {
+			clusters = append(clusters, nodeCluster{ID: "phase_unphased", Title: tl.PhaseName(0), Nodes: ids})
+		}
+
+	default:
+		clusters = append(clusters, nodeCluster{Nodes: nodes})
+	}
+
+	if len(external) > 0 {
+		clusters = append(clusters, nodeCluster{ID: "external", Title: "External", Nodes: external})
+	}
+	return clusters
+}
+
+// nodeLink returns the link target for a task node.
+func nodeLink(task tasks.Task, gopts GraphOptions) string {
+	template := gopts.LinkTemplate
+	if template == "" {
+		template = DefaultLinkTemplate
+	}
+	link := strings.ReplaceAll(template, "{id}", task.ID)
+	return strings.ReplaceAll(link, "{slug}", taskSlug(task))
+}
+
 // RenderMermaid renders a dependency graph in Mermaid format.
 func RenderMermaid(w io.Writer, tl *tasks.TaskList, deps DepsResult) {
+	RenderMermaidWithOptions(w, tl, deps, DefaultGraphOptions())
+}
+
+// RenderMermaidWithOptions renders a dependency graph in Mermaid format
+// using the given graph options.
+func RenderMermaidWithOptions(w io.Writer, tl *tasks.TaskList, deps DepsResult, gopts GraphOptions) {
+	direction := gopts.Direction
+	if direction == "" {
+		direction = DirectionTD
+	}
+
 	fmt.Fprintln(w, "```mermaid")
-	fmt.Fprintln(w, "graph TD")
+	fmt.Fprintf(w, "graph %s\n", direction)
 
 	// Define nodes with labels
-	seen := make(map[string]bool)
-	for _, e := range deps.Edges {
-		if !seen[e.From] {
-			task := deps.TaskMap[e.From]
-			label := sanitizeMermaid(task.Title)
-			shape := StatusShape(task.Status)
-			fmt.Fprintf(w, "    %s%s%s\n", e.From, shape[0], label)
-			fmt.Fprintf(w, "    %s%s\n", e.From, shape[1])
-			seen[e.From] = true
+	statuses := tl.StatusRegistry()
+	nodes := graphNodes(tl, deps, gopts)
+	keys := uniqueKeys(nodes, func(id string) string { return mermaidID(id, deps) })
+	for _, cluster := range clusterNodes(tl, deps, nodes, gopts.ClusterBy) {
+		indent := "    "
+		if cluster.ID != "" {
+			fmt.Fprintf(w, "    subgraph %s[\"%s\"]\n", cluster.ID, mermaidText(cluster.Title))
+			indent = "        "
+		}
+		for _, id := range cluster.Nodes {
+			task, _ := graphTask(id, deps, gopts)
+			shape := mermaidShape(statuses.Shape(task.Status))
+			fmt.Fprintf(w, "%s%s%s\"%s\"%s\n", indent, keys[id], shape[0], mermaidText(task.Title), shape[1])
 		}
-		if !seen[e.To] {
-			task := deps.TaskMap[e.To]
-			label := sanitizeMermaid(task.Title)
-			shape := StatusShape(task.Status)
-			fmt.Fprintf(w, "    %s%s%s\n", e.To, shape[0], label)
-			fmt.Fprintf(w, "    %s%s\n", e.To, shape[1])
-			seen[e.To] = true
+		if cluster.ID != "" {
+			fmt.Fprintln(w, "    end")
 		}
 	}
 
@@ -67,7 +382,46 @@ func RenderMermaid(w io.Writer, tl *tasks.TaskList, deps DepsResult) {
 
 	// Define edges
 	for _, e := range deps.Edges {
-		fmt.Fprintf(w, "    %s --> %s\n", e.From, e.To)
+		fmt.Fprintf(w, "    %s --> %s\n", keys[e.From], keys[e.To])
+	}
+
+	// Style external nodes
+	external := hasExternal(nodes, deps)
+	if external {
+		var ids []string
+		for _, id := range nodes {
+			if isExternal(id, deps) {
+				ids = append(ids, keys[id])
+			}
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "    classDef external stroke-dasharray: 5 5,fill:#f5f5f5")
+		fmt.Fprintf(w, "    class %s external\n", strings.Join(ids, ","))
+	}
+
+	// Clickable nodes
+	if gopts.NodeLinks {
+		fmt.Fprintln(w)
+		for _, id := range nodes {
+			task, ok := deps.TaskMap[id]
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(w, "    click %s href \"%s\"\n", keys[id], nodeLink(task, gopts))
+		}
+	}
+
+	if gopts.ShowLegend {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "    subgraph legend[\"Legend\"]")
+		for _, status := range statuses.All() {
+			shape := mermaidShape(statuses.Shape(status.ID))
+			fmt.Fprintf(w, "        legend_%s%s\"%s\"%s\n", graphID(string(status.ID)), shape[0], mermaidText(status.Description), shape[1])
+		}
+		if external {
+			fmt.Fprintln(w, "        legend_external[\"Other project\"]:::external")
+		}
+		fmt.Fprintln(w, "    end")
 	}
 
 	fmt.Fprintln(w, "```")
@@ -75,21 +429,50 @@ func RenderMermaid(w io.Writer, tl *tasks.TaskList, deps DepsResult) {
 
 // RenderDOT renders a dependency graph in Graphviz DOT format.
 func RenderDOT(w io.Writer, tl *tasks.TaskList, deps DepsResult) {
+	RenderDOTWithOptions(w, tl, deps, DefaultGraphOptions())
+}
+
+// RenderDOTWithOptions renders a dependency graph in Graphviz DOT format
+// using the given graph options.
+func RenderDOTWithOptions(w io.Writer, tl *tasks.TaskList, deps DepsResult, gopts GraphOptions) {
+	direction := gopts.Direction
+	if direction == "" {
+		direction = DirectionLR
+	}
+	rankdir := string(direction)
+	if direction == DirectionTD {
+		rankdir = "TB"
+	}
+
 	fmt.Fprintf(w, "digraph \"%s\" {\n", tl.Project)
-	fmt.Fprintln(w, "    rankdir=LR;")
+	fmt.Fprintf(w, "    rankdir=%s;\n", rankdir)
 	fmt.Fprintln(w, "    node [shape=box];")
 	fmt.Fprintln(w)
 
 	// Define nodes
-	seen := make(map[string]bool)
-	for _, e := range deps.Edges {
-		for _, id := range []string{e.From, e.To} {
-			if !seen[id] {
-				task := deps.TaskMap[id]
-				color := StatusColor(task.Status)
-				fmt.Fprintf(w, "    %s [label=\"%s\" color=\"%s\"];\n", id, sanitizeDOT(task.Title), color)
-				seen[id] = true
+	statuses := tl.StatusRegistry()
+	nodes := graphNodes(tl, deps, gopts)
+	for _, cluster := range clusterNodes(tl, deps, nodes, gopts.ClusterBy) {
+		indent := "    "
+		if cluster.ID != "" {
+			fmt.Fprintf(w, "    subgraph cluster_%s {\n", cluster.ID)
+			fmt.Fprintf(w, "        label=\"%s\";\n", sanitizeDOT(cluster.Title))
+			indent = "        "
+		}
+		for _, id := range cluster.Nodes {
+			task, external := graphTask(id, deps, gopts)
+			color := statuses.Color(task.Status)
+			attrs := fmt.Sprintf("label=\"%s\" color=\"%s\"", sanitizeDOT(task.Title), color)
+			if external {
+				attrs += " style=\"dashed\""
 			}
+			if gopts.NodeLinks && task.ID != "" {
+				attrs += fmt.Sprintf(" URL=\"%s\"", sanitizeDOT(nodeLink(task, gopts)))
+			}
+			fmt.Fprintf(w, "%s%s [%s];\n", indent, dotID(id), attrs)
+		}
+		if cluster.ID != "" {
+			fmt.Fprintln(w, "    }")
 		}
 	}
 
@@ -97,47 +480,82 @@ func RenderDOT(w io.Writer, tl *tasks.TaskList, deps DepsResult) {
 
 	// Define edges
 	for _, e := range deps.Edges {
-		fmt.Fprintf(w, "    %s -> %s;\n", e.From, e.To)
+		fmt.Fprintf(w, "    %s -> %s;\n", dotID(e.From), dotID(e.To))
+	}
+
+	if gopts.ShowLegend {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "    subgraph cluster_legend {")
+		fmt.Fprintln(w, "        label=\"Legend\";")
+		for _, status := range statuses.All() {
+			fmt.Fprintf(w, "        legend_%s [label=\"%s\" color=\"%s\"];\n", graphID(string(status.ID)), sanitizeDOT(status.Description), statuses.Color(status.ID))
+		}
+		if hasExternal(nodes, deps) {
+			fmt.Fprintln(w, "        legend_external [label=\"Other project\" style=\"dashed\"];")
+		}
+		fmt.Fprintln(w, "    }")
 	}
 
 	fmt.Fprintln(w, "}")
 }
 
-// StatusShape returns the Mermaid node shape for a status.
+// StatusShape returns the Mermaid node shape for a built-in status.
 // Returns [opening, closing] brackets.
 func StatusShape(status tasks.Status) [2]string {
-	switch status {
-	case tasks.StatusCompleted:
-		return [2]string{"([", "])"} // Stadium/rounded
-	case tasks.StatusInProgress:
-		return [2]string{"{{", "}}"} // Hexagon
-	case tasks.StatusPlanned:
-		return [2]string{"[", "]"} // Rectangle
-	default:
-		return [2]string{"((", "))"} // Circle
-	}
+	return mermaidShape(tasks.DefaultStatusRegistry().Shape(status))
 }
 
-// StatusColor returns the DOT node color for a status.
+// StatusColor returns the DOT node color for a built-in status.
 func StatusColor(status tasks.Status) string {
-	switch status {
-	case tasks.StatusCompleted:
-		return "green"
-	case tasks.StatusInProgress:
-		return "orange"
-	case tasks.StatusPlanned:
-		return "blue"
+	return tasks.DefaultStatusRegistry().Color(status)
+}
+
+// mermaidShape returns the Mermaid brackets for a node shape.
+func mermaidShape(shape string) [2]string {
+	switch shape {
+	case tasks.ShapeStadium:
+		return [2]string{"([", "])"}
+	case tasks.ShapeHexagon:
+		return [2]string{"{{", "}}"}
+	case tasks.ShapeRectangle:
+		return [2]string{"[", "]"}
 	default:
-		return "gray"
+		return [2]string{"((", "))"}
 	}
 }
 
 // sanitizeMermaid escapes special characters for Mermaid labels.
 func sanitizeMermaid(s string) string {
+	return "[\"" + mermaidText(s) + "\"]"
+}
+
+// mermaidText escapes characters that cannot appear inside a quoted Mermaid label.
+func mermaidText(s string) string {
 	s = strings.ReplaceAll(s, "\"", "'")
 	s = strings.ReplaceAll(s, "[", "(")
 	s = strings.ReplaceAll(s, "]", ")")
-	return "[\"" + s + "\"]"
+	return s
+}
+
+// graphID converts an arbitrary string into an identifier safe for graph formats.
+func graphID(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune('_')
+		}
+	}
+	return sb.String()
+}
+
+// dotID returns a DOT node identifier, quoting IDs that are not plain identifiers.
+func dotID(id string) string {
+	if id != "" && graphID(id) == id {
+		return id
+	}
+	return "\"" + sanitizeDOT(id) + "\""
 }
 
 // sanitizeDOT escapes special characters for DOT labels.
@@ -0,0 +1,84 @@
+package renderer
+
+import (
+	"sort"
+
+	"github.com/grokify/structured-tasks/tasks"
+)
+
+// Ancestors returns the IDs of tasks that the given task depends on, up to
+// depth levels upstream. A negative depth follows dependencies transitively.
+// Results are ordered by distance, then by task ID.
+func (d DepsResult) Ancestors(id string, depth int) []string {
+	upstream := make(map[string][]string)
+	for _, e := range d.Edges {
+		upstream[e.To] = append(upstream[e.To], e.From)
+	}
+	return walkGraph(upstream, id, depth)
+}
+
+// Descendants returns the IDs of tasks that depend on the given task, up to
+// depth levels downstream. A negative depth follows dependents transitively.
+// Results are ordered by distance, then by task ID.
+func (d DepsResult) Descendants(id string, depth int) []string {
+	downstream := make(map[string][]string)
+	for _, e := range d.Edges {
+		downstream[e.From] = append(downstream[e.From], e.To)
+	}
+	return walkGraph(downstream, id, depth)
+}
+
+// Impact returns every task that would slip if the given task slips,
+// i.e. all transitive dependents.
+func (d DepsResult) Impact(id string) []string {
+	return d.Descendants(id, -1)
+}
+
+// Focus returns the subgraph around a task containing its ancestors up to
+// up levels and its descendants up to down levels. Negative values are
+// transitive. Only edges between tasks in the subgraph are kept.
+func (d DepsResult) Focus(id string, up, down int) DepsResult {
+	keep := map[string]bool{id: true}
+	for _, a := range d.Ancestors(id, up) {
+		keep[a] = true
+	}
+	for _, desc := range d.Descendants(id, down) {
+		keep[desc] = true
+	}
+
+	result := DepsResult{TaskMap: make(map[string]tasks.Task)}
+	for _, e := range d.Edges {
+		if keep[e.From] && keep[e.To] {
+			result.Edges = append(result.Edges, e)
+		}
+	}
+	for taskID := range keep {
+		if task, ok := d.TaskMap[taskID]; ok {
+			result.TaskMap[taskID] = task
+		}
+	}
+	return result
+}
+
+// walkGraph performs a breadth-first walk from start over the adjacency map.
+func walkGraph(adjacency map[string][]string, start string, depth int) []string {
+	var result []string
+	visited := map[string]bool{start: true}
+	frontier := []string{start}
+
+	for level := 0; len(frontier) > 0 && (depth < 0 || level < depth); level++ {
+		var next []string
+		for _, id := range frontier {
+			for _, n := range adjacency[id] {
+				if !visited[n] {
+					visited[n] = true
+					next = append(next, n)
+				}
+			}
+		}
+		sort.Strings(next)
+		result = append(result, next...)
+		frontier = next
+	}
+	return result
+}
@@ -0,0 +1,90 @@
+package renderer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/grokify/structured-tasks/tasks"
+)
+
+// a -> b -> c -> d, a -> e, x isolated
+func depsQueryTaskList() *tasks.TaskList {
+	return &tasks.TaskList{
+		Tasks: []tasks.Task{
+			{ID: "a", Title: "A"},
+			{ID: "b", Title: "B", DependsOn: []string{"a"}},
+			{ID: "c", Title: "C", DependsOn: []string{"b"}},
+			{ID: "d", Title: "D", DependsOn: []string{"c"}},
+			{ID: "e", Title: "E", DependsOn: []string{"a"}},
+			{ID: "x", Title: "X"},
+		},
+	}
+}
+
+func TestAncestors(t *testing.T) {
+	deps := BuildDependencyGraph(depsQueryTaskList())
+
+	tests := []struct {
+		id       string
+		depth    int
+		expected []string
+	}{
+		{"d", 1, []string{"c"}},
+		{"d", 2, []string{"c", "b"}},
+		{"d", -1, []string{"c", "b", "a"}},
+		{"d", 0, nil},
+		{"a", -1, nil},
+	}
+
+	for _, tt := range tests {
+		result := deps.Ancestors(tt.id, tt.depth)
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("Ancestors(%s, %d): expected %v, got %v", tt.id, tt.depth, tt.expected, result)
+		}
+	}
+}
+
+func TestDescendants(t *testing.T) {
+	deps := BuildDependencyGraph(depsQueryTaskList())
+
+	tests := []struct {
+		id       string
+		depth    int
+		expected []string
+	}{
+		{"a", 1, []string{"b", "e"}},
+		{"a", -1, []string{"b", "e", "c", "d"}},
+		{"x", -1, nil},
+	}
+
+	for _, tt := range tests {
+		result := deps.Descendants(tt.id, tt.depth)
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("Descendants(%s, %d): expected %v, got %v", tt.id, tt.depth, tt.expected, result)
+		}
+	}
+
+	if impact := deps.Impact("b"); !reflect.DeepEqual(impact, []string{"c", "d"}) {
+		t.Errorf("Impact(b): expected [c d], got %v", impact)
+	}
+}
+
+func TestFocus(t *testing.T) {
+	tl := depsQueryTaskList()
+	deps := BuildDependencyGraph(tl)
+
+	focused := deps.Focus("c", 1, 1)
+	expectedEdges := []Edge{{From: "b", To: "c"}, {From: "c", To: "d"}}
+	if !reflect.DeepEqual(focused.Edges, expectedEdges) {
+		t.Errorf("expected edges %v, got %v", expectedEdges, focused.Edges)
+	}
+	if len(focused.TaskMap) != 3 {
+		t.Errorf("expected 3 tasks in focused map, got %d", len(focused.TaskMap))
+	}
+
+	// Isolated rendering must not pull in tasks outside the focus
+	nodes := graphNodes(tl, focused, DefaultGraphOptions().WithIsolated(true))
+	if !reflect.DeepEqual(nodes, []string{"b", "c", "d"}) {
+		t.Errorf("expected focused nodes [b c d], got %v", nodes)
+	}
+}
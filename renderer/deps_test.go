@@ -193,3 +193,275 @@ func TestSanitizeDOT(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderMermaidWithOptions(t *testing.T) {
+	tl := &tasks.TaskList{
+		Project: "test-project",
+		Areas: []tasks.Area{
+			{ID: "core", Name: "Core"},
+			{ID: "api", Name: "API"},
+		},
+		Tasks: []tasks.Task{
+			{ID: "task1", Title: "First Task", Status: tasks.StatusCompleted, Area: "core", Phase: 1},
+			{ID: "task2", Title: "Second Task", Status: tasks.StatusPlanned, Area: "api", Phase: 2, DependsOn: []string{"task1"}},
+			{ID: "task3", Title: "Lonely Task", Status: tasks.StatusFuture, Area: "api", Phase: 2},
+		},
+	}
+	deps := BuildDependencyGraph(tl)
+
+	t.Run("default omits isolated", func(t *testing.T) {
+		var buf bytes.Buffer
+		RenderMermaidWithOptions(&buf, tl, deps, DefaultGraphOptions())
+		output := buf.String()
+		if strings.Contains(output, "task3") {
+			t.Error("expected isolated task to be omitted")
+		}
+		if !strings.Contains(output, `task1(["First Task"])`) {
+			t.Errorf("expected stadium node for completed task, got:\n%s", output)
+		}
+	})
+
+	t.Run("isolated and direction", func(t *testing.T) {
+		var buf bytes.Buffer
+		RenderMermaidWithOptions(&buf, tl, deps, DefaultGraphOptions().WithIsolated(true).WithDirection(DirectionLR))
+		output := buf.String()
+		if !strings.Contains(output, "graph LR") {
+			t.Error("expected graph LR directive")
+		}
+		if !strings.Contains(output, `task3(("Lonely Task"))`) {
+			t.Errorf("expected isolated task node, got:\n%s", output)
+		}
+	})
+
+	t.Run("cluster by area", func(t *testing.T) {
+		var buf bytes.Buffer
+		RenderMermaidWithOptions(&buf, tl, deps, DefaultGraphOptions().WithClusterBy(ClusterByArea))
+		output := buf.String()
+		core := strings.Index(output, `subgraph area_core["Core"]`)
+		api := strings.Index(output, `subgraph area_api["API"]`)
+		if core < 0 || api < 0 {
+			t.Fatalf("expected area subgraphs, got:\n%s", output)
+		}
+		if core > api {
+			t.Error("expected subgraphs in area order")
+		}
+	})
+
+	t.Run("cluster by phase", func(t *testing.T) {
+		var buf bytes.Buffer
+		RenderMermaidWithOptions(&buf, tl, deps, DefaultGraphOptions().WithClusterBy(ClusterByPhase))
+		output := buf.String()
+		if !strings.Contains(output, `subgraph phase_1["Phase 1"]`) || !strings.Contains(output, `subgraph phase_2["Phase 2"]`) {
+			t.Errorf("expected phase subgraphs, got:\n%s", output)
+		}
+	})
+
+	t.Run("links and legend", func(t *testing.T) {
+		var buf bytes.Buffer
+		RenderMermaidWithOptions(&buf, tl, deps, DefaultGraphOptions().WithNodeLinks("").WithLegend(true))
+		output := buf.String()
+		if !strings.Contains(output, `click task1 href "#task1"`) {
+			t.Errorf("expected click directive, got:\n%s", output)
+		}
+		if !strings.Contains(output, `subgraph legend["Legend"]`) {
+			t.Error("expected legend subgraph")
+		}
+	})
+}
+
+func TestRenderDOTWithOptions(t *testing.T) {
+	tl := &tasks.TaskList{
+		Project: "test-project",
+		Areas: []tasks.Area{
+			{ID: "core", Name: "Core"},
+		},
+		Tasks: []tasks.Task{
+			{ID: "task-1", Title: "First Task", Status: tasks.StatusCompleted, Area: "core"},
+			{ID: "task-2", Title: "Second Task", Status: tasks.StatusPlanned, DependsOn: []string{"task-1"}},
+		},
+	}
+	deps := BuildDependencyGraph(tl)
+
+	var buf bytes.Buffer
+	gopts := DefaultGraphOptions().
+		WithClusterBy(ClusterByArea).
+		WithDirection(DirectionTD).
+		WithNodeLinks("https://example.com/tasks/{id}").
+		WithLegend(true)
+	RenderDOTWithOptions(&buf, tl, deps, gopts)
+	output := buf.String()
+
+	expected := []string{
+		"rankdir=TB;",
+		"subgraph cluster_area_core {",
+		`label="Core";`,
+		"subgraph cluster_area_other {",
+		`URL="https://example.com/tasks/task-1"`,
+		`"task-1" -> "task-2";`,
+		"subgraph cluster_legend {",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
+
+type stubResolver map[string]*tasks.TaskList
+
+func (s stubResolver) ResolveProject(key string) (*tasks.TaskList, error) {
+	if tl, ok := s[key]; ok {
+		return tl, nil
+	}
+	return nil, tasks.ErrUnresolvedReference
+}
+
+func TestExternalNodes(t *testing.T) {
+	tl := &tasks.TaskList{
+		Project: "web",
+		Areas:   []tasks.Area{{ID: "ui", Name: "UI"}},
+		Tasks: []tasks.Task{
+			{ID: "login", Title: "Login page", Status: tasks.StatusPlanned, Area: "ui", DependsOn: []string{"api#auth", "billing#plans"}},
+		},
+	}
+	deps := BuildDependencyGraph(tl)
+	gopts := DefaultGraphOptions().WithResolver(stubResolver{
+		"api": {Project: "api", Tasks: []tasks.Task{{ID: "auth", Title: "Auth tokens", Status: tasks.StatusCompleted}}},
+	})
+
+	t.Run("mermaid", func(t *testing.T) {
+		var buf bytes.Buffer
+		RenderMermaidWithOptions(&buf, tl, deps, gopts.WithLegend(true))
+		out := buf.String()
+		for _, want := range []string{
+			`ext_api_auth(["api: Auth tokens"])`,
+			`ext_billing_plans(("billing#plans"))`,
+			"ext_api_auth --> login",
+			"classDef external stroke-dasharray: 5 5",
+			"class ext_api_auth,ext_billing_plans external",
+			`legend_external["Other project"]:::external`,
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("Mermaid missing %q:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("dot clustered", func(t *testing.T) {
+		var buf bytes.Buffer
+		RenderDOTWithOptions(&buf, tl, deps, gopts.WithClusterBy(ClusterByArea))
+		out := buf.String()
+		for _, want := range []string{
+			"subgraph cluster_external {",
+			`"api#auth" [label="api: Auth tokens" color="green" style="dashed"];`,
+			`"api#auth" -> login;`,
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("DOT missing %q:\n%s", want, out)
+			}
+		}
+		if strings.Contains(out, "area_other") {
+			t.Error("External nodes should not be clustered as Other")
+		}
+	})
+
+	t.Run("other formats", func(t *testing.T) {
+		doc := BuildGraphDocument(tl, deps, gopts)
+		if !doc.Nodes[0].External || doc.Nodes[0].Title != "api: Auth tokens" || doc.Nodes[1].External || !doc.Nodes[2].External {
+			t.Errorf("Unexpected nodes: %+v", doc.Nodes)
+		}
+
+		var buf bytes.Buffer
+		RenderPlantUMLWithOptions(&buf, tl, deps, gopts)
+		if !strings.Contains(buf.String(), "as ext_api_auth #green;line.dashed") {
+			t.Errorf("PlantUML missing dashed external node:\n%s", buf.String())
+		}
+		buf.Reset()
+		RenderD2WithOptions(&buf, tl, deps, gopts)
+		if !strings.Contains(buf.String(), "style.stroke-dash: 4") || !strings.Contains(buf.String(), "ext_api_auth -> login") {
+			t.Errorf("D2 missing dashed external node:\n%s", buf.String())
+		}
+	})
+}
+
+func TestPackageGraph(t *testing.T) {
+	tl := &tasks.TaskList{
+		Project: "app",
+		Dependencies: &tasks.Dependencies{
+			Internal: []tasks.InternalDependency{
+				{Package: "github.com/acme/app/cmd", DependsOn: tasks.StringList{"github.com/acme/app/store", "github.com/acme/app/core"}},
+				{Package: "github.com/acme/app/store", DependsOn: tasks.StringList{"github.com/acme/app/core"}},
+			},
+		},
+	}
+	graph, deps := PackageGraph(tl)
+	if len(graph.Tasks) != 3 || len(deps.Edges) != 3 {
+		t.Fatalf("Expected 3 packages and 3 edges, got %d and %d", len(graph.Tasks), len(deps.Edges))
+	}
+	if graph.Tasks[2].ID != "github.com/acme/app/core" {
+		t.Errorf("Expected depended-on package as a node, got %+v", graph.Tasks)
+	}
+
+	var buf bytes.Buffer
+	RenderMermaidWithOptions(&buf, graph, deps, DefaultGraphOptions())
+	out := buf.String()
+	for _, want := range []string{
+		`github_com_acme_app_cmd(("github.com/acme/app/cmd"))`,
+		"github_com_acme_app_cmd --> github_com_acme_app_store",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Mermaid missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	RenderDOTWithOptions(&buf, graph, deps, DefaultGraphOptions())
+	if !strings.Contains(buf.String(), `"github.com/acme/app/store" -> "github.com/acme/app/core";`) {
+		t.Errorf("DOT missing package edge:\n%s", buf.String())
+	}
+
+	if _, deps := PackageGraph(&tasks.TaskList{}); len(deps.Edges) != 0 {
+		t.Errorf("Expected no edges without dependencies, got %v", deps.Edges)
+	}
+}
+
+func TestCustomStatusGraph(t *testing.T) {
+	tl := &tasks.TaskList{
+		Project: "Test",
+		Statuses: []tasks.StatusDef{
+			{ID: "blocked", Description: "Blocked", Color: "#cc0000", Shape: tasks.ShapeHexagon},
+		},
+		Tasks: []tasks.Task{
+			{ID: "a", Title: "A", Status: tasks.StatusCompleted},
+			{ID: "b", Title: "B", Status: "blocked", DependsOn: []string{"a"}},
+		},
+	}
+	deps := BuildDependencyGraph(tl)
+	gopts := DefaultGraphOptions().WithLegend(true)
+
+	var buf bytes.Buffer
+	RenderMermaidWithOptions(&buf, tl, deps, gopts)
+	for _, want := range []string{`b{{"B"}}`, `legend_blocked{{"Blocked"}}`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Mermaid missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	RenderDOTWithOptions(&buf, tl, deps, gopts)
+	if !strings.Contains(buf.String(), `b [label="B" color="#cc0000"];`) {
+		t.Errorf("DOT missing custom color:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	RenderPlantUMLWithOptions(&buf, tl, deps, gopts)
+	if !strings.Contains(buf.String(), "as b #cc0000") {
+		t.Errorf("PlantUML missing custom color:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	RenderD2WithOptions(&buf, tl, deps, gopts)
+	if !strings.Contains(buf.String(), `style.stroke: "#cc0000"`) {
+		t.Errorf("D2 missing quoted custom color:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,292 @@
+package renderer
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/grokify/structured-tasks/tasks"
+)
+
+// GraphNode is a task node in a JSON dependency graph document.
+type GraphNode struct {
+	ID     string       `json:"id"`
+	Title  string       `json:"title"`
+	Status tasks.Status `json:"status,omitempty"`
+	Phase  int          `json:"phase,omitempty"`
+	Area   string       `json:"area,omitempty"`
+	Type   string       `json:"type,omitempty"`
+	Link   string       `json:"link,omitempty"`
+
+	// External marks a cross-project reference (project#task-id).
+	External bool `json:"external,omitempty"`
+}
+
+// GraphDocument is the JSON representation of a dependency graph.
+type GraphDocument struct {
+	Project string      `json:"project"`
+	Nodes   []GraphNode `json:"nodes"`
+	Edges   []Edge      `json:"edges"`
+}
+
+// BuildGraphDocument builds a JSON-serializable node/edge document.
+func BuildGraphDocument(tl *tasks.TaskList, deps DepsResult, gopts GraphOptions) GraphDocument {
+	doc := GraphDocument{
+		Project: tl.Project,
+		Nodes:   []GraphNode{},
+		Edges:   []Edge{},
+	}
+	for _, id := range graphNodes(tl, deps, gopts) {
+		task, external := graphTask(id, deps, gopts)
+		node := GraphNode{
+			ID:       id,
+			Title:    task.Title,
+			Status:   task.Status,
+			Phase:    task.Phase,
+			Area:     task.Area,
+			Type:     task.Type,
+			External: external,
+		}
+		if gopts.NodeLinks && task.ID != "" {
+			node.Link = nodeLink(task, gopts)
+		}
+		doc.Nodes = append(doc.Nodes, node)
+	}
+	doc.Edges = append(doc.Edges, deps.Edges...)
+	return doc
+}
+
+// RenderGraphJSON renders a dependency graph as a JSON node/edge document.
+func RenderGraphJSON(w io.Writer, tl *tasks.TaskList, deps DepsResult) error {
+	return RenderGraphJSONWithOptions(w, tl, deps, DefaultGraphOptions())
+}
+
+// RenderGraphJSONWithOptions renders a dependency graph as a JSON node/edge
+// document using the given graph options.
+func RenderGraphJSONWithOptions(w io.Writer, tl *tasks.TaskList, deps DepsResult, gopts GraphOptions) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(BuildGraphDocument(tl, deps, gopts))
+}
+
+// RenderPlantUML renders a dependency graph in PlantUML format.
+func RenderPlantUML(w io.Writer, tl *tasks.TaskList, deps DepsResult) {
+	RenderPlantUMLWithOptions(w, tl, deps, DefaultGraphOptions())
+}
+
+// RenderPlantUMLWithOptions renders a dependency graph in PlantUML format
+// using the given graph options.
+func RenderPlantUMLWithOptions(w io.Writer, tl *tasks.TaskList, deps DepsResult, gopts GraphOptions) {
+	fmt.Fprintln(w, "@startuml")
+	if gopts.Direction == DirectionLR {
+		fmt.Fprintln(w, "left to right direction")
+	} else {
+		fmt.Fprintln(w, "top to bottom direction")
+	}
+	fmt.Fprintf(w, "title %s\n", tl.Project)
+	fmt.Fprintln(w)
+
+	// Define nodes
+	statuses := tl.StatusRegistry()
+	nodes := graphNodes(tl, deps, gopts)
+	keys := uniqueKeys(nodes, func(id string) string { return nodeKey(id, deps) })
+	for _, cluster := range clusterNodes(tl, deps, nodes, gopts.ClusterBy) {
+		indent := ""
+		if cluster.ID != "" {
+			fmt.Fprintf(w, "package \"%s\" {\n", sanitizePlantUML(cluster.Title))
+			indent = "    "
+		}
+		for _, id := range cluster.Nodes {
+			task, external := graphTask(id, deps, gopts)
+			line := fmt.Sprintf("%srectangle \"%s\" as %s #%s", indent, sanitizePlantUML(task.Title), keys[id], plantUMLColor(statuses.Color(task.Status)))
+			if external {
+				line += ";line.dashed"
+			}
+			if gopts.NodeLinks && task.ID != "" {
+				line += fmt.Sprintf(" [[%s]]", nodeLink(task, gopts))
+			}
+			fmt.Fprintln(w, line)
+		}
+		if cluster.ID != "" {
+			fmt.Fprintln(w, "}")
+		}
+	}
+
+	fmt.Fprintln(w)
+
+	// Define edges
+	for _, e := range deps.Edges {
+		fmt.Fprintf(w, "%s --> %s\n", keys[e.From], keys[e.To])
+	}
+
+	if gopts.ShowLegend {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "legend right")
+		fmt.Fprintln(w, "|= Color |= Status |")
+		for _, status := range statuses.All() {
+			color := plantUMLColor(statuses.Color(status.ID))
+			fmt.Fprintf(w, "|<#%s> %s | %s |\n", color, color, sanitizePlantUML(status.Description))
+		}
+		if hasExternal(nodes, deps) {
+			fmt.Fprintln(w, "| dashed | Other project |")
+		}
+		fmt.Fprintln(w, "endlegend")
+	}
+
+	fmt.Fprintln(w, "@enduml")
+}
+
+// RenderD2 renders a dependency graph in D2 format.
+func RenderD2(w io.Writer, tl *tasks.TaskList, deps DepsResult) {
+	RenderD2WithOptions(w, tl, deps, DefaultGraphOptions())
+}
+
+// RenderD2WithOptions renders a dependency graph in D2 format using the
+// given graph options.
+func RenderD2WithOptions(w io.Writer, tl *tasks.TaskList, deps DepsResult, gopts GraphOptions) {
+	direction := "down"
+	if gopts.Direction == DirectionLR {
+		direction = "right"
+	}
+	fmt.Fprintf(w, "direction: %s\n", direction)
+	fmt.Fprintln(w)
+
+	// Define nodes, recording the fully qualified path of each node
+	statuses := tl.StatusRegistry()
+	paths := make(map[string]string)
+	nodes := graphNodes(tl, deps, gopts)
+	keys := uniqueKeys(nodes, func(id string) string { return nodeKey(id, deps) })
+	for _, cluster := range clusterNodes(tl, deps, nodes, gopts.ClusterBy) {
+		indent := ""
+		prefix := ""
+		if cluster.ID != "" {
+			fmt.Fprintf(w, "%s: \"%s\" {\n", cluster.ID, sanitizeD2(cluster.Title))
+			indent = "  "
+			prefix = cluster.ID + "."
+		}
+		for _, id := range cluster.Nodes {
+			task, external := graphTask(id, deps, gopts)
+			key := keys[id]
+			paths[id] = prefix + key
+			fmt.Fprintf(w, "%s%s: \"%s\" {\n", indent, key, sanitizeD2(task.Title))
+			fmt.Fprintf(w, "%s  style.stroke: %s\n", indent, d2Color(statuses.Color(task.Status)))
+			if external {
+				fmt.Fprintf(w, "%s  style.stroke-dash: 4\n", indent)
+			}
+			if gopts.NodeLinks && task.ID != "" {
+				fmt.Fprintf(w, "%s  link: \"%s\"\n", indent, sanitizeD2(nodeLink(task, gopts)))
+			}
+			fmt.Fprintf(w, "%s}\n", indent)
+		}
+		if cluster.ID != "" {
+			fmt.Fprintln(w, "}")
+		}
+	}
+
+	fmt.Fprintln(w)
+
+	// Define edges
+	for _, e := range deps.Edges {
+		fmt.Fprintf(w, "%s -> %s\n", paths[e.From], paths[e.To])
+	}
+
+	if gopts.ShowLegend {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "legend: \"Legend\" {")
+		for _, status := range statuses.All() {
+			fmt.Fprintf(w, "  %s: \"%s\" {\n", graphID(string(status.ID)), sanitizeD2(status.Description))
+			fmt.Fprintf(w, "    style.stroke: %s\n", d2Color(statuses.Color(status.ID)))
+			fmt.Fprintln(w, "  }")
+		}
+		if hasExternal(nodes, deps) {
+			fmt.Fprintln(w, "  external: \"Other project\" {")
+			fmt.Fprintln(w, "    style.stroke-dash: 4")
+			fmt.Fprintln(w, "  }")
+		}
+		fmt.Fprintln(w, "}")
+	}
+}
+
+// RenderGraphML renders a dependency graph in GraphML format for tools such
+// as yEd and Gephi.
+func RenderGraphML(w io.Writer, tl *tasks.TaskList, deps DepsResult) {
+	RenderGraphMLWithOptions(w, tl, deps, DefaultGraphOptions())
+}
+
+// RenderGraphMLWithOptions renders a dependency graph in GraphML format
+// using the given graph options.
+func RenderGraphMLWithOptions(w io.Writer, tl *tasks.TaskList, deps DepsResult, gopts GraphOptions) {
+	fmt.Fprintln(w, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintln(w, `<graphml xmlns="http://graphml.graphdrawing.org/xmlns">`)
+	fmt.Fprintln(w, `  <key id="title" for="node" attr.name="title" attr.type="string"/>`)
+	fmt.Fprintln(w, `  <key id="status" for="node" attr.name="status" attr.type="string"/>`)
+	fmt.Fprintln(w, `  <key id="phase" for="node" attr.name="phase" attr.type="int"/>`)
+	fmt.Fprintln(w, `  <key id="area" for="node" attr.name="area" attr.type="string"/>`)
+	fmt.Fprintln(w, `  <key id="type" for="node" attr.name="type" attr.type="string"/>`)
+	fmt.Fprintln(w, `  <key id="color" for="node" attr.name="color" attr.type="string"/>`)
+	fmt.Fprintln(w, `  <key id="url" for="node" attr.name="url" attr.type="string"/>`)
+	fmt.Fprintln(w, `  <key id="external" for="node" attr.name="external" attr.type="boolean"/>`)
+	fmt.Fprintf(w, "  <graph id=\"%s\" edgedefault=\"directed\">\n", xmlText(tl.Project))
+
+	statuses := tl.StatusRegistry()
+	for _, node := range BuildGraphDocument(tl, deps, gopts).Nodes {
+		fmt.Fprintf(w, "    <node id=\"%s\">\n", xmlText(node.ID))
+		fmt.Fprintf(w, "      <data key=\"title\">%s</data>\n", xmlText(node.Title))
+		fmt.Fprintf(w, "      <data key=\"status\">%s</data>\n", xmlText(string(node.Status)))
+		fmt.Fprintf(w, "      <data key=\"phase\">%d</data>\n", node.Phase)
+		if node.Area != "" {
+			fmt.Fprintf(w, "      <data key=\"area\">%s</data>\n", xmlText(node.Area))
+		}
+		if node.Type != "" {
+			fmt.Fprintf(w, "      <data key=\"type\">%s</data>\n", xmlText(node.Type))
+		}
+		fmt.Fprintf(w, "      <data key=\"color\">%s</data>\n", xmlText(statuses.Color(node.Status)))
+		if node.Link != "" {
+			fmt.Fprintf(w, "      <data key=\"url\">%s</data>\n", xmlText(node.Link))
+		}
+		if node.External {
+			fmt.Fprintln(w, "      <data key=\"external\">true</data>")
+		}
+		fmt.Fprintln(w, "    </node>")
+	}
+
+	for i, e := range deps.Edges {
+		fmt.Fprintf(w, "    <edge id=\"e%d\" source=\"%s\" target=\"%s\"/>\n", i, xmlText(e.From), xmlText(e.To))
+	}
+
+	fmt.Fprintln(w, "  </graph>")
+	fmt.Fprintln(w, "</graphml>")
+}
+
+// plantUMLColor returns a color for use after PlantUML's "#" prefix.
+func plantUMLColor(color string) string {
+	return strings.TrimPrefix(color, "#")
+}
+
+// d2Color quotes hex colors, which D2 would otherwise read as comments.
+func d2Color(color string) string {
+	if strings.HasPrefix(color, "#") {
+		return "\"" + color + "\""
+	}
+	return color
+}
+
+// sanitizePlantUML escapes special characters for PlantUML labels.
+func sanitizePlantUML(s string) string {
+	return strings.ReplaceAll(s, "\"", "'")
+}
+
+// sanitizeD2 escapes special characters for D2 quoted strings.
+func sanitizeD2(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	return strings.ReplaceAll(s, "\"", "\\\"")
+}
+
+// xmlText escapes a string for use in XML text and attribute values.
+func xmlText(s string) string {
+	var sb strings.Builder
+	_ = xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
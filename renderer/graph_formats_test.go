@@ -0,0 +1,200 @@
+package renderer
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/grokify/structured-tasks/tasks"
+)
+
+func graphFormatsTaskList() *tasks.TaskList {
+	return &tasks.TaskList{
+		Project: "test-project",
+		Areas: []tasks.Area{
+			{ID: "core", Name: "Core"},
+		},
+		Tasks: []tasks.Task{
+			{ID: "task-1", Title: "First \"Task\"", Status: tasks.StatusCompleted, Area: "core", Phase: 1, Type: "Added"},
+			{ID: "task-2", Title: "Second & Task", Status: tasks.StatusPlanned, Phase: 2, DependsOn: []string{"task-1"}},
+			{ID: "task-3", Title: "Isolated", Status: tasks.StatusFuture},
+		},
+	}
+}
+
+func TestRenderGraphJSON(t *testing.T) {
+	tl := graphFormatsTaskList()
+	deps := BuildDependencyGraph(tl)
+
+	var buf bytes.Buffer
+	if err := RenderGraphJSONWithOptions(&buf, tl, deps, DefaultGraphOptions().WithIsolated(true)); err != nil {
+		t.Fatalf("RenderGraphJSONWithOptions failed: %v", err)
+	}
+
+	var doc GraphDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if doc.Project != "test-project" {
+		t.Errorf("expected project test-project, got %s", doc.Project)
+	}
+	if len(doc.Nodes) != 3 {
+		t.Fatalf("expected 3 nodes, got %d", len(doc.Nodes))
+	}
+	first := doc.Nodes[0]
+	if first.ID != "task-1" || first.Status != tasks.StatusCompleted || first.Phase != 1 || first.Area != "core" || first.Type != "Added" {
+		t.Errorf("unexpected node attributes: %+v", first)
+	}
+	if len(doc.Edges) != 1 || doc.Edges[0] != (Edge{From: "task-1", To: "task-2"}) {
+		t.Errorf("unexpected edges: %+v", doc.Edges)
+	}
+	if !strings.Contains(buf.String(), `"from": "task-1"`) {
+		t.Error("expected lowercase edge keys")
+	}
+}
+
+func TestRenderGraphJSONEmpty(t *testing.T) {
+	tl := &tasks.TaskList{Project: "empty"}
+	var buf bytes.Buffer
+	if err := RenderGraphJSON(&buf, tl, BuildDependencyGraph(tl)); err != nil {
+		t.Fatalf("RenderGraphJSON failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"nodes": []`) || !strings.Contains(buf.String(), `"edges": []`) {
+		t.Errorf("expected empty arrays, got:\n%s", buf.String())
+	}
+}
+
+func TestRenderPlantUML(t *testing.T) {
+	tl := graphFormatsTaskList()
+	deps := BuildDependencyGraph(tl)
+
+	var buf bytes.Buffer
+	RenderPlantUMLWithOptions(&buf, tl, deps, DefaultGraphOptions().WithClusterBy(ClusterByArea).WithDirection(DirectionLR).WithLegend(true))
+	output := buf.String()
+
+	expected := []string{
+		"@startuml",
+		"left to right direction",
+		`package "Core" {`,
+		`rectangle "First 'Task'" as task_1 #green`,
+		"task_1 --> task_2",
+		"legend right",
+		"@enduml",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestRenderD2(t *testing.T) {
+	tl := graphFormatsTaskList()
+	deps := BuildDependencyGraph(tl)
+
+	t.Run("flat", func(t *testing.T) {
+		var buf bytes.Buffer
+		RenderD2(&buf, tl, deps)
+		output := buf.String()
+		for _, want := range []string{"direction: down", `task_1: "First \"Task\"" {`, "style.stroke: green", "task_1 -> task_2"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("expected %q in output, got:\n%s", want, output)
+			}
+		}
+	})
+
+	t.Run("clustered with links", func(t *testing.T) {
+		var buf bytes.Buffer
+		RenderD2WithOptions(&buf, tl, deps, DefaultGraphOptions().WithClusterBy(ClusterByPhase).WithNodeLinks("https://example.com/{id}"))
+		output := buf.String()
+		for _, want := range []string{`phase_1: "Phase 1" {`, "phase_1.task_1 -> phase_2.task_2", `link: "https://example.com/task-1"`} {
+			if !strings.Contains(output, want) {
+				t.Errorf("expected %q in output, got:\n%s", want, output)
+			}
+		}
+	})
+}
+
+func TestRenderGraphML(t *testing.T) {
+	tl := graphFormatsTaskList()
+	deps := BuildDependencyGraph(tl)
+
+	var buf bytes.Buffer
+	RenderGraphML(&buf, tl, deps)
+	output := buf.String()
+
+	// Verify the document is well-formed XML
+	dec := xml.NewDecoder(strings.NewReader(output))
+	for {
+		if _, err := dec.Token(); err != nil {
+			if err != io.EOF {
+				t.Fatalf("output is not well-formed XML: %v", err)
+			}
+			break
+		}
+	}
+
+	for _, want := range []string{
+		`<node id="task-1">`,
+		`<data key="title">Second &amp; Task</data>`,
+		`<data key="status">completed</data>`,
+		`<edge id="e0" source="task-1" target="task-2"/>`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "task-3") {
+		t.Error("expected isolated task to be omitted by default")
+	}
+}
+
+func TestGraphKeyCollisions(t *testing.T) {
+	// task-1 and task_1 sanitize to the same key, and task_1_2 holds the
+	// first suffix that would otherwise be chosen.
+	tl := &tasks.TaskList{
+		Project: "test-project",
+		Tasks: []tasks.Task{
+			{ID: "task-1", Title: "Dash", Status: tasks.StatusCompleted},
+			{ID: "task_1", Title: "Underscore", Status: tasks.StatusPlanned, DependsOn: []string{"task-1"}},
+			{ID: "task_1_2", Title: "Suffixed", Status: tasks.StatusPlanned, DependsOn: []string{"task_1"}},
+		},
+	}
+	deps := BuildDependencyGraph(tl)
+
+	var buf bytes.Buffer
+	RenderPlantUML(&buf, tl, deps)
+	output := buf.String()
+	for _, want := range []string{`"Dash" as task_1 `, `"Underscore" as task_1_3 `, `"Suffixed" as task_1_2 `, "task_1 --> task_1_3", "task_1_3 --> task_1_2"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("PlantUML: expected %q in output, got:\n%s", want, output)
+		}
+	}
+
+	buf.Reset()
+	RenderD2(&buf, tl, deps)
+	output = buf.String()
+	for _, want := range []string{`task_1: "Dash" {`, `task_1_3: "Underscore" {`, "task_1 -> task_1_3", "task_1_3 -> task_1_2"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("D2: expected %q in output, got:\n%s", want, output)
+		}
+	}
+
+	// Mermaid keeps safe IDs as is, so only unsafe ones are renamed
+	tl.Tasks[0].ID = "pkg/a"
+	tl.Tasks[1].ID = "pkg_a"
+	tl.Tasks[1].DependsOn = []string{"pkg/a"}
+	tl.Tasks[2].DependsOn = []string{"pkg_a"}
+	deps = BuildDependencyGraph(tl)
+	buf.Reset()
+	RenderMermaid(&buf, tl, deps)
+	output = buf.String()
+	for _, want := range []string{"pkg_a --> pkg_a_2", "pkg_a_2 --> task_1_2"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Mermaid: expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
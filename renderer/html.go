@@ -0,0 +1,318 @@
+package renderer
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+
+	"github.com/grokify/structured-changelog/changelog"
+	"github.com/grokify/structured-tasks/tasks"
+)
+
+// taskGroup is a titled group of tasks.
+type taskGroup struct {
+	Title string
+	Tasks []tasks.Task
+}
+
+// taskGroups returns the task groups for opts.GroupBy in the order the
+// Markdown renderer uses.
+func taskGroups(tl *tasks.TaskList, opts Options) []taskGroup {
+	var groups []taskGroup
+	add := func(title string, group []tasks.Task) {
+		if len(group) > 0 {
+			groups = append(groups, taskGroup{Title: title, Tasks: group})
+		}
+	}
+
+	switch opts.GroupBy {
+	case GroupByPhase:
+		byPhase := tl.TasksByPhase()
+		for _, phase := range tl.PhaseNumbers() {
+			add(tl.PhaseName(phase), byPhase[phase])
+		}
+		add(tl.PhaseName(0), byPhase[0])
+	case GroupByStatus:
+		byStatus := tl.TasksByStatus()
+		statuses := statusRegistry(tl, opts)
+		legend := statuses.Legend()
+		for _, status := range statuses.Order() {
+			if isHiddenStatus(statuses, status, opts) {
+				continue
+			}
+			add(legend[status].Description, byStatus[status])
+		}
+	case GroupByType:
+		byType := tl.TasksByType()
+		for _, ct := range changelog.DefaultRegistry.All() {
+			add(ct.Name, byType[ct.Name])
+		}
+		add("Other", byType["_unspecified"])
+	case GroupByVersion:
+		groups = versionGroups(tl, opts)
+	case GroupByMilestone:
+		for _, g := range milestoneGroups(tl) {
+			add(g.Title, g.Tasks)
+		}
+	case GroupByLabel:
+		groups = labelGroups(tl)
+	default:
+		byArea := tl.TasksByArea()
+		for _, area := range tl.Areas {
+			add(area.Name, byArea[area.ID])
+		}
+		add("Other", byArea["_unspecified"])
+	}
+	return groups
+}
+
+// htmlTask is the view model for a task in the HTML template.
+type htmlTask struct {
+	tasks.Task
+	Slug      string
+	Status    string
+	Complete  bool
+	Cancelled bool
+	Shipped   string
+	WaitsOn   string
+	Links     []htmlLink
+	Labels    []htmlLabel
+	Reason    string // Why the task is blocked or was cancelled
+}
+
+type htmlDependencies struct {
+	External []htmlExternal
+	Internal []tasks.InternalDependency
+}
+
+type htmlExternal struct {
+	tasks.ExternalDependency
+	Status  string
+	Waiting []htmlTask
+}
+
+type htmlGroup struct {
+	Title string
+	Slug  string
+	Tasks []htmlTask
+}
+
+var htmlFuncs = template.FuncMap{
+	"paragraphs": func(s string) []string {
+		var paras []string
+		for _, p := range strings.Split(strings.TrimSpace(s), "\n\n") {
+			if p = strings.TrimSpace(p); p != "" {
+				paras = append(paras, p)
+			}
+		}
+		return paras
+	},
+}
+
+var taskListHTML = template.Must(template.New("tasks").Funcs(htmlFuncs).Parse(`
+{{- define "content"}}
+{{- range .}}
+{{- if eq .Type "text"}}
+{{- range paragraphs .Value}}
+<p>{{.}}</p>
+{{- end}}
+{{- else if eq .Type "code"}}
+<pre><code{{if .Language}} class="language-{{.Language}}"{{end}}>{{.Value}}</code></pre>
+{{- else if eq .Type "diagram"}}
+<pre{{if eq .Format "mermaid"}} class="mermaid"{{end}}>{{.Value}}</pre>
+{{- else if eq .Type "blockquote"}}
+<blockquote>
+{{- range paragraphs .Value}}
+<p>{{.}}</p>
+{{- end}}
+</blockquote>
+{{- else if eq .Type "list"}}
+<ul>
+{{- range .Items}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- else if eq .Type "table"}}
+<table>
+<tr>{{range .Headers}}<th>{{.}}</th>{{end}}</tr>
+{{- range .Rows}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+{{- end}}
+
+{{- define "sections"}}
+{{- range .}}
+
+<section id="{{.ID}}">
+<h2>{{.Title}}</h2>
+{{- template "content" .Content}}
+</section>
+{{- end}}
+{{- end -}}
+
+<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Task List{{if .Project}}: {{.Project}}{{end}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem; color: #24292f; }
+table { border-collapse: collapse; margin-bottom: 1.5rem; }
+th, td { border: 1px solid #d0d7de; padding: 0.3rem 0.6rem; text-align: left; }
+th { background: #f6f8fa; }
+pre { background: #f6f8fa; padding: 0.8rem; overflow-x: auto; }
+blockquote { border-left: 0.25rem solid #d0d7de; margin-left: 0; padding-left: 1rem; color: #57606a; }
+.task.complete h3 { color: #57606a; }
+.shipped { color: #57606a; font-style: italic; }
+.reason { color: #cf222e; }
+.label { border: 1px solid #d0d7de; border-radius: 1em; padding: 0 0.5em; margin-right: 0.3em; font-size: 0.85em; }
+.links { font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1 id="task-list">Task List</h1>
+{{- if .Project}}
+<p><strong>Project:</strong> {{.Project}}</p>
+{{- end}}
+{{- template "sections" .Before}}
+{{- range .Groups}}
+
+<section id="{{.Slug}}">
+<h2>{{.Title}}</h2>
+{{- range .Tasks}}
+<div class="task{{if .Complete}} complete{{end}}" id="{{.Slug}}">
+<h3>{{.Status}} {{if .Cancelled}}<s>{{.Title}}</s>{{else}}{{.Title}}{{end}}</h3>
+{{- if .Description}}
+<p>{{.Description}}</p>
+{{- end}}
+{{- template "content" .Content}}
+{{- if .Shipped}}
+<p class="shipped">Shipped in {{.Shipped}}</p>
+{{- end}}
+{{- if .WaitsOn}}
+<p class="waits-on">{{.WaitsOn}}</p>
+{{- end}}
+{{- if .Links}}
+<p class="links">{{range $i, $l := .Links}}{{if $i}} · {{end}}{{$l.Icon}} <a href="{{$l.URL}}">{{$l.Title}}</a>{{end}}</p>
+{{- end}}
+{{- if .Labels}}
+<p class="labels">{{range .Labels}}<span class="label"{{if .Color}} style="border-color: {{.Color}}"{{end}}>{{.Name}}</span>{{end}}</p>
+{{- end}}
+{{- if .Reason}}
+<p class="reason">{{.Reason}}</p>
+{{- end}}
+{{- if .Subtasks}}
+<ul>
+{{- range .Subtasks}}
+<li><input type="checkbox" disabled{{if .Completed}} checked{{end}}> {{.Description}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</div>
+{{- end}}
+</section>
+{{- end}}
+{{- with .Dependencies}}
+
+<section id="dependencies">
+<h2>Dependencies</h2>
+{{- if .External}}
+<h3>External</h3>
+<table>
+<tr><th>Dependency</th><th>Status</th><th>Note</th><th>Waiting Tasks</th></tr>
+{{- range .External}}
+<tr><td>{{.Name}}</td><td>{{.Status}}</td><td>{{.Note}}</td><td>{{range $i, $t := .Waiting}}{{if $i}}, {{end}}<a href="#{{$t.Slug}}">{{$t.Title}}</a>{{end}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Internal}}
+<h3>Internal</h3>
+<ul>
+{{- range .Internal}}
+<li><code>{{.Package}}</code>{{if .DependsOn}} → {{range $i, $p := .DependsOn}}{{if $i}}, {{end}}<code>{{$p}}</code>{{end}}{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</section>
+{{- end}}
+{{- template "sections" .After}}
+</body>
+</html>
+`))
+
+// RenderHTML renders a task list as a standalone HTML document, grouped as
+// for Render, with sections, content blocks and dependencies. Archived tasks
+// and tasks not matching opts.Filter are omitted. Text blocks are rendered as
+// plain paragraphs; Markdown markup in them is not interpreted.
+func RenderHTML(tl *tasks.TaskList, opts Options) (string, error) {
+	tl = selectTasks(tl, opts)
+	statuses := tl.StatusRegistry()
+	opts.statuses = statuses
+	legend := statuses.Legend()
+
+	var groups []htmlGroup
+	for _, g := range taskGroups(tl, opts) {
+		group := htmlGroup{Title: g.Title, Slug: slugify(g.Title)}
+		for _, task := range sortTasks(g.Tasks, tl, opts) {
+			if isHidden(statuses, task, opts) {
+				continue
+			}
+			ht := htmlTask{Task: task, Slug: taskSlug(task), Complete: isTaskComplete(statuses, task)}
+			if opts.UseEmoji {
+				ht.Status = legend[task.Status].Emoji
+			}
+			if task.Version != "" && statuses.IsDone(task.Status) {
+				ht.Shipped = versionLabel(task.Version)
+			}
+			if len(task.WaitsOn) > 0 {
+				ht.WaitsOn = waitsOnLabel(task, tl, opts)
+			}
+			if len(task.Links) > 0 {
+				ht.Links = htmlLinks(task, opts)
+			}
+			if len(task.Labels) > 0 {
+				ht.Labels = htmlLabels(task, tl)
+			}
+			switch {
+			case statuses.IsBlocked(task.Status):
+				ht.Reason = blockedLabel(task, tl)
+			case statuses.IsCancelled(task.Status):
+				ht.Cancelled = true
+				ht.Reason = cancelledLabel(task)
+			}
+			group.Tasks = append(group.Tasks, ht)
+		}
+		if len(group.Tasks) > 0 {
+			groups = append(groups, group)
+		}
+	}
+
+	var deps *htmlDependencies
+	if opts.ShowDependencies && hasDependencies(tl) {
+		deps = &htmlDependencies{Internal: tl.Dependencies.Internal}
+		for _, d := range tl.Dependencies.External {
+			ext := htmlExternal{ExternalDependency: d, Status: externalStatusLabel(d.Status, opts)}
+			for _, task := range tl.TasksWaitingOn(d.Name) {
+				ext.Waiting = append(ext.Waiting, htmlTask{Task: task, Slug: taskSlug(task)})
+			}
+			deps.External = append(deps.External, ext)
+		}
+	}
+
+	data := struct {
+		Project      string
+		Before       []tasks.Section
+		Groups       []htmlGroup
+		Dependencies *htmlDependencies
+		After        []tasks.Section
+	}{tl.Project, tl.SectionsBefore(), groups, deps, tl.SectionsAfter()}
+
+	var buf bytes.Buffer
+	if err := taskListHTML.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
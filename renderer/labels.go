@@ -0,0 +1,59 @@
+package renderer
+
+import (
+	"strings"
+
+	"github.com/grokify/structured-tasks/tasks"
+)
+
+// labelGroups returns one group per label with tasks, declared labels first,
+// followed by unlabeled tasks. Tasks with several labels appear in each of
+// their groups.
+func labelGroups(tl *tasks.TaskList) []taskGroup {
+	byLabel := tl.TasksByLabel()
+	var groups []taskGroup
+	for _, label := range tl.LabelIDs() {
+		if len(byLabel[label]) > 0 {
+			groups = append(groups, taskGroup{Title: tl.LabelName(label), Tasks: byLabel[label]})
+		}
+	}
+	if unlabeled := byLabel["_unspecified"]; len(unlabeled) > 0 {
+		groups = append(groups, taskGroup{Title: "Unlabeled", Tasks: unlabeled})
+	}
+	return groups
+}
+
+func renderByLabel(sb *strings.Builder, tl *tasks.TaskList, opts Options) {
+	for _, group := range labelGroups(tl) {
+		renderSectionHeading(sb, group.Title, tl.Project, opts)
+		renderTasks(sb, group.Tasks, tl, opts)
+
+		if opts.HorizontalRules {
+			sb.WriteString("---\n\n")
+		}
+	}
+}
+
+// labelsLabel lists the display names of a task's labels.
+func labelsLabel(task tasks.Task, tl *tasks.TaskList) string {
+	names := make([]string, len(task.Labels))
+	for i, label := range task.Labels {
+		names[i] = "`" + tl.LabelName(label) + "`"
+	}
+	return "Labels: " + strings.Join(names, " ")
+}
+
+// htmlLabel is the view model for a task label in the HTML template.
+type htmlLabel struct {
+	Name  string
+	Color string
+}
+
+func htmlLabels(task tasks.Task, tl *tasks.TaskList) []htmlLabel {
+	labels := make([]htmlLabel, len(task.Labels))
+	for i, label := range task.Labels {
+		def, _ := tl.Label(label)
+		labels[i] = htmlLabel{Name: tl.LabelName(label), Color: def.Color}
+	}
+	return labels
+}
@@ -0,0 +1,94 @@
+package renderer
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/grokify/structured-tasks/tasks"
+)
+
+// linkKindLabels maps link kinds to an icon and a name.
+var linkKindLabels = map[tasks.LinkKind][2]string{
+	tasks.LinkIssue: {"🎫", "Issue"},
+	tasks.LinkPR:    {"🔀", "PR"},
+	tasks.LinkDoc:   {"📄", "Doc"},
+	tasks.LinkADR:   {"📐", "ADR"},
+	tasks.LinkOther: {"🔗", "Link"},
+}
+
+// linkIcon returns the icon of a link kind if emoji are enabled, otherwise
+// its name followed by a colon.
+func linkIcon(kind tasks.LinkKind, opts Options) string {
+	label, ok := linkKindLabels[kind]
+	if !ok {
+		label = linkKindLabels[tasks.LinkOther]
+	}
+	if opts.UseEmoji {
+		return label[0]
+	}
+	return label[1] + ":"
+}
+
+// linkTitle returns the title of a link. Untitled GitHub-style issue and
+// pull request links are shown as "#N", other untitled links as their URL.
+func linkTitle(l tasks.Link) string {
+	if l.Title != "" {
+		return l.Title
+	}
+	dir, num := path.Split(strings.TrimSuffix(l.URL, "/"))
+	if (strings.HasSuffix(dir, "/issues/") || strings.HasSuffix(dir, "/pull/")) && num != "" && strings.Trim(num, "0123456789") == "" {
+		return "#" + num
+	}
+	return l.URL
+}
+
+// linksLabel renders a task's links as a row of icon-prefixed Markdown links.
+func linksLabel(task tasks.Task, opts Options) string {
+	links := make([]string, len(task.Links))
+	for i, l := range task.Links {
+		links[i] = linkIcon(l.Kind, opts) + " " + markdownLink(linkTitle(l), l.URL)
+	}
+	return strings.Join(links, " · ")
+}
+
+// issueCell returns the overview table cell linking to a task's primary
+// issue, or "-" if it has none.
+func issueCell(task tasks.Task) string {
+	issue, ok := task.PrimaryIssue()
+	if !ok {
+		return "-"
+	}
+	return markdownLink(linkTitle(issue), issue.URL)
+}
+
+var (
+	// markdownLinkText escapes the characters that would end a link's text
+	// or, in tables, its cell.
+	markdownLinkText = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`, "|", `\|`)
+
+	// markdownLinkURL encodes the characters not allowed in a URL in angle
+	// brackets.
+	markdownLinkURL = strings.NewReplacer("<", "%3C", ">", "%3E")
+)
+
+// markdownLink returns a Markdown link. The URL is written in angle
+// brackets so that spaces and parentheses in it do not end the link.
+func markdownLink(title, url string) string {
+	return fmt.Sprintf("[%s](<%s>)", markdownLinkText.Replace(title), markdownLinkURL.Replace(url))
+}
+
+// htmlLink is the view model for a task link in the HTML template.
+type htmlLink struct {
+	Icon  string
+	Title string
+	URL   string
+}
+
+func htmlLinks(task tasks.Task, opts Options) []htmlLink {
+	links := make([]htmlLink, len(task.Links))
+	for i, l := range task.Links {
+		links[i] = htmlLink{Icon: linkIcon(l.Kind, opts), Title: linkTitle(l), URL: l.URL}
+	}
+	return links
+}
@@ -1,7 +1,9 @@
 package renderer
 
 import (
+	"cmp"
 	"fmt"
+	"math"
 	"os"
 	"regexp"
 	"sort"
@@ -55,8 +57,11 @@ type tocEntry struct {
 	Tasks     []tocEntry
 }
 
-// Render generates Markdown from a TaskList.
+// Render generates Markdown from a TaskList. Archived tasks and tasks not
+// matching opts.Filter are omitted.
 func Render(tl *tasks.TaskList, opts Options) string {
+	tl = selectTasks(tl, opts)
+	opts.statuses = tl.StatusRegistry()
 	var sb strings.Builder
 
 	// Title
@@ -76,6 +81,14 @@ func Render(tl *tasks.TaskList, opts Options) string {
 		sb.WriteString(intro + "\n\n")
 	}
 
+	// Milestone summary table
+	if opts.ShowMilestoneTable && len(tl.Milestones) > 0 {
+		renderMilestoneTable(&sb, tl, opts)
+		if opts.HorizontalRules {
+			sb.WriteString("---\n\n")
+		}
+	}
+
 	// Overview table
 	if opts.ShowOverviewTable {
 		renderOverviewTable(&sb, tl, opts)
@@ -94,12 +107,14 @@ func Render(tl *tasks.TaskList, opts Options) string {
 
 	// Legend
 	if opts.ShowLegend {
-		renderLegend(&sb, tl)
+		renderLegend(&sb, tl, opts)
 		if opts.HorizontalRules {
 			sb.WriteString("---\n\n")
 		}
 	}
 
+	renderSections(&sb, tl.SectionsBefore(), tl, opts)
+
 	// Main content grouped by strategy
 	switch opts.GroupBy {
 	case GroupByPhase:
@@ -108,69 +123,133 @@ func Render(tl *tasks.TaskList, opts Options) string {
 		renderByStatus(&sb, tl, opts)
 	case GroupByType:
 		renderByType(&sb, tl, opts)
+	case GroupByVersion:
+		renderByVersion(&sb, tl, opts)
+	case GroupByMilestone:
+		renderByMilestone(&sb, tl, opts)
+	case GroupByLabel:
+		renderByLabel(&sb, tl, opts)
 	default:
 		renderByArea(&sb, tl, opts)
 	}
 
+	if opts.ShowDependencies && hasDependencies(tl) {
+		if opts.HorizontalRules && !strings.HasSuffix(sb.String(), "---\n\n") {
+			sb.WriteString("---\n\n")
+		}
+		renderDependencies(&sb, tl, opts)
+	}
+
+	if after := tl.SectionsAfter(); len(after) > 0 {
+		if opts.HorizontalRules && !strings.HasSuffix(sb.String(), "---\n\n") {
+			sb.WriteString("---\n\n")
+		}
+		renderSections(&sb, after, tl, opts)
+	}
+
 	return strings.TrimRight(sb.String(), "\n") + "\n"
 }
 
+// selectTasks returns a shallow copy of tl without archived tasks and tasks
+// not matching opts.Filter, or tl itself if no tasks are left out.
+func selectTasks(tl *tasks.TaskList, opts Options) *tasks.TaskList {
+	keep := func(task tasks.Task) bool {
+		return !task.Archived && opts.Filter.Match(task)
+	}
+	for _, task := range tl.Tasks {
+		if !keep(task) {
+			return tl.Filter(keep)
+		}
+	}
+	return tl
+}
+
 // RenderToFile writes rendered Markdown to a file.
 func RenderToFile(path string, tl *tasks.TaskList, opts Options) error {
 	content := Render(tl, opts)
 	return os.WriteFile(path, []byte(content), 0600)
 }
 
-func renderLegend(sb *strings.Builder, tl *tasks.TaskList) {
+func renderLegend(sb *strings.Builder, tl *tasks.TaskList, opts Options) {
 	sb.WriteString("## Legend\n\n")
 	sb.WriteString("| Status | Description |\n")
 	sb.WriteString("|--------|-------------|\n")
-	legend := tl.GetLegend()
-	for _, status := range tasks.StatusOrder() {
-		if entry, ok := legend[status]; ok {
-			fmt.Fprintf(sb, "| %s | %s |\n", entry.Emoji, entry.Description)
-		}
+	for _, status := range statusRegistry(tl, opts).All() {
+		fmt.Fprintf(sb, "| %s | %s |\n", status.Emoji, status.Description)
 	}
 	sb.WriteString("\n")
 }
 
-// statusSortOrder returns the sort order for a status.
-// In-progress first, then planned, then future, then completed at the bottom.
-func statusSortOrder(s tasks.Status) int {
-	switch s {
-	case tasks.StatusInProgress:
-		return 0
-	case tasks.StatusPlanned:
-		return 1
-	case tasks.StatusFuture:
-		return 2
-	case tasks.StatusCompleted:
-		return 3
-	default:
-		return 4
+// isHidden returns true if a task is omitted because its status counts as
+// done and completed tasks are hidden, or as cancelled and cancelled tasks
+// are hidden.
+func isHidden(statuses *tasks.StatusRegistry, task tasks.Task, opts Options) bool {
+	return isHiddenStatus(statuses, task.Status, opts)
+}
+
+// statusRegistry returns the status registry of tl, built once per render
+// by Render and RenderHTML and passed down in opts.
+func statusRegistry(tl *tasks.TaskList, opts Options) *tasks.StatusRegistry {
+	if opts.statuses != nil {
+		return opts.statuses
 	}
+	return tl.StatusRegistry()
 }
 
-// isPhaseComplete returns true if all tasks in a phase are completed.
-func isPhaseComplete(tl *tasks.TaskList, phase int) bool {
-	hasAny := false
-	for _, task := range tl.Tasks {
-		if task.Phase == phase {
-			hasAny = true
-			if task.Status != tasks.StatusCompleted {
-				return false
-			}
+// statusEmoji returns the emoji of a status, or "" if it is unknown.
+func statusEmoji(statuses *tasks.StatusRegistry, status tasks.Status) string {
+	def, _ := statuses.Lookup(status)
+	return def.Emoji
+}
+
+// isHiddenStatus returns true if tasks with the status are omitted.
+func isHiddenStatus(statuses *tasks.StatusRegistry, status tasks.Status, opts Options) bool {
+	return !opts.ShowCompleted && statuses.IsDone(status) ||
+		!opts.ShowCancelled && statuses.IsCancelled(status)
+}
+
+// blockedLabel describes why a blocked task is blocked and by what. Blockers
+// that are tasks in the list are shown by title.
+func blockedLabel(task tasks.Task, tl *tasks.TaskList) string {
+	label := "Blocked"
+	if task.BlockedReason != "" {
+		label += ": " + task.BlockedReason
+	}
+	if by := task.BlockedBy; by != "" {
+		if blocker, ok := tl.TaskByID(by); ok {
+			by = blocker.Title
 		}
+		label += " (blocked by " + by + ")"
+	}
+	return label
+}
+
+// cancelledLabel describes why a task was cancelled.
+func cancelledLabel(task tasks.Task) string {
+	if task.CancelledReason == "" {
+		return "Cancelled"
 	}
-	return hasAny
+	return "Cancelled: " + task.CancelledReason
+}
+
+// isPhaseComplete returns true if a phase is completed: whether its declared
+// status, if set, counts as done, otherwise whether all of its tasks do.
+func isPhaseComplete(tl *tasks.TaskList, statuses *tasks.StatusRegistry, phase int) bool {
+	return statuses.IsDone(tl.PhaseStatus(phase))
 }
 
 func renderOverviewTable(sb *strings.Builder, tl *tasks.TaskList, opts Options) {
 	sb.WriteString("## Status\n\n")
-	sb.WriteString("| Phase | Task | Status | Area |\n")
-	sb.WriteString("|-------|------|--------|------|\n")
+	if opts.ShowIssueLinks {
+		sb.WriteString("| Phase | Task | Status | Area | Issue |\n")
+		sb.WriteString("|-------|------|--------|------|-------|\n")
+	} else {
+		sb.WriteString("| Phase | Task | Status | Area |\n")
+		sb.WriteString("|-------|------|--------|------|\n")
+	}
 
-	legend := tl.GetLegend()
+	statuses := statusRegistry(tl, opts)
+	legend := statuses.Legend()
 
 	// Build area name lookup
 	areaNames := make(map[string]string)
@@ -178,13 +257,14 @@ func renderOverviewTable(sb *strings.Builder, tl *tasks.TaskList, opts Options)
 		areaNames[area.ID] = area.Name
 	}
 
-	// Find completed phases and build renumbering map
-	// Incomplete phases are renumbered starting from 1
+	// Find completed phases and build renumbering map. Without declared
+	// phases, incomplete phases are renumbered starting from 1; declared
+	// phases are shown by name.
 	completedPhases := make(map[int]bool)
 	phaseDisplayNum := make(map[int]int)
 	displayNum := 1
 	for _, phase := range tl.PhaseNumbers() {
-		if isPhaseComplete(tl, phase) {
+		if isPhaseComplete(tl, statuses, phase) {
 			completedPhases[phase] = true
 		} else {
 			phaseDisplayNum[phase] = displayNum
@@ -192,30 +272,10 @@ func renderOverviewTable(sb *strings.Builder, tl *tasks.TaskList, opts Options)
 		}
 	}
 
-	// Sort tasks: by phase first, then by status (completed at bottom), then by title
-	sorted := make([]tasks.Task, len(tl.Tasks))
-	copy(sorted, tl.Tasks)
-	sort.Slice(sorted, func(i, j int) bool {
-		// Phase first (0 = unphased goes last)
-		iPhase := sorted[i].Phase
-		jPhase := sorted[j].Phase
-		if iPhase == 0 {
-			iPhase = 9999
-		}
-		if jPhase == 0 {
-			jPhase = 9999
-		}
-		if iPhase != jPhase {
-			return iPhase < jPhase
-		}
-		// Within same phase, sort by status (completed at bottom)
-		iOrder := statusSortOrder(sorted[i].Status)
-		jOrder := statusSortOrder(sorted[j].Status)
-		if iOrder != jOrder {
-			return iOrder < jOrder
-		}
-		// Finally by title
-		return sorted[i].Title < sorted[j].Title
+	// Sort tasks: by phase first (unphased last), then by opts.SortBy
+	sorted := sortTasks(tl.Tasks, tl, opts)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sortPhase(sorted[i].Phase) < sortPhase(sorted[j].Phase)
 	})
 
 	for _, task := range sorted {
@@ -223,8 +283,8 @@ func renderOverviewTable(sb *strings.Builder, tl *tasks.TaskList, opts Options)
 		if completedPhases[task.Phase] {
 			continue
 		}
-		// Skip individual completed tasks if ShowCompleted is false
-		if task.Status == tasks.StatusCompleted && !opts.ShowCompleted {
+		// Skip hidden completed and cancelled tasks
+		if isHidden(statuses, task, opts) {
 			continue
 		}
 
@@ -238,9 +298,13 @@ func renderOverviewTable(sb *strings.Builder, tl *tasks.TaskList, opts Options)
 			status = string(task.Status)
 		}
 
-		// Phase (renumbered for display)
+		// Phase (by name if declared, otherwise renumbered for display)
 		phase := "-"
-		if num, ok := phaseDisplayNum[task.Phase]; ok {
+		if len(tl.Phases) > 0 {
+			if task.Phase > 0 {
+				phase = tl.PhaseName(task.Phase)
+			}
+		} else if num, ok := phaseDisplayNum[task.Phase]; ok {
 			phase = fmt.Sprintf("%d", num)
 		}
 
@@ -253,7 +317,11 @@ func renderOverviewTable(sb *strings.Builder, tl *tasks.TaskList, opts Options)
 		// Task title with anchor link
 		titleLink := fmt.Sprintf("[%s](#%s)", task.Title, taskSlug(task))
 
-		fmt.Fprintf(sb, "| %s | %s | %s | %s |\n", phase, titleLink, status, areaName)
+		if opts.ShowIssueLinks {
+			fmt.Fprintf(sb, "| %s | %s | %s | %s | %s |\n", phase, titleLink, status, areaName, issueCell(task))
+		} else {
+			fmt.Fprintf(sb, "| %s | %s | %s | %s |\n", phase, titleLink, status, areaName)
+		}
 	}
 	sb.WriteString("\n")
 }
@@ -276,9 +344,19 @@ func renderTOC(sb *strings.Builder, tl *tasks.TaskList, opts Options) {
 	sb.WriteString("\n")
 }
 
-// isTaskComplete returns true if a task is considered complete.
-func isTaskComplete(task tasks.Task) bool {
-	if task.Status == tasks.StatusCompleted {
+// sortPhase returns the sort key of a phase number, placing unphased tasks
+// (phase 0) last.
+func sortPhase(phase int) int {
+	if phase == 0 {
+		return math.MaxInt
+	}
+	return phase
+}
+
+// isTaskComplete returns true if a task is considered complete: its status
+// counts as done, or all of its subtasks are checked.
+func isTaskComplete(statuses *tasks.StatusRegistry, task tasks.Task) bool {
+	if statuses.IsDone(task.Status) {
 		return true
 	}
 	if len(task.Subtasks) > 0 {
@@ -293,10 +371,22 @@ func isTaskComplete(task tasks.Task) bool {
 }
 
 // countCompleted counts how many tasks in the slice are complete.
-func countCompleted(taskList []tasks.Task) int {
+func countCompleted(statuses *tasks.StatusRegistry, taskList []tasks.Task) int {
+	count := 0
+	for _, task := range taskList {
+		if isTaskComplete(statuses, task) {
+			count++
+		}
+	}
+	return count
+}
+
+// countActive counts how many tasks in the slice are not cancelled, the
+// denominator for progress.
+func countActive(statuses *tasks.StatusRegistry, taskList []tasks.Task) int {
 	count := 0
 	for _, task := range taskList {
-		if isTaskComplete(task) {
+		if !statuses.IsCancelled(task.Status) {
 			count++
 		}
 	}
@@ -304,6 +394,7 @@ func countCompleted(taskList []tasks.Task) int {
 }
 
 func buildTOCEntries(tl *tasks.TaskList, opts Options) []tocEntry {
+	statuses := statusRegistry(tl, opts)
 	var entries []tocEntry
 
 	switch opts.GroupBy {
@@ -317,10 +408,10 @@ func buildTOCEntries(tl *tasks.TaskList, opts Options) []tocEntry {
 			entry := tocEntry{
 				Title:     area.Name,
 				Slug:      slugify(area.Name),
-				Count:     len(areaTasks),
-				Completed: countCompleted(areaTasks),
+				Count:     countActive(statuses, areaTasks),
+				Completed: countCompleted(statuses, areaTasks),
 			}
-			for i, task := range sortTasks(areaTasks, opts) {
+			for i, task := range sortTasks(areaTasks, tl, opts) {
 				title := task.Title
 				if opts.NumberItems {
 					title = fmt.Sprintf("%d. %s", i+1, task.Title)
@@ -335,23 +426,23 @@ func buildTOCEntries(tl *tasks.TaskList, opts Options) []tocEntry {
 
 	case GroupByStatus:
 		tasksByStatus := tl.TasksByStatus()
-		legend := tl.GetLegend()
-		for _, status := range tasks.StatusOrder() {
+		legend := statuses.Legend()
+		for _, status := range statuses.Order() {
 			statusTasks := tasksByStatus[status]
 			if len(statusTasks) == 0 {
 				continue
 			}
-			if status == tasks.StatusCompleted && !opts.ShowCompleted {
+			if isHiddenStatus(statuses, status, opts) {
 				continue
 			}
 			title := legend[status].Description
 			entry := tocEntry{
 				Title:     title,
 				Slug:      slugify(title),
-				Count:     len(statusTasks),
-				Completed: countCompleted(statusTasks),
+				Count:     countActive(statuses, statusTasks),
+				Completed: countCompleted(statuses, statusTasks),
 			}
-			for i, task := range sortTasks(statusTasks, opts) {
+			for i, task := range sortTasks(statusTasks, tl, opts) {
 				taskTitle := task.Title
 				if opts.NumberItems {
 					taskTitle = fmt.Sprintf("%d. %s", i+1, task.Title)
@@ -372,14 +463,14 @@ func buildTOCEntries(tl *tasks.TaskList, opts Options) []tocEntry {
 			if len(phaseTasks) == 0 {
 				continue
 			}
-			title := fmt.Sprintf("Phase %d", phase)
+			title := tl.PhaseName(phase)
 			entry := tocEntry{
 				Title:     title,
 				Slug:      slugify(title),
-				Count:     len(phaseTasks),
-				Completed: countCompleted(phaseTasks),
+				Count:     countActive(statuses, phaseTasks),
+				Completed: countCompleted(statuses, phaseTasks),
 			}
-			for i, task := range sortTasks(phaseTasks, opts) {
+			for i, task := range sortTasks(phaseTasks, tl, opts) {
 				taskTitle := task.Title
 				if opts.NumberItems {
 					taskTitle = fmt.Sprintf("%d. %s", i+1, task.Title)
@@ -396,8 +487,8 @@ func buildTOCEntries(tl *tasks.TaskList, opts Options) []tocEntry {
 			entry := tocEntry{
 				Title:     "Unphased",
 				Slug:      "unphased",
-				Count:     len(phaseTasks),
-				Completed: countCompleted(phaseTasks),
+				Count:     countActive(statuses, phaseTasks),
+				Completed: countCompleted(statuses, phaseTasks),
 			}
 			entries = append(entries, entry)
 		}
@@ -414,10 +505,73 @@ func buildTOCEntries(tl *tasks.TaskList, opts Options) []tocEntry {
 			entry := tocEntry{
 				Title:     ct.Name,
 				Slug:      slugify(ct.Name),
-				Count:     len(typeTasks),
-				Completed: countCompleted(typeTasks),
+				Count:     countActive(statuses, typeTasks),
+				Completed: countCompleted(statuses, typeTasks),
+			}
+			for i, task := range sortTasks(typeTasks, tl, opts) {
+				taskTitle := task.Title
+				if opts.NumberItems {
+					taskTitle = fmt.Sprintf("%d. %s", i+1, task.Title)
+				}
+				entry.Tasks = append(entry.Tasks, tocEntry{
+					Title: taskTitle,
+					Slug:  taskSlug(task),
+				})
+			}
+			entries = append(entries, entry)
+		}
+
+	case GroupByMilestone:
+		for _, group := range milestoneGroups(tl) {
+			entry := tocEntry{
+				Title:     group.Title,
+				Slug:      slugify(group.Title),
+				Count:     countActive(statuses, group.Tasks),
+				Completed: countCompleted(statuses, group.Tasks),
+			}
+			for i, task := range sortTasks(group.Tasks, tl, opts) {
+				taskTitle := task.Title
+				if opts.NumberItems {
+					taskTitle = fmt.Sprintf("%d. %s", i+1, task.Title)
+				}
+				entry.Tasks = append(entry.Tasks, tocEntry{
+					Title: taskTitle,
+					Slug:  taskSlug(task),
+				})
+			}
+			entries = append(entries, entry)
+		}
+
+	case GroupByLabel:
+		for _, group := range labelGroups(tl) {
+			entry := tocEntry{
+				Title:     group.Title,
+				Slug:      slugify(group.Title),
+				Count:     countActive(statuses, group.Tasks),
+				Completed: countCompleted(statuses, group.Tasks),
+			}
+			for i, task := range sortTasks(group.Tasks, tl, opts) {
+				taskTitle := task.Title
+				if opts.NumberItems {
+					taskTitle = fmt.Sprintf("%d. %s", i+1, task.Title)
+				}
+				entry.Tasks = append(entry.Tasks, tocEntry{
+					Title: taskTitle,
+					Slug:  taskSlug(task),
+				})
+			}
+			entries = append(entries, entry)
+		}
+
+	case GroupByVersion:
+		for _, group := range versionGroups(tl, opts) {
+			entry := tocEntry{
+				Title:     group.Title,
+				Slug:      slugify(group.Title),
+				Count:     countActive(statuses, group.Tasks),
+				Completed: countCompleted(statuses, group.Tasks),
 			}
-			for i, task := range sortTasks(typeTasks, opts) {
+			for i, task := range sortTasks(group.Tasks, tl, opts) {
 				taskTitle := task.Title
 				if opts.NumberItems {
 					taskTitle = fmt.Sprintf("%d. %s", i+1, task.Title)
@@ -434,23 +588,58 @@ func buildTOCEntries(tl *tasks.TaskList, opts Options) []tocEntry {
 	return entries
 }
 
-// sortTasks returns a sorted copy of tasks for consistent ordering.
-// Completed tasks are placed at the bottom within their group.
-func sortTasks(taskList []tasks.Task, _ Options) []tasks.Task {
+// sortTasks returns a sorted copy of tasks for consistent ordering by
+// opts.SortBy. Ties keep their order in taskList.
+func sortTasks(taskList []tasks.Task, tl *tasks.TaskList, opts Options) []tasks.Task {
 	sorted := make([]tasks.Task, len(taskList))
 	copy(sorted, taskList)
-	sort.Slice(sorted, func(i, j int) bool {
-		// Within same phase, sort by status (completed at bottom)
-		iOrder := statusSortOrder(sorted[i].Status)
-		jOrder := statusSortOrder(sorted[j].Status)
-		if iOrder != jOrder {
-			return iOrder < jOrder
-		}
-		return sorted[i].Title < sorted[j].Title
+	compare := taskComparer(tl, opts)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compare(sorted[i], sorted[j]) < 0
 	})
 	return sorted
 }
 
+// taskComparer returns a function that compares two tasks by opts.SortBy.
+// An empty SortBy sorts by status.
+func taskComparer(tl *tasks.TaskList, opts Options) func(a, b tasks.Task) int {
+	statuses := statusRegistry(tl, opts)
+	byStatus := func(a, b tasks.Task) int {
+		if c := cmp.Compare(statuses.Rank(a.Status), statuses.Rank(b.Status)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Title, b.Title)
+	}
+
+	switch opts.SortBy {
+	case SortByOrder:
+		return func(a, b tasks.Task) int { return 0 }
+	case SortByPriority:
+		return func(a, b tasks.Task) int {
+			return cmp.Compare(a.Priority.Rank(), b.Priority.Rank())
+		}
+	case SortByTitle:
+		return func(a, b tasks.Task) int { return strings.Compare(a.Title, b.Title) }
+	case SortByDueDate:
+		return func(a, b tasks.Task) int {
+			aDue, bDue := tl.DueDate(a), tl.DueDate(b)
+			switch {
+			case aDue == bDue:
+				return 0
+			case aDue == "":
+				return 1
+			case bDue == "":
+				return -1
+			}
+			return strings.Compare(aDue, bDue)
+		}
+	case SortByDependency:
+		order := tl.DependencyOrder()
+		return func(a, b tasks.Task) int { return cmp.Compare(order[a.ID], order[b.ID]) }
+	}
+	return byStatus
+}
+
 func renderByArea(sb *strings.Builder, tl *tasks.TaskList, opts Options) {
 	tasksByArea := tl.TasksByArea()
 
@@ -518,8 +707,10 @@ func renderByPhase(sb *strings.Builder, tl *tasks.TaskList, opts Options) {
 			continue
 		}
 
-		header := fmt.Sprintf("Phase %d", phase)
-		renderSectionHeading(sb, header, tl.Project, opts)
+		renderSectionHeading(sb, tl.PhaseName(phase), tl.Project, opts)
+		if p, ok := tl.Phase(phase); ok {
+			renderPhaseDetails(sb, tl, p, opts)
+		}
 
 		if opts.ShowAreaSubheadings && len(tl.Areas) > 0 {
 			renderTasksByAreaWithinPhase(sb, phaseTasks, tl, opts, areaNames)
@@ -543,6 +734,20 @@ func renderByPhase(sb *strings.Builder, tl *tasks.TaskList, opts Options) {
 	}
 }
 
+// renderPhaseDetails writes the status, target date and description of a
+// declared phase.
+func renderPhaseDetails(sb *strings.Builder, tl *tasks.TaskList, p tasks.Phase, opts Options) {
+	statuses := statusRegistry(tl, opts)
+	line := "**Status:** " + statusLabel(statuses, tl.PhaseStatus(p.Number), opts)
+	if p.TargetDate != "" {
+		line += " · **Target:** " + p.TargetDate
+	}
+	sb.WriteString(line + "\n\n")
+	if p.Description != "" {
+		sb.WriteString(p.Description + "\n\n")
+	}
+}
+
 // renderTasksByAreaWithinPhase renders tasks grouped by area as sub-sections.
 func renderTasksByAreaWithinPhase(sb *strings.Builder, taskList []tasks.Task, tl *tasks.TaskList, opts Options, areaNames map[string]string) {
 	// Group tasks by area
@@ -584,15 +789,16 @@ func renderTasksByAreaWithinPhase(sb *strings.Builder, taskList []tasks.Task, tl
 }
 
 // renderTasksAsList renders tasks as a simple list with checkboxes.
-func renderTasksAsList(sb *strings.Builder, taskList []tasks.Task, _ *tasks.TaskList, opts Options) {
-	sorted := sortTasks(taskList, opts)
+func renderTasksAsList(sb *strings.Builder, taskList []tasks.Task, tl *tasks.TaskList, opts Options) {
+	statuses := statusRegistry(tl, opts)
+	sorted := sortTasks(taskList, tl, opts)
 
 	for _, task := range sorted {
-		if task.Status == tasks.StatusCompleted && !opts.ShowCompleted {
+		if isHidden(statuses, task, opts) {
 			continue
 		}
 
-		isComplete := isTaskComplete(task)
+		isComplete := isTaskComplete(statuses, task)
 
 		var line string
 		if opts.UseCheckboxes {
@@ -608,6 +814,15 @@ func renderTasksAsList(sb *strings.Builder, taskList []tasks.Task, _ *tasks.Task
 		if task.Description != "" {
 			line += " - " + task.Description
 		}
+		if task.Version != "" && statuses.IsDone(task.Status) {
+			line += " (shipped in " + versionLabel(task.Version) + ")"
+		}
+		switch {
+		case statuses.IsBlocked(task.Status):
+			line += " - " + blockedLabel(task, tl)
+		case statuses.IsCancelled(task.Status):
+			line += " - " + cancelledLabel(task)
+		}
 
 		sb.WriteString(line + "\n")
 
@@ -625,17 +840,18 @@ func renderTasksAsList(sb *strings.Builder, taskList []tasks.Task, _ *tasks.Task
 
 func renderByStatus(sb *strings.Builder, tl *tasks.TaskList, opts Options) {
 	tasksByStatus := tl.TasksByStatus()
+	statuses := statusRegistry(tl, opts)
+	legend := statuses.Legend()
 
-	for _, status := range tasks.StatusOrder() {
+	for _, status := range statuses.Order() {
 		statusTasks := tasksByStatus[status]
 		if len(statusTasks) == 0 {
 			continue
 		}
-		if status == tasks.StatusCompleted && !opts.ShowCompleted {
+		if isHiddenStatus(statuses, status, opts) {
 			continue
 		}
 
-		legend := tl.GetLegend()
 		header := legend[status].Description
 		if opts.UseEmoji {
 			header = legend[status].Emoji + " " + header
@@ -649,11 +865,158 @@ func renderByStatus(sb *strings.Builder, tl *tasks.TaskList, opts Options) {
 	}
 }
 
+// versionGroups returns unreleased tasks followed by one group per shipped
+// version, newest first. Shipped groups are omitted if completed tasks are
+// hidden.
+func versionGroups(tl *tasks.TaskList, opts Options) []taskGroup {
+	byVersion := tl.TasksByVersion()
+	var groups []taskGroup
+	if unreleased := byVersion["_unspecified"]; len(unreleased) > 0 {
+		groups = append(groups, taskGroup{Title: "Unreleased", Tasks: unreleased})
+	}
+	if !opts.ShowCompleted {
+		return groups
+	}
+	for _, v := range tl.Versions() {
+		groups = append(groups, taskGroup{Title: "Shipped in " + versionLabel(v), Tasks: byVersion[v]})
+	}
+	return groups
+}
+
+// versionLabel formats a version for display, adding a "v" prefix to
+// numeric versions.
+func versionLabel(v string) string {
+	if v != "" && v[0] >= '0' && v[0] <= '9' {
+		return "v" + v
+	}
+	return v
+}
+
+func renderByVersion(sb *strings.Builder, tl *tasks.TaskList, opts Options) {
+	for _, group := range versionGroups(tl, opts) {
+		renderSectionHeading(sb, group.Title, tl.Project, opts)
+		renderTasks(sb, group.Tasks, tl, opts)
+
+		if opts.HorizontalRules {
+			sb.WriteString("---\n\n")
+		}
+	}
+}
+
+// milestoneGroup is a section of tasks for GroupByMilestone.
+type milestoneGroup struct {
+	Title     string
+	Milestone *tasks.Milestone // nil for unscheduled tasks
+	Tasks     []tasks.Task
+}
+
+// milestoneGroups returns one group per milestone with tasks, in roadmap
+// order, followed by tasks not scheduled for a declared milestone.
+func milestoneGroups(tl *tasks.TaskList) []milestoneGroup {
+	byMilestone := tl.TasksByMilestone()
+	var groups []milestoneGroup
+	declared := make(map[string]bool, len(tl.Milestones))
+	for i := range tl.Milestones {
+		m := &tl.Milestones[i]
+		declared[m.Version] = true
+		if len(byMilestone[m.Version]) == 0 {
+			continue
+		}
+		groups = append(groups, milestoneGroup{Title: versionLabel(m.Version), Milestone: m, Tasks: byMilestone[m.Version]})
+	}
+	var unscheduled []tasks.Task
+	for _, task := range tl.Tasks {
+		if !declared[task.Milestone] {
+			unscheduled = append(unscheduled, task)
+		}
+	}
+	if len(unscheduled) > 0 {
+		groups = append(groups, milestoneGroup{Title: "Unscheduled", Tasks: unscheduled})
+	}
+	return groups
+}
+
+// progressLabel formats completed/total with a percentage, or "-" if there
+// are no tasks.
+func progressLabel(completed, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d (%d%%)", completed, total, completed*100/total)
+}
+
+// statusLabel returns the emoji or name of a status.
+func statusLabel(statuses *tasks.StatusRegistry, status tasks.Status, opts Options) string {
+	def, ok := statuses.Lookup(status)
+	switch {
+	case !ok:
+		return string(status)
+	case opts.UseEmoji:
+		return def.Emoji + " " + def.Description
+	default:
+		return def.Description
+	}
+}
+
+func renderMilestoneTable(sb *strings.Builder, tl *tasks.TaskList, opts Options) {
+	statuses := statusRegistry(tl, opts)
+	sb.WriteString("## Milestones\n\n")
+	sb.WriteString("| Milestone | Status | Date | Progress | Summary |\n")
+	sb.WriteString("|-----------|--------|------|----------|---------|\n")
+
+	byMilestone := tl.TasksByMilestone()
+	for _, m := range tl.Milestones {
+		milestoneTasks := byMilestone[m.Version]
+		label := versionLabel(m.Version)
+		if opts.GroupBy == GroupByMilestone && len(milestoneTasks) > 0 {
+			label = fmt.Sprintf("[%s](#%s)", label, slugify(label))
+		}
+		date := m.Date
+		if date == "" {
+			date = "-"
+		}
+		summary := m.Summary
+		if summary == "" {
+			summary = "-"
+		}
+		fmt.Fprintf(sb, "| %s | %s | %s | %s | %s |\n", label, statusLabel(statuses, tl.MilestoneStatus(m), opts),
+			date, progressLabel(countCompleted(statuses, milestoneTasks), countActive(statuses, milestoneTasks)), summary)
+	}
+	sb.WriteString("\n")
+}
+
+func renderByMilestone(sb *strings.Builder, tl *tasks.TaskList, opts Options) {
+	statuses := statusRegistry(tl, opts)
+	for _, group := range milestoneGroups(tl) {
+		renderSectionHeading(sb, group.Title, tl.Project, opts)
+
+		progress := "**Progress:** " + progressLabel(countCompleted(statuses, group.Tasks), countActive(statuses, group.Tasks))
+		if m := group.Milestone; m != nil {
+			line := "**Status:** " + statusLabel(statuses, tl.MilestoneStatus(*m), opts)
+			if m.Date != "" {
+				line += " · **Date:** " + m.Date
+			}
+			sb.WriteString(line + " · " + progress + "\n\n")
+			if m.Summary != "" {
+				sb.WriteString(m.Summary + "\n\n")
+			}
+		} else {
+			sb.WriteString(progress + "\n\n")
+		}
+		renderTasks(sb, group.Tasks, tl, opts)
+
+		if opts.HorizontalRules {
+			sb.WriteString("---\n\n")
+		}
+	}
+}
+
 func renderTasks(sb *strings.Builder, taskList []tasks.Task, tl *tasks.TaskList, opts Options) {
-	sorted := sortTasks(taskList, opts)
+	statuses := statusRegistry(tl, opts)
+	sorted := sortTasks(taskList, tl, opts)
 
 	for i, task := range sorted {
-		if task.Status == tasks.StatusCompleted && !opts.ShowCompleted {
+		if isHidden(statuses, task, opts) {
 			continue
 		}
 		renderTask(sb, task, i+1, tl, opts)
@@ -661,7 +1024,14 @@ func renderTasks(sb *strings.Builder, taskList []tasks.Task, tl *tasks.TaskList,
 }
 
 func renderTask(sb *strings.Builder, task tasks.Task, num int, tl *tasks.TaskList, opts Options) {
-	isComplete := isTaskComplete(task)
+	statuses := statusRegistry(tl, opts)
+	isComplete := isTaskComplete(statuses, task)
+
+	// Cancelled tasks are struck through
+	name := task.Title
+	if statuses.IsCancelled(task.Status) {
+		name = "~~" + name + "~~"
+	}
 
 	// Task header with checkbox
 	var title string
@@ -671,21 +1041,21 @@ func renderTask(sb *strings.Builder, task tasks.Task, num int, tl *tasks.TaskLis
 			checkbox = "[x]"
 		}
 		if opts.NumberItems {
-			title = fmt.Sprintf("%d. %s %s", num, checkbox, task.Title)
+			title = fmt.Sprintf("%d. %s %s", num, checkbox, name)
 		} else {
-			title = fmt.Sprintf("%s %s", checkbox, task.Title)
+			title = fmt.Sprintf("%s %s", checkbox, name)
 		}
 	} else {
 		if opts.NumberItems {
-			title = fmt.Sprintf("%d. %s", num, task.Title)
+			title = fmt.Sprintf("%d. %s", num, name)
 		} else {
-			title = task.Title
+			title = name
 		}
 	}
 
 	// Add emoji suffix if not using checkboxes
 	if opts.UseEmoji && !opts.UseCheckboxes {
-		title += " " + tl.GetStatusEmoji(task.Status)
+		title += " " + statusEmoji(statuses, task.Status)
 	}
 
 	// Add stable anchor for navigation
@@ -697,6 +1067,36 @@ func renderTask(sb *strings.Builder, task tasks.Task, num int, tl *tasks.TaskLis
 		sb.WriteString(task.Description + "\n\n")
 	}
 
+	renderContent(sb, task.Content)
+
+	// Release the task shipped in
+	if task.Version != "" && statuses.IsDone(task.Status) && opts.GroupBy != GroupByVersion {
+		fmt.Fprintf(sb, "Shipped in %s\n\n", versionLabel(task.Version))
+	}
+
+	// External dependencies the task waits on
+	if len(task.WaitsOn) > 0 {
+		sb.WriteString(waitsOnLabel(task, tl, opts) + "\n\n")
+	}
+
+	// Links to issues, pull requests and documents
+	if len(task.Links) > 0 {
+		sb.WriteString(linksLabel(task, opts) + "\n\n")
+	}
+
+	// Labels
+	if len(task.Labels) > 0 {
+		sb.WriteString(labelsLabel(task, tl) + "\n\n")
+	}
+
+	// Why the task is blocked or was cancelled
+	switch {
+	case statuses.IsBlocked(task.Status):
+		sb.WriteString(blockedLabel(task, tl) + "\n\n")
+	case statuses.IsCancelled(task.Status):
+		sb.WriteString(cancelledLabel(task) + "\n\n")
+	}
+
 	// Subtasks
 	if len(task.Subtasks) > 0 {
 		for _, subtask := range task.Subtasks {
@@ -1,14 +1,34 @@
 // Package renderer provides Markdown generation from TaskList IR.
 package renderer
 
+import (
+	"github.com/grokify/structured-tasks/query"
+	"github.com/grokify/structured-tasks/tasks"
+)
+
 // GroupBy specifies how to group tasks.
 type GroupBy string
 
 const (
-	GroupByArea   GroupBy = "area"
-	GroupByType   GroupBy = "type"
-	GroupByPhase  GroupBy = "phase"
-	GroupByStatus GroupBy = "status"
+	GroupByArea      GroupBy = "area"
+	GroupByType      GroupBy = "type"
+	GroupByPhase     GroupBy = "phase"
+	GroupByStatus    GroupBy = "status"
+	GroupByVersion   GroupBy = "version"
+	GroupByMilestone GroupBy = "milestone"
+	GroupByLabel     GroupBy = "label" // Tasks with several labels appear under each
+)
+
+// SortBy specifies how tasks are ordered within a group.
+type SortBy string
+
+const (
+	SortByOrder      SortBy = "order"      // Position in the Tasks array
+	SortByPriority   SortBy = "priority"   // Most urgent first, unprioritized last
+	SortByStatus     SortBy = "status"     // Status display order, then title
+	SortByTitle      SortBy = "title"      // Alphabetically by title
+	SortByDueDate    SortBy = "due"        // Earliest due date first, undated last
+	SortByDependency SortBy = "dependency" // Dependencies before the tasks that depend on them
 )
 
 // Options controls how the task list is rendered to Markdown.
@@ -16,9 +36,21 @@ type Options struct {
 	// GroupBy determines how tasks are grouped.
 	GroupBy GroupBy
 
+	// SortBy determines how tasks are ordered in sections, the status
+	// table and the table of contents. Ties keep array order.
+	SortBy SortBy
+
 	// ShowCompleted includes completed tasks in output.
 	ShowCompleted bool
 
+	// ShowCancelled includes cancelled tasks in output.
+	ShowCancelled bool
+
+	// Filter limits output to the tasks matching a filter expression, such
+	// as a view for one audience. Tables, counts and progress only cover
+	// matching tasks. A nil filter includes every task.
+	Filter *query.Filter
+
 	// UseCheckboxes renders tasks as [ ] and [x] syntax.
 	UseCheckboxes bool
 
@@ -49,12 +81,29 @@ type Options struct {
 	// ShowOverviewTable renders a summary table of all tasks at the top.
 	ShowOverviewTable bool
 
+	// ShowMilestoneTable renders a summary table of milestones at the top.
+	// It is omitted if the task list has no milestones.
+	ShowMilestoneTable bool
+
+	// ShowDependencies renders a "Dependencies" section after the tasks
+	// listing external dependencies and the internal package graph. It is
+	// omitted if the task list declares no dependencies.
+	ShowDependencies bool
+
+	// ShowIssueLinks adds an "Issue" column to the status table linking to
+	// each task's primary (first) issue link.
+	ShowIssueLinks bool
+
 	// ShowAreaSubheadings shows area groupings within phase sections.
 	// Only applies when GroupBy is set to GroupByPhase.
 	ShowAreaSubheadings bool
 
 	// ShowNavLinks adds navigation links (e.g., "Top" links in section headings).
 	ShowNavLinks bool
+
+	// statuses is the status registry of the task list being rendered,
+	// built once by Render and RenderHTML.
+	statuses *tasks.StatusRegistry
 }
 
 // DefaultIntroText is the standard introductory paragraph.
@@ -66,7 +115,9 @@ The format is based on and generated by [Structured Tasks](https://github.com/gr
 func DefaultOptions() Options {
 	return Options{
 		GroupBy:             GroupByArea,
+		SortBy:              SortByStatus,
 		ShowCompleted:       true,
+		ShowCancelled:       false,
 		UseCheckboxes:       true,
 		UseEmoji:            true,
 		ShowLegend:          false,
@@ -75,6 +126,9 @@ func DefaultOptions() Options {
 		ShowTOC:             false,
 		TOCDepth:            1,
 		ShowOverviewTable:   true,
+		ShowMilestoneTable:  true,
+		ShowDependencies:    true,
+		ShowIssueLinks:      false,
 		ShowAreaSubheadings: false,
 		ShowNavLinks:        true,
 		NumberItems:         false,
@@ -88,6 +142,18 @@ func (o Options) WithGroupBy(g GroupBy) Options {
 	return o
 }
 
+// WithSortBy sets the sort strategy.
+func (o Options) WithSortBy(s SortBy) Options {
+	o.SortBy = s
+	return o
+}
+
+// WithFilter limits output to the tasks matching the filter.
+func (o Options) WithFilter(f *query.Filter) Options {
+	o.Filter = f
+	return o
+}
+
 // WithCheckboxes enables or disables checkbox syntax.
 func (o Options) WithCheckboxes(enabled bool) Options {
 	o.UseCheckboxes = enabled
@@ -111,3 +177,27 @@ func (o Options) WithNumberedItems(enabled bool) Options {
 	o.NumberItems = enabled
 	return o
 }
+
+// WithMilestoneTable enables or disables the milestone summary table.
+func (o Options) WithMilestoneTable(enabled bool) Options {
+	o.ShowMilestoneTable = enabled
+	return o
+}
+
+// WithCancelled enables or disables showing cancelled tasks.
+func (o Options) WithCancelled(enabled bool) Options {
+	o.ShowCancelled = enabled
+	return o
+}
+
+// WithIssueLinks enables or disables the issue column in the status table.
+func (o Options) WithIssueLinks(enabled bool) Options {
+	o.ShowIssueLinks = enabled
+	return o
+}
+
+// WithDependencies enables or disables the dependencies section.
+func (o Options) WithDependencies(enabled bool) Options {
+	o.ShowDependencies = enabled
+	return o
+}
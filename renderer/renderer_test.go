@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/grokify/structured-tasks/query"
 	"github.com/grokify/structured-tasks/tasks"
 )
 
@@ -495,7 +496,7 @@ func TestIsTaskCompleteAllSubtasksDone(t *testing.T) {
 		},
 	}
 
-	if !isTaskComplete(task) {
+	if !isTaskComplete(tasks.DefaultStatusRegistry(), task) {
 		t.Error("Task with all subtasks complete should be complete")
 	}
 }
@@ -507,7 +508,481 @@ func TestIsTaskCompleteNoSubtasks(t *testing.T) {
 		Status: tasks.StatusInProgress,
 	}
 
-	if isTaskComplete(task) {
+	if isTaskComplete(tasks.DefaultStatusRegistry(), task) {
 		t.Error("Task without subtasks and not completed status should not be complete")
 	}
 }
+
+func TestRenderShippedVersions(t *testing.T) {
+	tl := &tasks.TaskList{
+		IRVersion: "1.0",
+		Project:   "Test",
+		Tasks: []tasks.Task{
+			{ID: "a", Title: "Parser", Status: tasks.StatusCompleted, Version: "0.2.0"},
+			{ID: "b", Title: "Renderer", Status: tasks.StatusCompleted, Version: "0.10.0"},
+			{ID: "c", Title: "Plugins", Status: tasks.StatusPlanned},
+		},
+	}
+
+	output := Render(tl, DefaultOptions())
+	if !strings.Contains(output, "Shipped in v0.2.0") {
+		t.Errorf("Expected shipped version in output:\n%s", output)
+	}
+
+	output = Render(tl, DefaultOptions().WithGroupBy(GroupByVersion))
+	unreleased := strings.Index(output, "## Unreleased")
+	newer := strings.Index(output, "## Shipped in v0.10.0")
+	older := strings.Index(output, "## Shipped in v0.2.0")
+	if unreleased < 0 || newer < unreleased || older < newer {
+		t.Errorf("Expected unreleased, then versions newest first:\n%s", output)
+	}
+
+	opts := DefaultOptions().WithGroupBy(GroupByVersion)
+	opts.ShowCompleted = false
+	if output := Render(tl, opts); strings.Contains(output, "Shipped in") {
+		t.Errorf("Expected shipped sections hidden:\n%s", output)
+	}
+}
+
+func TestRenderMilestones(t *testing.T) {
+	tl := &tasks.TaskList{
+		IRVersion: "1.0",
+		Project:   "Test",
+		Milestones: []tasks.Milestone{
+			{Version: "0.1.0", Date: "2026-01-15", Summary: "First release"},
+			{Version: "0.2.0"},
+		},
+		Tasks: []tasks.Task{
+			{ID: "a", Title: "Parser", Status: tasks.StatusCompleted, Milestone: "0.1.0"},
+			{ID: "b", Title: "Renderer", Status: tasks.StatusInProgress, Milestone: "0.2.0"},
+			{ID: "c", Title: "Docs", Status: tasks.StatusPlanned, Milestone: "0.2.0"},
+			{ID: "d", Title: "Plugins", Status: tasks.StatusFuture},
+		},
+	}
+
+	output := Render(tl, DefaultOptions().WithGroupBy(GroupByMilestone).WithEmoji(false))
+	for _, want := range []string{
+		"## Milestones",
+		"| [v0.1.0](#v010) | Completed | 2026-01-15 | 1/1 (100%) | First release |",
+		"| [v0.2.0](#v020) | In Progress | - | 0/2 (0%) | - |",
+		"## v0.2.0",
+		"**Status:** In Progress · **Progress:** 0/2 (0%)",
+		"## Unscheduled",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+	if strings.Index(output, "## v0.1.0") > strings.Index(output, "## v0.2.0") {
+		t.Error("Expected milestones in roadmap order")
+	}
+
+	output = Render(tl, DefaultOptions().WithMilestoneTable(false))
+	if strings.Contains(output, "## Milestones") {
+		t.Error("Expected milestone table to be hidden")
+	}
+}
+
+func TestRenderPhaseNames(t *testing.T) {
+	tl := &tasks.TaskList{
+		IRVersion: "1.0",
+		Project:   "Test",
+		Phases: []tasks.Phase{
+			{Number: 1, Name: "Foundation", Status: tasks.StatusCompleted},
+			{Number: 4, Name: "Plugins", Description: "Third-party extensions.", TargetDate: "2026-09-01"},
+		},
+		Tasks: []tasks.Task{
+			{ID: "a", Title: "Parser", Status: tasks.StatusCompleted, Phase: 1},
+			{ID: "b", Title: "Loader", Status: tasks.StatusPlanned, Phase: 4},
+		},
+	}
+
+	opts := DefaultOptions().WithGroupBy(GroupByPhase).WithEmoji(false)
+	opts.ShowTOC = true
+	output := Render(tl, opts)
+	for _, want := range []string{
+		"| Plugins | [Loader](#b) |",
+		"- [Plugins (0/1)](#plugins)",
+		"## Foundation",
+		"## Plugins",
+		"**Status:** Planned · **Target:** 2026-09-01",
+		"Third-party extensions.",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "Phase 4") || strings.Contains(output, "| 1 |") {
+		t.Errorf("Expected phase names instead of numbers:\n%s", output)
+	}
+}
+
+func contentTaskList() *tasks.TaskList {
+	return &tasks.TaskList{
+		IRVersion: "1.0",
+		Project:   "Test",
+		Sections: []tasks.Section{
+			{ID: "faq", Title: "FAQ", Order: 1, Content: []tasks.ContentBlock{
+				{Type: tasks.ContentList, Items: []string{"Why?", "How?"}},
+			}},
+			{ID: "design", Title: "Design Notes", Content: []tasks.ContentBlock{
+				{Type: tasks.ContentText, Value: "Uses <interfaces>."},
+				{Type: tasks.ContentBlockquote, Value: "Note: keep it small\n\nReally."},
+				{Type: tasks.ContentTable, Headers: []string{"Backend", "Status"}, Rows: [][]string{{"file|s3", "done"}}},
+			}},
+		},
+		Tasks: []tasks.Task{
+			{ID: "a", Title: "Loader", Status: tasks.StatusPlanned, Content: []tasks.ContentBlock{
+				{Type: tasks.ContentCode, Language: "go", Value: "func Load() {}"},
+				{Type: tasks.ContentDiagram, Format: "mermaid", Value: "graph TD\n  A-->B"},
+			}},
+		},
+	}
+}
+
+func TestRenderSectionsAndContent(t *testing.T) {
+	output := Render(contentTaskList(), DefaultOptions())
+	for _, want := range []string{
+		"Uses <interfaces>.\n\n",
+		"> Note: keep it small\n>\n> Really.\n",
+		"| Backend | Status |\n| --- | --- |\n| file\\|s3 | done |\n",
+		"```go\nfunc Load() {}\n```\n",
+		"```mermaid\ngraph TD\n  A-->B\n```\n",
+		"- Why?\n- How?\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+	design, task, faq := strings.Index(output, "## Design Notes"), strings.Index(output, "### [ ] Loader"), strings.Index(output, "## FAQ")
+	if design < 0 || task < design || faq < task {
+		t.Errorf("Expected design notes, then tasks, then FAQ:\n%s", output)
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	output, err := RenderHTML(contentTaskList(), DefaultOptions())
+	if err != nil {
+		t.Fatalf("RenderHTML() error: %v", err)
+	}
+	for _, want := range []string{
+		"<p>Uses &lt;interfaces&gt;.</p>",
+		"<blockquote>\n<p>Note: keep it small</p>\n<p>Really.</p>\n</blockquote>",
+		"<tr><td>file|s3</td><td>done</td></tr>",
+		`<pre><code class="language-go">func Load() {}</code></pre>`,
+		`<pre class="mermaid">graph TD`,
+		`<div class="task" id="a">`,
+		"<li>Why?</li>",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+	if strings.Index(output, "Design Notes") > strings.Index(output, "Loader") || strings.Index(output, "FAQ") < strings.Index(output, "Loader") {
+		t.Errorf("Expected sections around tasks:\n%s", output)
+	}
+}
+
+func TestRenderDependencies(t *testing.T) {
+	tl := &tasks.TaskList{
+		IRVersion: "1.0",
+		Project:   "Test",
+		Dependencies: &tasks.Dependencies{
+			External: []tasks.ExternalDependency{
+				{Name: "billing-api", Status: tasks.ExternalBuildClient, Note: "OpenAPI spec only"},
+				{Name: "acme-sdk", Status: tasks.ExternalAvailable},
+			},
+			Internal: []tasks.InternalDependency{
+				{Package: "cmd/app", DependsOn: tasks.StringList{"core", "store"}},
+				{Package: "core"},
+			},
+		},
+		Tasks: []tasks.Task{
+			{ID: "invoices", Title: "Invoices", Status: tasks.StatusPlanned, WaitsOn: []string{"billing-api"}},
+		},
+	}
+
+	output := Render(tl, DefaultOptions())
+	for _, want := range []string{
+		"Waits on: billing-api (🔨 Build Client)",
+		"## Dependencies",
+		"| billing-api | 🔨 Build Client | OpenAPI spec only | [Invoices](#invoices) |",
+		"| acme-sdk | ✅ Available |  |  |",
+		"- `cmd/app` → `core`, `store`",
+		"- `core`\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+	if strings.Index(output, "## Dependencies") < strings.Index(output, "### [ ] Invoices") {
+		t.Errorf("Expected dependencies after tasks:\n%s", output)
+	}
+
+	if output := Render(tl, DefaultOptions().WithDependencies(false)); strings.Contains(output, "## Dependencies") {
+		t.Errorf("Expected no dependencies section when disabled:\n%s", output)
+	}
+
+	html, err := RenderHTML(tl, DefaultOptions())
+	if err != nil {
+		t.Fatalf("RenderHTML() error: %v", err)
+	}
+	for _, want := range []string{
+		`<p class="waits-on">Waits on: billing-api (🔨 Build Client)</p>`,
+		`<tr><td>billing-api</td><td>🔨 Build Client</td><td>OpenAPI spec only</td><td><a href="#invoices">Invoices</a></td></tr>`,
+		"<li><code>cmd/app</code> → <code>core</code>, <code>store</code></li>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected %q in HTML:\n%s", want, html)
+		}
+	}
+}
+
+func TestRenderCustomStatuses(t *testing.T) {
+	tl := &tasks.TaskList{
+		IRVersion: "1.0",
+		Project:   "Test",
+		Statuses: []tasks.StatusDef{
+			{ID: "blocked", Emoji: "⛔", Description: "Blocked", Order: 15},
+			{ID: "wontDo", Emoji: "🚫", Description: "Won't Do", Done: true},
+		},
+		Tasks: []tasks.Task{
+			{ID: "a", Title: "Alpha", Status: tasks.StatusPlanned},
+			{ID: "b", Title: "Bravo", Status: "blocked"},
+			{ID: "c", Title: "Charlie", Status: "wontDo"},
+		},
+	}
+
+	opts := DefaultOptions().WithGroupBy(GroupByStatus).WithLegend(true)
+	opts.ShowTOC = true
+	output := Render(tl, opts)
+	for _, want := range []string{
+		"| ⛔ | Blocked |",
+		"## ⛔ Blocked",
+		"### [x] Charlie",
+		"[Won't Do (1/1)](#wont-do)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+	if strings.Index(output, "## ⛔ Blocked") > strings.Index(output, "## 📋 Planned") {
+		t.Errorf("Expected blocked before planned:\n%s", output)
+	}
+
+	opts.ShowCompleted = false
+	if output := Render(tl, opts); strings.Contains(output, "Charlie") {
+		t.Errorf("Expected done custom status to be hidden:\n%s", output)
+	}
+}
+
+func TestRenderBlockedAndCancelled(t *testing.T) {
+	tl := &tasks.TaskList{
+		IRVersion: "1.0",
+		Project:   "Test",
+		Milestones: []tasks.Milestone{
+			{Version: "1.0.0"},
+		},
+		Statuses: []tasks.StatusDef{{ID: "waitingOnVendor", Blocked: true}},
+		Tasks: []tasks.Task{
+			{ID: "api", Title: "API", Status: tasks.StatusCompleted, Milestone: "1.0.0"},
+			{ID: "pdf", Title: "PDF export", Status: "waitingOnVendor", BlockedReason: "Awaiting quote"},
+			{ID: "sso", Title: "SSO", Status: tasks.StatusBlocked, Milestone: "1.0.0", BlockedReason: "Contract pending", BlockedBy: "api"},
+			{ID: "fax", Title: "Fax export", Status: tasks.StatusCancelled, Milestone: "1.0.0", CancelledReason: "No demand"},
+		},
+	}
+
+	output := Render(tl, DefaultOptions())
+	for _, want := range []string{
+		"Blocked: Contract pending (blocked by API)",
+		"Blocked: Awaiting quote",
+		"| v1.0.0 | 🚧 In Progress | - | 1/2 (50%) | - |",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "Fax export") {
+		t.Errorf("Expected cancelled task hidden by default:\n%s", output)
+	}
+
+	output = Render(tl, DefaultOptions().WithCancelled(true))
+	for _, want := range []string{"### [ ] ~~Fax export~~", "Cancelled: No demand"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q with cancelled tasks shown:\n%s", want, output)
+		}
+	}
+
+	html, err := RenderHTML(tl, DefaultOptions().WithCancelled(true))
+	if err != nil {
+		t.Fatalf("RenderHTML() error: %v", err)
+	}
+	for _, want := range []string{
+		`<p class="reason">Blocked: Contract pending (blocked by API)</p>`,
+		`<p class="reason">Blocked: Awaiting quote</p>`,
+		"<s>Fax export</s>",
+		`<p class="reason">Cancelled: No demand</p>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected %q in HTML:\n%s", want, html)
+		}
+	}
+}
+
+func TestSortTasks(t *testing.T) {
+	tl := &tasks.TaskList{
+		IRVersion:  "1.0",
+		Project:    "Test",
+		Areas:      []tasks.Area{{ID: "core", Name: "Core"}},
+		Milestones: []tasks.Milestone{{Version: "1.0.0", Date: "2026-03-01"}},
+		Tasks: []tasks.Task{
+			{ID: "c", Title: "Charlie", Status: tasks.StatusCompleted, Area: "core", Priority: tasks.PriorityLow, DependsOn: []string{"b"}},
+			{ID: "a", Title: "Alpha", Status: tasks.StatusPlanned, Area: "core", DueDate: "2026-05-01"},
+			{ID: "b", Title: "Bravo", Status: tasks.StatusInProgress, Area: "core", Priority: tasks.PriorityHigh, Milestone: "1.0.0"},
+		},
+	}
+
+	ids := func(list []tasks.Task) string {
+		var s []string
+		for _, task := range list {
+			s = append(s, task.ID)
+		}
+		return strings.Join(s, ",")
+	}
+	for sortBy, want := range map[SortBy]string{
+		SortByOrder:      "c,a,b",
+		SortByPriority:   "b,c,a",
+		SortByStatus:     "b,a,c",
+		SortByTitle:      "a,b,c",
+		SortByDueDate:    "b,a,c",
+		SortByDependency: "a,b,c",
+		"":               "b,a,c",
+	} {
+		if got := ids(sortTasks(tl.Tasks, tl, DefaultOptions().WithSortBy(sortBy))); got != want {
+			t.Errorf("sortTasks(%q) = %s, want %s", sortBy, got, want)
+		}
+	}
+
+	opts := DefaultOptions().WithSortBy(SortByTitle)
+	opts.ShowTOC, opts.TOCDepth = true, 2
+	output := Render(tl, opts)
+	for _, want := range []string{
+		"| - | [Alpha](#a) | 📋 | Core |\n| - | [Bravo](#b) | 🚧 | Core |\n| - | [Charlie](#c) | ✅ | Core |",
+		"  - [Alpha](#a)\n  - [Bravo](#b)\n  - [Charlie](#c)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+}
+
+func TestRenderLabels(t *testing.T) {
+	tl := &tasks.TaskList{
+		IRVersion: "1.0",
+		Project:   "Test",
+		Labels:    []tasks.LabelDef{{ID: "security", Name: "Security", Color: "red"}},
+		Tasks: []tasks.Task{
+			{ID: "a", Title: "Auth", Status: tasks.StatusPlanned, Labels: []string{"security", "perf"}},
+			{ID: "b", Title: "Docs", Status: tasks.StatusPlanned},
+		},
+	}
+
+	output := Render(tl, DefaultOptions().WithGroupBy(GroupByLabel))
+	for _, want := range []string{"## Security", "## perf", "## Unlabeled", "Labels: `Security` `perf`"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+	if strings.Count(output, "### [ ] Auth") != 2 {
+		t.Errorf("Expected task under each of its labels:\n%s", output)
+	}
+
+	html, err := RenderHTML(tl, DefaultOptions().WithGroupBy(GroupByLabel))
+	if err != nil {
+		t.Fatalf("RenderHTML() error: %v", err)
+	}
+	for _, want := range []string{`<h2>Security</h2>`, `<span class="label" style="border-color: red">Security</span><span class="label">perf</span>`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected %q in HTML:\n%s", want, html)
+		}
+	}
+}
+
+func TestRenderLinks(t *testing.T) {
+	tl := &tasks.TaskList{
+		IRVersion: "1.0",
+		Project:   "Test",
+		Tasks: []tasks.Task{
+			{ID: "a", Title: "Auth", Status: tasks.StatusPlanned, Links: []tasks.Link{
+				{Kind: tasks.LinkIssue, URL: "https://github.com/org/repo/issues/12"},
+				{Kind: tasks.LinkPR, URL: "https://github.com/org/repo/pull/34/"},
+				{Kind: tasks.LinkDoc, URL: "https://example.com/design", Title: "Design"},
+				{URL: "https://example.com/other"},
+			}},
+			{ID: "b", Title: "Docs", Status: tasks.StatusPlanned},
+		},
+	}
+
+	output := Render(tl, DefaultOptions())
+	wantRow := "🎫 [#12](<https://github.com/org/repo/issues/12>) · 🔀 [#34](<https://github.com/org/repo/pull/34/>) · " +
+		"📄 [Design](<https://example.com/design>) · 🔗 [https://example.com/other](<https://example.com/other>)"
+	if !strings.Contains(output, wantRow) {
+		t.Errorf("Expected link row %q in output:\n%s", wantRow, output)
+	}
+	if strings.Contains(output, "| Issue |") {
+		t.Errorf("Expected no issue column by default:\n%s", output)
+	}
+
+	output = Render(tl, DefaultOptions().WithEmoji(false).WithIssueLinks(true))
+	for _, want := range []string{"| Phase | Task | Status | Area | Issue |", "| [#12](<https://github.com/org/repo/issues/12>) |", "| - |", "Issue: [#12]", "PR: [#34]"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+
+	html, err := RenderHTML(tl, DefaultOptions())
+	if err != nil {
+		t.Fatalf("RenderHTML() error: %v", err)
+	}
+	if !strings.Contains(html, `<p class="links">🎫 <a href="https://github.com/org/repo/issues/12">#12</a> · 🔀`) {
+		t.Errorf("Expected link row in HTML:\n%s", html)
+	}
+
+	// Titles and URLs that would break the Markdown link are escaped
+	tl.Tasks[1].Links = []tasks.Link{{Kind: tasks.LinkIssue, URL: "https://example.com/q?a=(1)&b=<2>", Title: `RFC [draft] | v2`}}
+	output = Render(tl, DefaultOptions().WithIssueLinks(true))
+	want := `[RFC \[draft\] \| v2](<https://example.com/q?a=(1)&b=%3C2%3E>)`
+	if strings.Count(output, want) != 2 {
+		t.Errorf("Expected escaped link %q in link row and issue cell:\n%s", want, output)
+	}
+}
+
+func TestRenderFilter(t *testing.T) {
+	tl := &tasks.TaskList{
+		IRVersion: "1.0",
+		Project:   "Test",
+		Areas:     []tasks.Area{{ID: "api", Name: "API"}, {ID: "core", Name: "Core"}},
+		Tasks: []tasks.Task{
+			{ID: "a", Title: "Auth", Status: tasks.StatusPlanned, Area: "api", Phase: 1},
+			{ID: "b", Title: "Billing", Status: tasks.StatusCompleted, Area: "api", Phase: 1},
+			{ID: "c", Title: "Cache", Status: tasks.StatusPlanned, Area: "core", Phase: 3},
+		},
+	}
+
+	opts := DefaultOptions().WithFilter(query.MustParse("status != completed && phase <= 2"))
+	output := Render(tl, opts)
+	if !strings.Contains(output, "Auth") || strings.Contains(output, "Billing") || strings.Contains(output, "Cache") {
+		t.Errorf("Expected only matching tasks:\n%s", output)
+	}
+	if len(tl.Tasks) != 3 {
+		t.Errorf("Expected task list to be unchanged, got %d tasks", len(tl.Tasks))
+	}
+
+	html, err := RenderHTML(tl, opts)
+	if err != nil {
+		t.Fatalf("RenderHTML() error: %v", err)
+	}
+	if !strings.Contains(html, "Auth") || strings.Contains(html, "Billing") || strings.Contains(html, "Cache") {
+		t.Errorf("Expected only matching tasks in HTML:\n%s", html)
+	}
+}
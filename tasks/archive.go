@@ -0,0 +1,176 @@
+package tasks
+
+import (
+	"fmt"
+
+	"github.com/grokify/structured-changelog/changelog"
+)
+
+// DefaultArchiveType is the changelog category used for archived tasks
+// without a Type.
+const DefaultArchiveType = "Added"
+
+// ArchiveOptions configures Archive.
+type ArchiveOptions struct {
+	Phase   int         // Phase whose completed tasks are archived
+	Version string      // Version of the new changelog release
+	Date    string      // Release date (YYYY-MM-DD)
+	Mark    bool        // Keep archived tasks in the task list, marked as archived
+	Labels  LabelFilter // Only archive tasks the filter selects
+}
+
+// WithoutArchived returns tl without its archived tasks, or tl itself if
+// none are archived. Archived tasks live on in the changelog, so views and
+// counts of the task list leave them out.
+func (tl *TaskList) WithoutArchived() *TaskList {
+	for _, task := range tl.Tasks {
+		if task.Archived {
+			return tl.Filter(func(t Task) bool { return !t.Archived })
+		}
+	}
+	return tl
+}
+
+// Archive moves the completed tasks of a phase, those whose status counts
+// as done, into a new release of cl.
+// Each task becomes an entry in the changelog category named by its Type
+// (DefaultArchiveType if unset), described by its title and description.
+// Archived tasks are removed from the task list, along with dependsOn,
+// blocks and blockedBy references to them, unless opts.Mark is set, in which case they
+// are kept and marked as archived. Archived tasks have their Version set to
+// the release version. Tasks already marked archived are skipped. Archive
+// returns the archived tasks.
+func Archive(tl *TaskList, cl *changelog.Changelog, opts ArchiveOptions) ([]Task, error) {
+	if opts.Version == "" {
+		return nil, fmt.Errorf("%w: release version", ErrMissingRequiredField)
+	}
+	if cl.Unreleased != nil && cl.Unreleased.Version == opts.Version {
+		return nil, fmt.Errorf("%w: release %s already exists", ErrDuplicateID, opts.Version)
+	}
+	for _, r := range cl.Releases {
+		if r.Version == opts.Version {
+			return nil, fmt.Errorf("%w: release %s already exists", ErrDuplicateID, opts.Version)
+		}
+	}
+
+	release := changelog.NewRelease(opts.Version, opts.Date)
+	statuses := tl.StatusRegistry()
+	var archived []Task
+	for i, task := range tl.Tasks {
+		if task.Phase != opts.Phase || !statuses.IsDone(task.Status) || task.Archived || !opts.Labels.Match(task) {
+			continue
+		}
+		category := task.Type
+		if category == "" {
+			category = DefaultArchiveType
+		}
+		if !addChangelogEntry(&release, category, changelog.NewEntry(archiveDescription(task))) {
+			return nil, fmt.Errorf("%w: tasks[%d].type: %s", ErrInvalidType, i, task.Type)
+		}
+		task.Version = opts.Version
+		archived = append(archived, task)
+	}
+	if len(archived) == 0 {
+		return nil, fmt.Errorf("%w: no completed tasks in phase %d", ErrNothingToArchive, opts.Phase)
+	}
+
+	cl.AddRelease(release)
+
+	ids := make(map[string]bool, len(archived))
+	for _, task := range archived {
+		ids[task.ID] = true
+	}
+	if opts.Mark {
+		for i := range tl.Tasks {
+			if ids[tl.Tasks[i].ID] {
+				tl.Tasks[i].Archived = true
+				tl.Tasks[i].Version = opts.Version
+			}
+		}
+		return archived, nil
+	}
+
+	remaining := make([]Task, 0, len(tl.Tasks)-len(archived))
+	for _, task := range tl.Tasks {
+		if ids[task.ID] {
+			continue
+		}
+		task.DependsOn = withoutIDs(task.DependsOn, ids)
+		task.Blocks = withoutIDs(task.Blocks, ids)
+		if ids[task.BlockedBy] {
+			task.BlockedBy = ""
+		}
+		remaining = append(remaining, task)
+	}
+	tl.Tasks = remaining
+	return archived, nil
+}
+
+// archiveDescription returns the changelog entry text for a task.
+func archiveDescription(task Task) string {
+	if task.Description == "" {
+		return task.Title
+	}
+	return task.Title + ": " + task.Description
+}
+
+// withoutIDs returns refs without the given IDs, or nil if none remain.
+func withoutIDs(refs []string, ids map[string]bool) []string {
+	var out []string
+	for _, ref := range refs {
+		if !ids[ref] {
+			out = append(out, ref)
+		}
+	}
+	return out
+}
+
+// addChangelogEntry adds an entry to the release category with the given
+// structured-changelog name. It returns false for unknown categories.
+func addChangelogEntry(r *changelog.Release, category string, e changelog.Entry) bool {
+	switch category {
+	case "Highlights":
+		r.AddHighlights(e)
+	case "Breaking":
+		r.AddBreaking(e)
+	case "Upgrade Guide":
+		r.AddUpgradeGuide(e)
+	case "Security":
+		r.AddSecurity(e)
+	case "Added":
+		r.AddAdded(e)
+	case "Changed":
+		r.AddChanged(e)
+	case "Deprecated":
+		r.AddDeprecated(e)
+	case "Removed":
+		r.AddRemoved(e)
+	case "Fixed":
+		r.AddFixed(e)
+	case "Performance":
+		r.AddPerformance(e)
+	case "Dependencies":
+		r.AddDependencies(e)
+	case "Documentation":
+		r.AddDocumentation(e)
+	case "Build":
+		r.AddBuild(e)
+	case "Tests":
+		r.AddTests(e)
+	case "Infrastructure":
+		r.AddInfrastructure(e)
+	case "Observability":
+		r.AddObservability(e)
+	case "Compliance":
+		r.AddCompliance(e)
+	case "Internal":
+		r.AddInternal(e)
+	case "Known Issues":
+		r.AddKnownIssues(e)
+	case "Contributors":
+		r.AddContributors(e)
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package tasks
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ContentType identifies the kind of a content block.
+type ContentType string
+
+const (
+	ContentText       ContentType = "text"
+	ContentCode       ContentType = "code"
+	ContentDiagram    ContentType = "diagram"
+	ContentTable      ContentType = "table"
+	ContentList       ContentType = "list"
+	ContentBlockquote ContentType = "blockquote"
+)
+
+// ContentBlock is a piece of rich content in a section or task. Which
+// fields apply depends on Type: Value for text, code, diagram and
+// blockquote; Language for code; Format ("ascii" or "mermaid") for
+// diagrams; Headers and Rows for tables; Items for lists.
+type ContentBlock struct {
+	Type     ContentType `json:"type"`
+	Value    string      `json:"value,omitempty"`
+	Language string      `json:"language,omitempty"`
+	Format   string      `json:"format,omitempty"`
+	Headers  []string    `json:"headers,omitempty"`
+	Rows     [][]string  `json:"rows,omitempty"`
+	Items    []string    `json:"items,omitempty"`
+}
+
+// Section is a freeform document section, such as design notes, rendered
+// alongside the tasks. Sections with Order 0 or less are rendered before
+// the tasks and sections with a positive Order after them; within each
+// group they are sorted by Order, then declaration order.
+type Section struct {
+	ID      string         `json:"id"`
+	Title   string         `json:"title"`
+	Order   int            `json:"order,omitempty"`
+	Content []ContentBlock `json:"content,omitempty"`
+}
+
+// SectionsBefore returns the sections rendered before the tasks, in order.
+func (tl *TaskList) SectionsBefore() []Section {
+	return sortedSections(tl.Sections, func(order int) bool { return order <= 0 })
+}
+
+// SectionsAfter returns the sections rendered after the tasks, in order.
+func (tl *TaskList) SectionsAfter() []Section {
+	return sortedSections(tl.Sections, func(order int) bool { return order > 0 })
+}
+
+func sortedSections(sections []Section, keep func(order int) bool) []Section {
+	var result []Section
+	for _, s := range sections {
+		if keep(s.Order) {
+			result = append(result, s)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Order < result[j].Order
+	})
+	return result
+}
+
+// validateContent reports content blocks that are missing the fields their
+// type requires.
+func validateContent(blocks []ContentBlock, prefix string, report func(rule Rule, field, message string)) {
+	for i, b := range blocks {
+		field := fmt.Sprintf("%s.content[%d]", prefix, i)
+		switch b.Type {
+		case "":
+			report(RuleRequiredField, field+".type", "required field is missing")
+		case ContentText, ContentCode, ContentDiagram, ContentBlockquote:
+			if b.Value == "" {
+				report(RuleInvalidContent, field+".value", fmt.Sprintf("%s block requires a value", b.Type))
+			}
+		case ContentTable:
+			if len(b.Headers) == 0 {
+				report(RuleInvalidContent, field+".headers", "table block requires headers")
+			}
+			for j, row := range b.Rows {
+				if len(b.Headers) > 0 && len(row) != len(b.Headers) {
+					report(RuleInvalidContent, fmt.Sprintf("%s.rows[%d]", field, j), fmt.Sprintf("row has %d cells, expected %d", len(row), len(b.Headers)))
+				}
+			}
+		case ContentList:
+			if len(b.Items) == 0 {
+				report(RuleInvalidContent, field+".items", "list block requires items")
+			}
+		default:
+			report(RuleInvalidContent, field+".type", fmt.Sprintf("unknown content type: %s", b.Type))
+		}
+	}
+}
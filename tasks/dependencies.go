@@ -0,0 +1,137 @@
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ExternalStatus is the availability of an external dependency.
+type ExternalStatus string
+
+const (
+	ExternalAvailable   ExternalStatus = "available"   // Usable as is
+	ExternalBuildClient ExternalStatus = "buildClient" // A client must be built before it can be used
+	ExternalPlanned     ExternalStatus = "planned"     // Not yet available
+)
+
+// Dependencies describes what a project depends on: external SDKs and the
+// graph of its own packages.
+type Dependencies struct {
+	External []ExternalDependency `json:"external,omitempty"`
+	Internal []InternalDependency `json:"internal,omitempty"`
+}
+
+// ExternalDependency is an SDK or service the project depends on. Tasks
+// that cannot proceed until it is available list its name in WaitsOn.
+type ExternalDependency struct {
+	Name   string         `json:"name"` // Dependency name or import path
+	Status ExternalStatus `json:"status,omitempty"`
+	Note   string         `json:"note,omitempty"`
+}
+
+// InternalDependency declares the packages a project package depends on.
+type InternalDependency struct {
+	Package   string     `json:"package"`
+	DependsOn StringList `json:"dependsOn,omitempty"`
+}
+
+// StringList is a list of strings that may be written as a single string
+// or an array of strings.
+type StringList []string
+
+// UnmarshalJSON accepts either a string or an array of strings.
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*l = StringList{s}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("%w: expected a string or an array of strings", ErrInvalidFormat)
+	}
+	*l = list
+	return nil
+}
+
+// ExternalDependency returns the external dependency with the given name.
+func (tl *TaskList) ExternalDependency(name string) (ExternalDependency, bool) {
+	if tl.Dependencies == nil {
+		return ExternalDependency{}, false
+	}
+	for _, d := range tl.Dependencies.External {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return ExternalDependency{}, false
+}
+
+// TasksWaitingOn returns the tasks that wait on the named external
+// dependency.
+func (tl *TaskList) TasksWaitingOn(name string) []Task {
+	var result []Task
+	for _, task := range tl.Tasks {
+		for _, w := range task.WaitsOn {
+			if w == name {
+				result = append(result, task)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func isValidExternalStatus(s ExternalStatus) bool {
+	switch s {
+	case ExternalAvailable, ExternalBuildClient, ExternalPlanned:
+		return true
+	}
+	return false
+}
+
+// validateDependencies reports invalid external and internal dependencies
+// and task waitsOn references to undeclared external dependencies.
+func validateDependencies(tl *TaskList, report func(rule Rule, field, message string), reportTask func(i int, rule Rule, field, message string)) {
+	external := make(map[string]bool)
+	if deps := tl.Dependencies; deps != nil {
+		for i, d := range deps.External {
+			prefix := fmt.Sprintf("dependencies.external[%d]", i)
+			if d.Name == "" {
+				report(RuleRequiredField, prefix+".name", "required field is missing")
+			} else if external[d.Name] {
+				report(RuleDuplicateID, prefix+".name", fmt.Sprintf("duplicate dependency: %s", d.Name))
+			} else {
+				external[d.Name] = true
+			}
+			if d.Status != "" && !isValidExternalStatus(d.Status) {
+				report(RuleInvalidStatus, prefix+".status", fmt.Sprintf("invalid dependency status: %s (expected available, buildClient or planned)", d.Status))
+			}
+		}
+
+		packages := make(map[string]bool)
+		for i, d := range deps.Internal {
+			prefix := fmt.Sprintf("dependencies.internal[%d]", i)
+			if d.Package == "" {
+				report(RuleRequiredField, prefix+".package", "required field is missing")
+			} else if packages[d.Package] {
+				report(RuleDuplicateID, prefix+".package", fmt.Sprintf("duplicate package: %s", d.Package))
+			} else {
+				packages[d.Package] = true
+			}
+			for j, dep := range d.DependsOn {
+				if dep == "" {
+					report(RuleRequiredField, fmt.Sprintf("%s.dependsOn[%d]", prefix, j), "required field is missing")
+				}
+			}
+		}
+	}
+
+	for i, task := range tl.Tasks {
+		for j, name := range task.WaitsOn {
+			if !external[name] {
+				reportTask(i, RuleUnknownExternal, fmt.Sprintf("tasks[%d].waitsOn[%d]", i, j), fmt.Sprintf("references unknown external dependency: %s", name))
+			}
+		}
+	}
+}
@@ -22,15 +22,40 @@ var (
 	// ErrInvalidReference indicates a reference to a non-existent item.
 	ErrInvalidReference = errors.New("invalid reference")
 
+	// ErrUnresolvedReference indicates a cross-project reference whose
+	// project is not available.
+	ErrUnresolvedReference = errors.New("unresolved cross-project reference")
+
 	// ErrInvalidFormat indicates an invalid format for a field value.
 	ErrInvalidFormat = errors.New("invalid format")
 
 	// ErrInvalidType indicates an invalid change type.
 	ErrInvalidType = errors.New("invalid change type")
 
+	// ErrPhaseOrder indicates a dependency that conflicts with phase order.
+	ErrPhaseOrder = errors.New("inconsistent phase order")
+
+	// ErrInconsistentStatus indicates a status that conflicts with related tasks or subtasks.
+	ErrInconsistentStatus = errors.New("inconsistent status")
+
+	// ErrValidation indicates that validation failed.
+	ErrValidation = errors.New("validation failed")
+
 	// ErrParseJSON indicates a JSON parsing error.
 	ErrParseJSON = errors.New("failed to parse JSON")
 
+	// ErrParseYAML indicates a YAML parsing error.
+	ErrParseYAML = errors.New("failed to parse YAML")
+
+	// ErrParseTOML indicates a TOML parsing error.
+	ErrParseTOML = errors.New("failed to parse TOML")
+
+	// ErrIncludeCycle indicates a task list that includes itself.
+	ErrIncludeCycle = errors.New("include cycle")
+
+	// ErrNothingToArchive indicates that no tasks matched an archive request.
+	ErrNothingToArchive = errors.New("nothing to archive")
+
 	// ErrReadFile indicates a file read error.
 	ErrReadFile = errors.New("failed to read file")
 
@@ -40,11 +65,15 @@ var (
 
 // ParseError wraps a parsing error with context.
 type ParseError struct {
-	Op  string // Operation (e.g., "read", "parse", "unmarshal")
-	Err error  // Underlying error
+	Op  string   // Operation (e.g., "read", "parse", "unmarshal")
+	Pos Position // Source position, if known
+	Err error    // Underlying error
 }
 
 func (e *ParseError) Error() string {
+	if e.Pos.IsValid() {
+		return fmt.Sprintf("%s: %s: %v", e.Pos, e.Op, e.Err)
+	}
 	return fmt.Sprintf("%s: %v", e.Op, e.Err)
 }
 
@@ -0,0 +1,444 @@
+package tasks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is a serialization format for a task list.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+)
+
+// ParseFormatName converts a format name (json, yaml, yml, toml) to a Format.
+func ParseFormatName(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "json":
+		return FormatJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "toml":
+		return FormatTOML, nil
+	}
+	return "", fmt.Errorf("%w: unknown format: %s", ErrInvalidFormat, name)
+}
+
+// FormatFromPath returns the format implied by a file extension, or "" if
+// the extension is not recognized.
+func FormatFromPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".jsonc":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	}
+	return ""
+}
+
+// DetectFormat determines the format of a task list file by extension,
+// falling back to inspecting the content.
+func DetectFormat(path string, data []byte) Format {
+	if f := FormatFromPath(path); f != "" {
+		return f
+	}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] == '{' || bytes.HasPrefix(trimmed, []byte("//")) || bytes.HasPrefix(trimmed, []byte("/*")) {
+		return FormatJSON
+	}
+	for _, line := range strings.Split(string(trimmed), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") || strings.Contains(line, " = ") || strings.HasPrefix(line, "irVersion=") {
+			return FormatTOML
+		}
+		break
+	}
+	return FormatYAML
+}
+
+// ParseFormat parses data in the given format into a TaskList.
+func ParseFormat(data []byte, format Format) (*TaskList, error) {
+	return parseFormat("", data, format)
+}
+
+func parseFormat(file string, data []byte, format Format) (*TaskList, error) {
+	switch format {
+	case FormatYAML:
+		return parseYAML(file, data)
+	case FormatTOML:
+		return parseTOML(file, data)
+	default:
+		return parse(file, data)
+	}
+}
+
+// parseYAML decodes YAML by converting it to JSON, so that YAML documents
+// use exactly the same field names and semantics as TASKS.json.
+func parseYAML(file string, data []byte) (*TaskList, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, &ParseError{Op: "parse", Pos: yamlErrorPosition(file, err), Err: fmt.Errorf("%w: %v", ErrParseYAML, err)}
+	}
+	v, err := yamlValue(file, documentNode(&root), reflect.TypeOf(TaskList{}))
+	if err != nil {
+		return nil, err
+	}
+	tl, err := decodeGeneric(v)
+	if err != nil {
+		return nil, &ParseError{Op: "parse", Pos: Position{File: file}, Err: fmt.Errorf("%w: %v", ErrParseYAML, err)}
+	}
+	tl.sourceFile = file
+	tl.positions = make(map[string]Position)
+	indexYAMLPositions(file, documentNode(&root), "", tl.positions)
+	return tl, nil
+}
+
+// parseTOML decodes TOML by converting it to JSON. TOML documents record
+// the source file but not per-field positions.
+func parseTOML(file string, data []byte) (*TaskList, error) {
+	var v map[string]any
+	if err := toml.Unmarshal(data, &v); err != nil {
+		pe := &ParseError{Op: "parse", Pos: Position{File: file}, Err: fmt.Errorf("%w: %v", ErrParseTOML, err)}
+		var tomlErr toml.ParseError
+		if errors.As(err, &tomlErr) {
+			pe.Pos.Line = tomlErr.Position.Line
+			pe.Pos.Column = tomlErr.Position.Col
+		}
+		return nil, pe
+	}
+	tl, err := decodeGeneric(v)
+	if err != nil {
+		return nil, &ParseError{Op: "parse", Pos: Position{File: file}, Err: fmt.Errorf("%w: %v", ErrParseTOML, err)}
+	}
+	tl.sourceFile = file
+	return tl, nil
+}
+
+// decodeGeneric converts a generic decoded document into a TaskList via JSON.
+func decodeGeneric(v any) (*TaskList, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var tl TaskList
+	if err := json.Unmarshal(data, &tl); err != nil {
+		return nil, err
+	}
+	return &tl, nil
+}
+
+// yamlValue converts a YAML node into a generic value that decodes into a
+// value of type t via JSON. Scalars take the type of the field they decode
+// into, so unquoted values such as "irVersion: 1.0" or "id: 42" decode into
+// string fields. Values that do not fit their field are reported with the
+// node's position. A nil t accepts any value.
+func yamlValue(file string, n *yaml.Node, t reflect.Type) (any, error) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t != nil && t.Kind() == reflect.Interface {
+		t = nil
+	}
+	if n.Kind == yaml.AliasNode {
+		return yamlValue(file, n.Alias, t)
+	}
+	if n.Kind == 0 || n.Kind == yaml.DocumentNode { // Empty document
+		return nil, nil
+	}
+
+	switch n.Kind {
+	case yaml.MappingNode:
+		if t != nil && t.Kind() != reflect.Struct && t.Kind() != reflect.Map {
+			return nil, yamlTypeError(file, n, "a mapping", t)
+		}
+		m := make(map[string]any, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i].Value
+			v, err := yamlValue(file, n.Content[i+1], yamlFieldType(t, key))
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+		}
+		return m, nil
+	case yaml.SequenceNode:
+		var elem reflect.Type
+		if t != nil {
+			if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+				return nil, yamlTypeError(file, n, "a list", t)
+			}
+			elem = t.Elem()
+		}
+		list := make([]any, len(n.Content))
+		for i, item := range n.Content {
+			v, err := yamlValue(file, item, elem)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = v
+		}
+		return list, nil
+	}
+
+	if n.Tag == "!!null" {
+		return nil, nil
+	}
+	if t == nil {
+		var v any
+		if err := n.Decode(&v); err != nil {
+			return nil, &ParseError{Op: "parse", Pos: Position{File: file, Line: n.Line, Column: n.Column}, Err: fmt.Errorf("%w: %v", ErrParseYAML, err)}
+		}
+		return v, nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return n.Value, nil
+	case reflect.Slice:
+		// Lists with a custom JSON decoding, such as StringList, may also
+		// be written as a single value.
+		if reflect.PointerTo(t).Implements(reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()) {
+			return yamlValue(file, n, t.Elem())
+		}
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		v := reflect.New(t)
+		if err := n.Decode(v.Interface()); err == nil {
+			return v.Elem().Interface(), nil
+		}
+	}
+	return nil, yamlTypeError(file, n, fmt.Sprintf("%q", n.Value), t)
+}
+
+// yamlFieldType returns the type of the value stored under key in a value
+// of type t, matching struct fields by their JSON name, or nil if unknown.
+func yamlFieldType(t reflect.Type, key string) reflect.Type {
+	if t == nil {
+		return nil
+	}
+	if t.Kind() == reflect.Map {
+		return t.Elem()
+	}
+	var fold reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if name == key {
+			return f.Type
+		}
+		// encoding/json also matches names ignoring case.
+		if fold == nil && strings.EqualFold(name, key) {
+			fold = f.Type
+		}
+	}
+	return fold
+}
+
+// yamlTypeError reports a YAML value that cannot be decoded into type t.
+func yamlTypeError(file string, n *yaml.Node, what string, t reflect.Type) error {
+	kind := t.Kind().String()
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		kind = "mapping"
+	case reflect.Slice, reflect.Array:
+		kind = "list"
+	case reflect.Float32, reflect.Float64, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		kind = "number"
+	}
+	return &ParseError{
+		Op:  "parse",
+		Pos: Position{File: file, Line: n.Line, Column: n.Column},
+		Err: fmt.Errorf("%w: cannot use %s as a %s", ErrParseYAML, what, kind),
+	}
+}
+
+// documentNode returns the root content node of a YAML document.
+func documentNode(n *yaml.Node) *yaml.Node {
+	if n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+		return n.Content[0]
+	}
+	return n
+}
+
+// indexYAMLPositions records the position of every YAML value by field path.
+func indexYAMLPositions(file string, n *yaml.Node, path string, positions map[string]Position) {
+	positions[path] = Position{File: file, Line: n.Line, Column: n.Column}
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			child := n.Content[i].Value
+			if path != "" {
+				child = path + "." + child
+			}
+			indexYAMLPositions(file, n.Content[i+1], child, positions)
+		}
+	case yaml.SequenceNode:
+		for i, item := range n.Content {
+			indexYAMLPositions(file, item, fmt.Sprintf("%s[%d]", path, i), positions)
+		}
+	}
+}
+
+// yamlErrorPosition extracts the line from a YAML error message.
+func yamlErrorPosition(file string, err error) Position {
+	pos := Position{File: file}
+	var line int
+	msg := err.Error()
+	if i := strings.Index(msg, "line "); i >= 0 {
+		if _, scanErr := fmt.Sscanf(msg[i:], "line %d", &line); scanErr == nil {
+			pos.Line = line
+			pos.Column = 1
+		}
+	}
+	return pos
+}
+
+// Marshal serializes a TaskList in the given format. For JSON, comments
+// from a JSONC source are written back next to the fields they annotated.
+func Marshal(tl *TaskList, format Format) ([]byte, error) {
+	data, err := ToJSON(tl)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case FormatYAML:
+		return jsonToYAML(data)
+	case FormatTOML:
+		return jsonToTOML(data)
+	default:
+		return attachComments(data, tl.comments), nil
+	}
+}
+
+// jsonToYAML converts JSON to YAML, preserving field order. Multi-line
+// strings are written as literal blocks.
+func jsonToYAML(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	node, err := yamlNodeFromJSON(dec)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// yamlNodeFromJSON reads one JSON value from the decoder as a YAML node.
+func yamlNodeFromJSON(dec *json.Decoder) (*yaml.Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		if t == '[' {
+			node.Kind = yaml.SequenceNode
+		}
+		for dec.More() {
+			if node.Kind == yaml.MappingNode {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, _ := keyTok.(string)
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key})
+			}
+			child, err := yamlNodeFromJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+		if _, err := dec.Token(); err != nil { // closing delimiter
+			return nil, err
+		}
+		return node, nil
+	case string:
+		node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: t}
+		if strings.Contains(t, "\n") {
+			node.Style = yaml.LiteralStyle
+		}
+		return node, nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(t.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: t.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprintf("%t", t)}, nil
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+}
+
+// jsonToTOML converts JSON to TOML.
+func jsonToTOML(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v map[string]any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = ""
+	if err := enc.Encode(tomlValue(v)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// tomlValue converts json.Number values into native numbers for encoding.
+func tomlValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			t[k] = tomlValue(val)
+		}
+		return t
+	case []any:
+		for i, val := range t {
+			t[i] = tomlValue(val)
+		}
+		return t
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		f, _ := t.Float64()
+		return f
+	}
+	return v
+}
@@ -0,0 +1,207 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Includes: a task list may list other files in its top-level "include"
+// field. Paths are relative to the including file and may be glob patterns
+// such as "tasks/*.json". ParseFile appends the areas and tasks of each
+// included file, in order, and clears Include on the result so that the
+// returned TaskList is a single flattened list. Other top-level fields in
+// included files are ignored. Source positions of merged areas and tasks
+// point into the file they came from.
+
+// IncludedFiles returns the files that were merged into the task list by
+// ParseFile, in the order they were loaded.
+func (tl *TaskList) IncludedFiles() []string {
+	return tl.includedFiles
+}
+
+// TaskSource returns the file the task at index i was loaded from.
+func (tl *TaskList) TaskSource(i int) string {
+	return tl.Position(fmt.Sprintf("tasks[%d]", i)).File
+}
+
+// resolveIncludes loads the files listed in tl.Include and merges them into
+// tl. stack holds the absolute paths of the files currently being loaded,
+// and loaded the files already merged, both used to detect include cycles.
+func resolveIncludes(tl *TaskList, file string, stack, loaded map[string]bool) error {
+	includes := tl.Include
+	tl.Include = nil
+	dir := filepath.Dir(file)
+
+	for i, pattern := range includes {
+		pos := tl.Position(fmt.Sprintf("include[%d]", i))
+		files, err := includeFiles(dir, pattern)
+		if err != nil {
+			return &ParseError{Op: "include", Pos: pos, Err: err}
+		}
+		for _, path := range files {
+			abs, err := filepath.Abs(path)
+			if err != nil {
+				return &ParseError{Op: "include", Pos: pos, Err: fmt.Errorf("%w: %v", ErrReadFile, err)}
+			}
+			if stack[abs] {
+				if isGlob(pattern) {
+					continue // a glob may match the including file itself
+				}
+				return &ParseError{Op: "include", Pos: pos, Err: fmt.Errorf("%w: %s", ErrIncludeCycle, path)}
+			}
+			if loaded[abs] && isGlob(pattern) {
+				continue
+			}
+
+			child, err := parseFileIncludes(path, stack, loaded)
+			if err != nil {
+				return err
+			}
+			loaded[abs] = true
+			tl.merge(child)
+		}
+	}
+	return nil
+}
+
+// includeFiles expands an include entry into file paths. Glob patterns that
+// match nothing are allowed; plain paths must exist.
+func includeFiles(dir, pattern string) ([]string, error) {
+	path := pattern
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, filepath.FromSlash(pattern))
+	}
+	if !isGlob(pattern) {
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrReadFile, err)
+		}
+		return []string{path}, nil
+	}
+	matches, err := filepath.Glob(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad include pattern: %s", ErrInvalidFormat, pattern)
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+func isGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// merge appends the areas and tasks of an included task list, carrying over
+// their source positions and comments.
+func (tl *TaskList) merge(child *TaskList) {
+	if tl.positions == nil {
+		tl.positions = make(map[string]Position)
+	}
+	areaOffset, taskOffset := len(tl.Areas), len(tl.Tasks)
+
+	for path, pos := range child.positions {
+		switch {
+		case strings.HasPrefix(path, "areas["):
+			tl.positions[reindexPath(path, "areas", areaOffset)] = pos
+		case strings.HasPrefix(path, "tasks["):
+			tl.positions[reindexPath(path, "tasks", taskOffset)] = pos
+		}
+	}
+	// Formats without per-field positions still attribute tasks to their file
+	for i := range child.Areas {
+		if _, ok := child.positions[fmt.Sprintf("areas[%d]", i)]; !ok {
+			tl.positions[fmt.Sprintf("areas[%d]", areaOffset+i)] = Position{File: child.sourceFile}
+		}
+	}
+	for i := range child.Tasks {
+		if _, ok := child.positions[fmt.Sprintf("tasks[%d]", i)]; !ok {
+			tl.positions[fmt.Sprintf("tasks[%d]", taskOffset+i)] = Position{File: child.sourceFile}
+		}
+	}
+
+	// Comments on areas and tasks are keyed by ID, so they merge as is
+	for path, c := range itemComments(child.comments) {
+		if tl.comments == nil {
+			tl.comments = make(map[string]*jsonComments)
+		}
+		tl.comments[path] = c
+	}
+
+	tl.Areas = append(tl.Areas, child.Areas...)
+	tl.Tasks = append(tl.Tasks, child.Tasks...)
+	tl.includedFiles = append(tl.includedFiles, child.sourceFile)
+	tl.includedFiles = append(tl.includedFiles, child.includedFiles...)
+}
+
+// reindexPath shifts the leading array index of a path such as
+// "tasks[2].dependsOn[0]" by offset.
+func reindexPath(path, field string, offset int) string {
+	rest := path[len(field)+1:]
+	end := strings.IndexByte(rest, ']')
+	if end < 0 {
+		return path
+	}
+	var i int
+	if _, err := fmt.Sscanf(rest[:end], "%d", &i); err != nil {
+		return path
+	}
+	return fmt.Sprintf("%s[%d]%s", field, i+offset, rest[end+1:])
+}
+
+// SplitByArea splits a task list into one task list per declared area,
+// each holding the area and its tasks, plus a remainder holding everything
+// else: top-level fields and tasks without a declared area. Parts are
+// returned in area declaration order; areas without tasks are included.
+// Comments on areas and tasks move with them.
+func SplitByArea(tl *TaskList) (rest *TaskList, parts []*TaskList) {
+	rest = &TaskList{
+		IRVersion:    tl.IRVersion,
+		Project:      tl.Project,
+		Legend:       tl.Legend,
+		Statuses:     tl.Statuses,
+		Labels:       tl.Labels,
+		Phases:       tl.Phases,
+		Milestones:   tl.Milestones,
+		Sections:     tl.Sections,
+		Dependencies: tl.Dependencies,
+		comments:     tl.comments,
+	}
+	byArea := make(map[string]*TaskList, len(tl.Areas))
+	for _, area := range tl.Areas {
+		if _, ok := byArea[area.ID]; ok {
+			rest.Areas = append(rest.Areas, area) // duplicate, left for validation
+			continue
+		}
+		part := &TaskList{
+			IRVersion: tl.IRVersion,
+			Project:   tl.Project,
+			Areas:     []Area{area},
+			comments:  itemComments(tl.comments),
+		}
+		byArea[area.ID] = part
+		parts = append(parts, part)
+	}
+	for _, task := range tl.Tasks {
+		if part, ok := byArea[task.Area]; ok && task.Area != "" {
+			part.Tasks = append(part.Tasks, task)
+		} else {
+			rest.Tasks = append(rest.Tasks, task)
+		}
+	}
+	return rest, parts
+}
+
+// itemComments returns the comments attached to areas and tasks by ID.
+func itemComments(comments map[string]*jsonComments) map[string]*jsonComments {
+	var items map[string]*jsonComments
+	for path, c := range comments {
+		if strings.HasPrefix(path, "areas[#") || strings.HasPrefix(path, "tasks[#") {
+			if items == nil {
+				items = make(map[string]*jsonComments)
+			}
+			items[path] = c
+		}
+	}
+	return items
+}
@@ -0,0 +1,393 @@
+package tasks
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// JSONC support: TASKS.json may contain // line comments, /* block */
+// comments and trailing commas. Comments are stripped before decoding and
+// recorded by field path so they can be written back when the task list is
+// saved. Array elements that are objects with an "id" are keyed by ID rather
+// than index, so comments follow their task when tasks are reordered.
+
+// stripJSONC replaces comments and trailing commas with spaces, preserving
+// newlines so that byte offsets, lines and columns are unchanged.
+func stripJSONC(data []byte) []byte {
+	if !bytes.Contains(data, []byte("/")) && !bytes.Contains(data, []byte(",")) {
+		return data
+	}
+	out := make([]byte, len(data))
+	copy(out, data)
+
+	lastComma := -1
+	for i := 0; i < len(out); i++ {
+		switch c := out[i]; {
+		case c == '"':
+			lastComma = -1
+			i = skipJSONString(out, i)
+		case c == '/' && i+1 < len(out) && (out[i+1] == '/' || out[i+1] == '*'):
+			end := commentEnd(out, i)
+			blank(out[i:end])
+			i = end - 1
+		case c == ',':
+			lastComma = i
+		case c == '}' || c == ']':
+			if lastComma >= 0 {
+				out[lastComma] = ' '
+			}
+			lastComma = -1
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+		default:
+			lastComma = -1
+		}
+	}
+	return out
+}
+
+// skipJSONString returns the offset of the closing quote of the string
+// starting at offset i.
+func skipJSONString(data []byte, i int) int {
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return len(data)
+}
+
+// commentEnd returns the offset just past the comment starting at offset i.
+// Line comments end before the newline.
+func commentEnd(data []byte, i int) int {
+	if data[i+1] == '/' {
+		if j := bytes.IndexByte(data[i:], '\n'); j >= 0 {
+			return i + j
+		}
+		return len(data)
+	}
+	if j := bytes.Index(data[i+2:], []byte("*/")); j >= 0 {
+		return i + 2 + j + 2
+	}
+	return len(data)
+}
+
+// blank replaces every byte except newlines with a space.
+func blank(b []byte) {
+	for i := range b {
+		if b[i] != '\n' {
+			b[i] = ' '
+		}
+	}
+}
+
+// jsonComments holds the comments attached to a value.
+type jsonComments struct {
+	Leading  []string // Comments on lines before the value
+	Trailing string   // Comment on the same line after the value
+	End      []string // Comments before the closing bracket of an object or array
+}
+
+// jsoncLayout records comments (when reading source) or insertion offsets
+// (when writing output) by stable field path.
+type jsoncLayout struct {
+	data     []byte
+	pos      int
+	comments map[string]*jsonComments
+
+	// Offsets used when re-attaching comments to generated JSON.
+	memberStart map[string]int // offset of the line start of a member or element
+	lineEnd     map[string]int // offset of the end of the line where a value ends
+	closeStart  map[string]int // offset of the line start of a closing bracket
+}
+
+func newJSONCLayout(data []byte) *jsoncLayout {
+	return &jsoncLayout{
+		data:        data,
+		comments:    make(map[string]*jsonComments),
+		memberStart: make(map[string]int),
+		lineEnd:     make(map[string]int),
+		closeStart:  make(map[string]int),
+	}
+}
+
+// extractComments returns the comments in JSONC data keyed by field path,
+// or nil if there are none.
+func extractComments(data []byte) map[string]*jsonComments {
+	if !bytes.Contains(data, []byte("//")) && !bytes.Contains(data, []byte("/*")) {
+		return nil
+	}
+	l := newJSONCLayout(data)
+	l.root()
+	if len(l.comments) == 0 {
+		return nil
+	}
+	return l.comments
+}
+
+func (l *jsoncLayout) entry(path string) *jsonComments {
+	c, ok := l.comments[path]
+	if !ok {
+		c = &jsonComments{}
+		l.comments[path] = c
+	}
+	return c
+}
+
+// skip skips whitespace and collects comments. If sameLine is true, it stops
+// at the first newline.
+func (l *jsoncLayout) skip(sameLine bool) []string {
+	var comments []string
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		switch {
+		case c == '\n' && sameLine:
+			return comments
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			l.pos++
+		case c == '/' && l.pos+1 < len(l.data) && (l.data[l.pos+1] == '/' || l.data[l.pos+1] == '*'):
+			end := commentEnd(l.data, l.pos)
+			comments = append(comments, strings.TrimRight(string(l.data[l.pos:end]), " \t\r"))
+			l.pos = end
+		default:
+			return comments
+		}
+	}
+	return comments
+}
+
+// lineStart returns the offset of the start of the line containing offset.
+func (l *jsoncLayout) lineStart(offset int) int {
+	return bytes.LastIndexByte(l.data[:offset], '\n') + 1
+}
+
+// lineEndAt returns the offset of the newline at or after offset.
+func (l *jsoncLayout) lineEndAt(offset int) int {
+	if j := bytes.IndexByte(l.data[offset:], '\n'); j >= 0 {
+		return offset + j
+	}
+	return len(l.data)
+}
+
+func (l *jsoncLayout) root() {
+	if leading := l.skip(false); len(leading) > 0 {
+		l.entry("").Leading = leading
+	}
+	l.memberStart[""] = 0
+	l.value("")
+	if after := l.skip(false); len(after) > 0 {
+		l.entry("").Trailing = strings.Join(after, "\n")
+	}
+}
+
+// value scans a value and returns the string value of its "id" member if it
+// is an object with one.
+func (l *jsoncLayout) value(path string) string {
+	if l.pos >= len(l.data) {
+		return ""
+	}
+	switch l.data[l.pos] {
+	case '{':
+		return l.object(path)
+	case '[':
+		l.array(path)
+	case '"':
+		l.pos = skipJSONString(l.data, l.pos) + 1
+	default:
+		for l.pos < len(l.data) && !strings.ContainsRune(",]}/ \t\r\n", rune(l.data[l.pos])) {
+			l.pos++
+		}
+	}
+	return ""
+}
+
+// afterValue records the trailing comment and line end for a member.
+func (l *jsoncLayout) afterValue(path string) {
+	save := l.pos
+	trailing := l.skip(true)
+	if l.pos < len(l.data) && l.data[l.pos] == ',' {
+		l.pos++
+		trailing = append(trailing, l.skip(true)...)
+	} else if len(trailing) == 0 {
+		l.pos = save
+	}
+	if len(trailing) > 0 {
+		l.entry(path).Trailing = strings.Join(trailing, " ")
+	}
+	l.lineEnd[path] = l.lineEndAt(l.pos)
+}
+
+func (l *jsoncLayout) object(path string) string {
+	var id string
+	l.pos++ // {
+	for {
+		comments := l.skip(false)
+		if l.pos >= len(l.data) {
+			return id
+		}
+		if l.data[l.pos] == '}' {
+			if len(comments) > 0 {
+				l.entry(path).End = comments
+			}
+			l.closeStart[path] = l.lineStart(l.pos)
+			l.pos++
+			return id
+		}
+		if l.data[l.pos] != '"' {
+			l.pos++ // stray comma from a trailing comma
+			continue
+		}
+		keyStart := l.pos
+		keyEnd := skipJSONString(l.data, l.pos)
+		key := string(l.data[keyStart+1 : keyEnd])
+		l.pos = keyEnd + 1
+
+		child := key
+		if path != "" {
+			child = path + "." + key
+		}
+		if len(comments) > 0 {
+			l.entry(child).Leading = comments
+		}
+		l.memberStart[child] = l.lineStart(keyStart)
+
+		l.skip(false)
+		if l.pos < len(l.data) && l.data[l.pos] == ':' {
+			l.pos++
+		}
+		l.skip(false)
+		valueStart := l.pos
+		l.value(child)
+		if key == "id" && valueStart < len(l.data) && l.data[valueStart] == '"' {
+			id = string(l.data[valueStart+1 : l.pos-1])
+		}
+		l.afterValue(child)
+	}
+}
+
+func (l *jsoncLayout) array(path string) {
+	l.pos++ // [
+	for i := 0; ; {
+		comments := l.skip(false)
+		if l.pos >= len(l.data) {
+			return
+		}
+		switch l.data[l.pos] {
+		case ']':
+			if len(comments) > 0 {
+				l.entry(path).End = comments
+			}
+			l.closeStart[path] = l.lineStart(l.pos)
+			l.pos++
+			return
+		case ',':
+			l.pos++
+			continue
+		}
+
+		child := fmt.Sprintf("%s[%d]", path, i)
+		if len(comments) > 0 {
+			l.entry(child).Leading = comments
+		}
+		l.memberStart[child] = l.lineStart(l.pos)
+		if id := l.value(child); id != "" {
+			stable := fmt.Sprintf("%s[#%s]", path, id)
+			l.rename(child, stable)
+			child = stable
+		}
+		l.afterValue(child)
+		i++
+	}
+}
+
+// rename moves all recorded data under an index path to its stable ID path.
+func (l *jsoncLayout) rename(from, to string) {
+	renameKeys(l.comments, from, to)
+	renameKeys(l.memberStart, from, to)
+	renameKeys(l.lineEnd, from, to)
+	renameKeys(l.closeStart, from, to)
+}
+
+func renameKeys[V any](m map[string]V, from, to string) {
+	for k, v := range m {
+		if k == from || strings.HasPrefix(k, from+".") || strings.HasPrefix(k, from+"[") {
+			delete(m, k)
+			m[to+k[len(from):]] = v
+		}
+	}
+}
+
+// attachComments inserts comments into indented JSON generated by
+// json.MarshalIndent. Comments whose path no longer exists are dropped.
+func attachComments(data []byte, comments map[string]*jsonComments) []byte {
+	if len(comments) == 0 {
+		return data
+	}
+	l := newJSONCLayout(data)
+	l.root()
+
+	type insertion struct {
+		offset int
+		order  int
+		text   string
+	}
+	var inserts []insertion
+	indentAt := func(offset int) string {
+		end := offset
+		for end < len(data) && (data[end] == ' ' || data[end] == '\t') {
+			end++
+		}
+		return string(data[offset:end])
+	}
+	lines := func(indent string, comments []string) string {
+		var sb strings.Builder
+		for _, c := range comments {
+			sb.WriteString(indent + c + "\n")
+		}
+		return sb.String()
+	}
+
+	paths := make([]string, 0, len(comments))
+	for path := range comments {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		c := comments[path]
+		if start, ok := l.memberStart[path]; ok && len(c.Leading) > 0 {
+			inserts = append(inserts, insertion{start, 1, lines(indentAt(start), c.Leading)})
+		}
+		if c.Trailing != "" {
+			if path == "" {
+				inserts = append(inserts, insertion{len(data), 3, "\n" + c.Trailing})
+			} else if end, ok := l.lineEnd[path]; ok {
+				inserts = append(inserts, insertion{end, 0, " " + c.Trailing})
+			}
+		}
+		if start, ok := l.closeStart[path]; ok && len(c.End) > 0 {
+			inserts = append(inserts, insertion{start, 2, lines(indentAt(start)+"  ", c.End)})
+		}
+	}
+
+	sort.SliceStable(inserts, func(i, j int) bool {
+		if inserts[i].offset != inserts[j].offset {
+			return inserts[i].offset < inserts[j].offset
+		}
+		return inserts[i].order < inserts[j].order
+	})
+
+	var out bytes.Buffer
+	prev := 0
+	for _, ins := range inserts {
+		out.Write(data[prev:ins.offset])
+		out.WriteString(ins.text)
+		prev = ins.offset
+	}
+	out.Write(data[prev:])
+	return out.Bytes()
+}
@@ -0,0 +1,174 @@
+package tasks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LabelDef declares a label. Labels are free-form tags on tasks for
+// cross-cutting concerns such as "security" or "perf"; declaring them in
+// the top-level "labels" field is optional, but once any are declared,
+// tasks may only use declared labels.
+type LabelDef struct {
+	ID          string `json:"id"`
+	Name        string `json:"name,omitempty"`  // Display name; defaults to the ID
+	Color       string `json:"color,omitempty"` // A color name or #rrggbb
+	Description string `json:"description,omitempty"`
+}
+
+// Label returns the declared label with the given ID.
+func (tl *TaskList) Label(id string) (LabelDef, bool) {
+	for _, l := range tl.Labels {
+		if l.ID == id {
+			return l, true
+		}
+	}
+	return LabelDef{}, false
+}
+
+// LabelName returns the display name of a label: its declared name, or its
+// ID if it is not declared or has no name.
+func (tl *TaskList) LabelName(id string) string {
+	if l, ok := tl.Label(id); ok && l.Name != "" {
+		return l.Name
+	}
+	return id
+}
+
+// LabelIDs returns the labels of the task list: declared labels in
+// declaration order, followed by undeclared labels used by tasks, sorted.
+func (tl *TaskList) LabelIDs() []string {
+	seen := make(map[string]bool)
+	var ids, undeclared []string
+	for _, l := range tl.Labels {
+		if !seen[l.ID] {
+			seen[l.ID] = true
+			ids = append(ids, l.ID)
+		}
+	}
+	for _, task := range tl.Tasks {
+		for _, label := range task.Labels {
+			if label != "" && !seen[label] {
+				seen[label] = true
+				undeclared = append(undeclared, label)
+			}
+		}
+	}
+	sort.Strings(undeclared)
+	return append(ids, undeclared...)
+}
+
+// TasksByLabel returns tasks grouped by label. A task with several labels
+// appears in each of their groups; tasks without labels are grouped under
+// "_unspecified".
+func (tl *TaskList) TasksByLabel() map[string][]Task {
+	result := make(map[string][]Task)
+	for _, task := range tl.Tasks {
+		if len(task.Labels) == 0 {
+			result["_unspecified"] = append(result["_unspecified"], task)
+			continue
+		}
+		for _, label := range task.Labels {
+			result[label] = append(result[label], task)
+		}
+	}
+	return result
+}
+
+// HasLabel returns true if the task has the label.
+func (t Task) HasLabel(label string) bool {
+	for _, l := range t.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// LabelFilter selects tasks by label: tasks with any of the Include labels
+// (or any task if Include is empty) and none of the Exclude labels.
+type LabelFilter struct {
+	Include []string
+	Exclude []string
+}
+
+// ParseLabelFilter builds a label filter from command-line values. Values
+// prefixed with "!" are excluded; others are included.
+func ParseLabelFilter(values []string) LabelFilter {
+	var f LabelFilter
+	for _, v := range values {
+		if label, ok := strings.CutPrefix(v, "!"); ok {
+			f.Exclude = append(f.Exclude, label)
+		} else {
+			f.Include = append(f.Include, v)
+		}
+	}
+	return f
+}
+
+// IsEmpty returns true if the filter selects every task.
+func (f LabelFilter) IsEmpty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0
+}
+
+// Match returns true if the filter selects the task.
+func (f LabelFilter) Match(task Task) bool {
+	for _, label := range f.Exclude {
+		if task.HasLabel(label) {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, label := range f.Include {
+		if task.HasLabel(label) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns a shallow copy of the task list holding only the tasks
+// keep returns true for. Other top-level fields are shared with tl; source
+// positions are dropped, since task indexes change.
+func (tl *TaskList) Filter(keep func(Task) bool) *TaskList {
+	filtered := *tl
+	filtered.positions = nil
+	filtered.Tasks = nil
+	for _, task := range tl.Tasks {
+		if keep(task) {
+			filtered.Tasks = append(filtered.Tasks, task)
+		}
+	}
+	return &filtered
+}
+
+// validateLabels reports invalid label declarations, empty task labels and,
+// if labels are declared, task labels that are not.
+func validateLabels(tl *TaskList, report func(rule Rule, field, message string), reportTask func(i int, rule Rule, field, message string)) {
+	declared := make(map[string]bool)
+	for i, l := range tl.Labels {
+		prefix := fmt.Sprintf("labels[%d]", i)
+		if l.ID == "" {
+			report(RuleRequiredField, prefix+".id", "required field is missing")
+		} else if declared[l.ID] {
+			report(RuleDuplicateID, prefix+".id", fmt.Sprintf("duplicate label: %s", l.ID))
+		} else {
+			declared[l.ID] = true
+		}
+	}
+
+	for i, task := range tl.Tasks {
+		for j, label := range task.Labels {
+			field := fmt.Sprintf("tasks[%d].labels[%d]", i, j)
+			switch {
+			case label == "":
+				reportTask(i, RuleRequiredField, field, "required field is missing")
+			case len(tl.Labels) > 0 && !declared[label]:
+				reportTask(i, RuleUnknownLabel, field, fmt.Sprintf("references undeclared label: %s", label))
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package tasks
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// LinkKind is the kind of resource a task link points to.
+type LinkKind string
+
+const (
+	LinkIssue LinkKind = "issue" // Issue or ticket
+	LinkPR    LinkKind = "pr"    // Pull or merge request
+	LinkDoc   LinkKind = "doc"   // Design doc or other document
+	LinkADR   LinkKind = "adr"   // Architecture decision record
+	LinkOther LinkKind = "other"
+)
+
+// LinkKinds returns the known link kinds.
+func LinkKinds() []LinkKind {
+	return []LinkKind{LinkIssue, LinkPR, LinkDoc, LinkADR, LinkOther}
+}
+
+// Link is a reference from a task to an external resource such as the
+// issue tracking it or the pull request implementing it. Links without a
+// kind are treated as LinkOther.
+type Link struct {
+	Kind  LinkKind `json:"kind,omitempty"`
+	URL   string   `json:"url"`
+	Title string   `json:"title,omitempty"`
+}
+
+// PrimaryIssue returns the first issue link of the task.
+func (t Task) PrimaryIssue() (Link, bool) {
+	for _, l := range t.Links {
+		if l.Kind == LinkIssue {
+			return l, true
+		}
+	}
+	return Link{}, false
+}
+
+func isValidLinkKind(kind LinkKind) bool {
+	for _, k := range LinkKinds() {
+		if kind == k {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidURL returns true if s is an absolute URL with a scheme and host,
+// such as https://github.com/org/repo/issues/1.
+func isValidURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+// validateLinks reports task links without a URL, with a malformed URL or
+// with an unknown kind.
+func validateLinks(tl *TaskList, reportTask func(i int, rule Rule, field, message string)) {
+	for i, task := range tl.Tasks {
+		for j, l := range task.Links {
+			prefix := fmt.Sprintf("tasks[%d].links[%d]", i, j)
+			switch {
+			case l.URL == "":
+				reportTask(i, RuleRequiredField, prefix+".url", "required field is missing")
+			case !isValidURL(l.URL):
+				reportTask(i, RuleInvalidLink, prefix+".url", fmt.Sprintf("invalid URL: %s (expected an absolute URL such as https://...)", l.URL))
+			}
+			if l.Kind != "" && !isValidLinkKind(l.Kind) {
+				reportTask(i, RuleInvalidLink, prefix+".kind", fmt.Sprintf("invalid link kind: %s (expected issue, pr, doc, adr or other)", l.Kind))
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package tasks
+
+// Milestone is a release on the roadmap. Milestones are listed in the
+// top-level "versionHistory" field, in roadmap order, and tasks are
+// scheduled for one by setting Task.Milestone to its version.
+type Milestone struct {
+	Version string `json:"version"`
+	Date    string `json:"date,omitempty"`   // Target or release date (YYYY-MM-DD)
+	Status  Status `json:"status,omitempty"` // Derived from the milestone's tasks if unset
+	Summary string `json:"summary,omitempty"`
+}
+
+// Milestone returns the milestone with the given version.
+func (tl *TaskList) Milestone(version string) (Milestone, bool) {
+	for _, m := range tl.Milestones {
+		if m.Version == version {
+			return m, true
+		}
+	}
+	return Milestone{}, false
+}
+
+// TasksByMilestone returns tasks grouped by milestone version. Tasks
+// without a milestone are grouped under "_unspecified".
+func (tl *TaskList) TasksByMilestone() map[string][]Task {
+	result := make(map[string][]Task)
+	for _, task := range tl.Tasks {
+		m := task.Milestone
+		if m == "" {
+			m = "_unspecified"
+		}
+		result[m] = append(result[m], task)
+	}
+	return result
+}
+
+// MilestoneStatus returns the status of a milestone. If the milestone has
+// no explicit status, it is derived from its tasks, ignoring cancelled ones:
+// completed if all are done, in progress if any are started or done, future
+// if all are future, and planned otherwise.
+func (tl *TaskList) MilestoneStatus(m Milestone) Status {
+	if m.Status != "" {
+		return m.Status
+	}
+	var milestoneTasks []Task
+	for _, task := range tl.Tasks {
+		if task.Milestone == m.Version {
+			milestoneTasks = append(milestoneTasks, task)
+		}
+	}
+	return derivedStatus(milestoneTasks, tl.StatusRegistry())
+}
+
+// derivedStatus returns the overall status of a group of tasks, ignoring
+// cancelled tasks: completed if all are done, in progress if any are done
+// or started, future if all are proposed, cancelled if all tasks are
+// cancelled, and planned otherwise (including for no tasks).
+func derivedStatus(group []Task, statuses *StatusRegistry) Status {
+	var done, started, future, cancelled int
+	for _, task := range group {
+		switch {
+		case statuses.IsCancelled(task.Status):
+			cancelled++
+		case statuses.IsDone(task.Status):
+			done++
+		case statuses.IsProposed(task.Status):
+			future++
+		case statuses.IsInProgress(task.Status):
+			started++
+		}
+	}
+	active := len(group) - cancelled
+	switch {
+	case len(group) == 0:
+		return StatusPlanned
+	case active == 0:
+		return StatusCancelled
+	case done == active:
+		return StatusCompleted
+	case done+started > 0:
+		return StatusInProgress
+	case future == active:
+		return StatusFuture
+	}
+	return StatusPlanned
+}
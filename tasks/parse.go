@@ -2,31 +2,119 @@ package tasks
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 )
 
-// ParseFile reads and parses a TASKS.json file.
+// ParseFile reads and parses a task list file. The format (JSON, YAML or
+// TOML) is detected from the file extension or content. Files listed in
+// Include are parsed and merged into the result.
 func ParseFile(path string) (*TaskList, error) {
+	return parseFileIncludes(path, make(map[string]bool), make(map[string]bool))
+}
+
+func parseFileIncludes(path string, stack, loaded map[string]bool) (*TaskList, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrReadFile, err)
 	}
-	return Parse(data)
+	tl, err := parseFormat(path, data, DetectFormat(path, data))
+	if err != nil {
+		return nil, err
+	}
+	if len(tl.Include) == 0 {
+		return tl, nil
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReadFile, err)
+	}
+	stack[abs], loaded[abs] = true, true
+	defer delete(stack, abs)
+	if err := resolveIncludes(tl, path, stack, loaded); err != nil {
+		return nil, err
+	}
+	return tl, nil
 }
 
-// Parse parses JSON data into a TaskList.
+// Parse parses JSON data into a TaskList. Comments (// and /* */) and
+// trailing commas are accepted; comments are kept with the TaskList and
+// written back by WriteFile and Marshal.
+// The returned TaskList records source positions for each field path.
 func Parse(data []byte) (*TaskList, error) {
+	return parse("", data)
+}
+
+func parse(file string, data []byte) (*TaskList, error) {
+	// Stripping preserves byte offsets, so positions refer to the original
+	stripped := stripJSONC(data)
 	var tl TaskList
-	if err := json.Unmarshal(data, &tl); err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrParseJSON, err)
+	if err := json.Unmarshal(stripped, &tl); err != nil {
+		return nil, newJSONParseError(file, stripped, err)
 	}
+	tl.setSource(file, stripped)
+	tl.comments = extractComments(data)
 	return &tl, nil
 }
 
-// WriteFile writes a TaskList to a JSON file.
+// newJSONParseError wraps a JSON decoding error with its source position.
+func newJSONParseError(file string, data []byte, err error) *ParseError {
+	pe := &ParseError{
+		Op:  "parse",
+		Pos: Position{File: file},
+		Err: fmt.Errorf("%w: %v", ErrParseJSON, err),
+	}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		pe.Pos = offsetPosition(file, data, int(syntaxErr.Offset))
+	case errors.As(err, &typeErr):
+		pe.Pos = offsetPosition(file, data, int(typeErr.Offset))
+		// Prefer the start of the offending value when the field is known
+		if pos, ok := indexPositions(file, data)[jsonFieldPath(typeErr.Field)]; ok {
+			pe.Pos = pos
+		}
+	}
+	return pe
+}
+
+// jsonFieldPath converts an encoding/json field path ("tasks.0.id") into
+// a task list field path ("tasks[0].id").
+func jsonFieldPath(field string) string {
+	parts := strings.Split(field, ".")
+	var sb strings.Builder
+	for i, part := range parts {
+		if _, err := strconv.Atoi(part); err == nil && i > 0 {
+			fmt.Fprintf(&sb, "[%s]", part)
+			continue
+		}
+		if i > 0 {
+			sb.WriteString(".")
+		}
+		sb.WriteString(part)
+	}
+	return sb.String()
+}
+
+// WriteFile writes a TaskList to a file. The format is chosen by file
+// extension (.json, .yaml, .yml, .toml), defaulting to JSON.
 func WriteFile(path string, tl *TaskList) error {
-	data, err := json.MarshalIndent(tl, "", "  ")
+	format := FormatFromPath(path)
+	if format == "" {
+		format = FormatJSON
+	}
+	return WriteFileFormat(path, tl, format)
+}
+
+// WriteFileFormat writes a TaskList to a file in the given format.
+func WriteFileFormat(path string, tl *TaskList, format Format) error {
+	data, err := Marshal(tl, format)
 	if err != nil {
 		return fmt.Errorf("%w: %v", ErrWriteFile, err)
 	}
@@ -36,7 +124,8 @@ func WriteFile(path string, tl *TaskList) error {
 	return nil
 }
 
-// ToJSON converts a TaskList to JSON bytes.
+// ToJSON converts a TaskList to JSON bytes. Comments from a JSONC source
+// are not included; use Marshal with FormatJSON to keep them.
 func ToJSON(tl *TaskList) ([]byte, error) {
 	return json.MarshalIndent(tl, "", "  ")
 }
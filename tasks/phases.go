@@ -0,0 +1,51 @@
+package tasks
+
+import "fmt"
+
+// Phase describes a numbered phase. Phases are listed in the top-level
+// "phases" field; tasks refer to a phase by its number in Task.Phase.
+type Phase struct {
+	Number      int    `json:"number"`
+	ID          string `json:"id,omitempty"` // Optional stable identifier
+	Name        string `json:"name"`         // Display name, e.g. "Phase 1: Foundation"
+	Description string `json:"description,omitempty"`
+	TargetDate  string `json:"targetDate,omitempty"` // YYYY-MM-DD
+	Status      Status `json:"status,omitempty"`     // Derived from the phase's tasks if unset
+}
+
+// Phase returns the declared phase with the given number.
+func (tl *TaskList) Phase(number int) (Phase, bool) {
+	for _, p := range tl.Phases {
+		if p.Number == number {
+			return p, true
+		}
+	}
+	return Phase{}, false
+}
+
+// PhaseName returns the display name of a phase: its declared name, or
+// "Phase N" if it is not declared or has no name. Phase 0 is "Unphased".
+func (tl *TaskList) PhaseName(number int) string {
+	if p, ok := tl.Phase(number); ok && p.Name != "" {
+		return p.Name
+	}
+	if number == 0 {
+		return "Unphased"
+	}
+	return fmt.Sprintf("Phase %d", number)
+}
+
+// PhaseStatus returns the status of a phase: its declared status if set,
+// otherwise derived from its tasks as for MilestoneStatus.
+func (tl *TaskList) PhaseStatus(number int) Status {
+	if p, ok := tl.Phase(number); ok && p.Status != "" {
+		return p.Status
+	}
+	var phaseTasks []Task
+	for _, task := range tl.Tasks {
+		if task.Phase == number {
+			phaseTasks = append(phaseTasks, task)
+		}
+	}
+	return derivedStatus(phaseTasks, tl.StatusRegistry())
+}
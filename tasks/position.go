@@ -0,0 +1,241 @@
+package tasks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Position is a location in a source file. Line and Column are 1-based;
+// Column counts characters, not bytes.
+type Position struct {
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+// IsValid reports whether the position has a line number.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String returns the position in file:line:col form.
+func (p Position) String() string {
+	if !p.IsValid() {
+		return p.File
+	}
+	if p.File == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// Position returns the source position of the value at a field path such as
+// "tasks[3].dependsOn". If the path itself is absent (e.g. a missing field),
+// the position of the nearest enclosing value is returned. The zero Position
+// is returned if the task list was not parsed from source.
+func (tl *TaskList) Position(path string) Position {
+	if tl.positions == nil {
+		return Position{File: tl.sourceFile}
+	}
+	for {
+		if pos, ok := tl.positions[path]; ok {
+			return pos
+		}
+		if path == "" {
+			return Position{File: tl.sourceFile}
+		}
+		path = parentPath(path)
+	}
+}
+
+// SourceFile returns the file the task list was parsed from, if any.
+func (tl *TaskList) SourceFile() string {
+	return tl.sourceFile
+}
+
+// setSource records the source file and position index on a parsed task list.
+func (tl *TaskList) setSource(file string, data []byte) {
+	tl.sourceFile = file
+	tl.positions = indexPositions(file, data)
+}
+
+// parentPath strips the last segment from a field path.
+func parentPath(path string) string {
+	i := strings.LastIndexAny(path, ".[")
+	if i < 0 {
+		return ""
+	}
+	return path[:i]
+}
+
+// offsetPosition converts a byte offset into a line and column.
+func offsetPosition(file string, data []byte, offset int) Position {
+	return newLineIndex(data).position(file, offset)
+}
+
+// lineIndex maps byte offsets to line and column numbers.
+type lineIndex struct {
+	data       []byte
+	lineStarts []int
+}
+
+func newLineIndex(data []byte) lineIndex {
+	starts := []int{0}
+	for i, b := range data {
+		if b == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return lineIndex{data: data, lineStarts: starts}
+}
+
+func (li lineIndex) position(file string, offset int) Position {
+	if offset > len(li.data) {
+		offset = len(li.data)
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	line := sort.Search(len(li.lineStarts), func(i int) bool {
+		return li.lineStarts[i] > offset
+	})
+	lineStart := li.lineStarts[line-1]
+	return Position{File: file, Line: line, Column: utf8.RuneCount(li.data[lineStart:offset]) + 1}
+}
+
+// indexPositions scans JSON data and records the position of every value by
+// field path. The data is assumed to be syntactically valid; scanning stops
+// quietly at the first unexpected byte.
+func indexPositions(file string, data []byte) map[string]Position {
+	s := &positionScanner{data: data, offsets: make(map[string]int)}
+	s.value("")
+
+	lines := newLineIndex(data)
+	positions := make(map[string]Position, len(s.offsets))
+	for path, offset := range s.offsets {
+		positions[path] = lines.position(file, offset)
+	}
+	return positions
+}
+
+// positionScanner is a minimal JSON scanner that records value offsets.
+type positionScanner struct {
+	data    []byte
+	pos     int
+	offsets map[string]int
+}
+
+func (s *positionScanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (s *positionScanner) value(path string) bool {
+	s.skipSpace()
+	if s.pos >= len(s.data) {
+		return false
+	}
+	s.offsets[path] = s.pos
+
+	switch s.data[s.pos] {
+	case '{':
+		return s.object(path)
+	case '[':
+		return s.array(path)
+	case '"':
+		_, ok := s.str()
+		return ok
+	default:
+		for s.pos < len(s.data) && !strings.ContainsRune(",]} \t\r\n", rune(s.data[s.pos])) {
+			s.pos++
+		}
+		return true
+	}
+}
+
+func (s *positionScanner) object(path string) bool {
+	s.pos++ // {
+	for {
+		s.skipSpace()
+		if s.pos >= len(s.data) {
+			return false
+		}
+		switch s.data[s.pos] {
+		case '}':
+			s.pos++
+			return true
+		case ',':
+			s.pos++
+			continue
+		case '"':
+		default:
+			return false
+		}
+		key, ok := s.str()
+		if !ok {
+			return false
+		}
+		s.skipSpace()
+		if s.pos >= len(s.data) || s.data[s.pos] != ':' {
+			return false
+		}
+		s.pos++
+		child := key
+		if path != "" {
+			child = path + "." + key
+		}
+		if !s.value(child) {
+			return false
+		}
+	}
+}
+
+func (s *positionScanner) array(path string) bool {
+	s.pos++ // [
+	for i := 0; ; {
+		s.skipSpace()
+		if s.pos >= len(s.data) {
+			return false
+		}
+		switch s.data[s.pos] {
+		case ']':
+			s.pos++
+			return true
+		case ',':
+			s.pos++
+			continue
+		}
+		if !s.value(fmt.Sprintf("%s[%d]", path, i)) {
+			return false
+		}
+		i++
+	}
+}
+
+// str scans a JSON string and returns its raw contents without unescaping.
+// Keys in task list documents never require unescaping.
+func (s *positionScanner) str() (string, bool) {
+	s.pos++ // opening quote
+	start := s.pos
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case '\\':
+			s.pos += 2
+		case '"':
+			str := string(s.data[start:s.pos])
+			s.pos++
+			return str, true
+		default:
+			s.pos++
+		}
+	}
+	return "", false
+}
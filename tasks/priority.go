@@ -0,0 +1,106 @@
+package tasks
+
+import "sort"
+
+// Priority is the urgency of a task. Tasks without a priority rank below
+// all others.
+type Priority string
+
+const (
+	PriorityCritical Priority = "critical"
+	PriorityHigh     Priority = "high"
+	PriorityMedium   Priority = "medium"
+	PriorityLow      Priority = "low"
+)
+
+// Priorities returns the priorities from most to least urgent.
+func Priorities() []Priority {
+	return []Priority{PriorityCritical, PriorityHigh, PriorityMedium, PriorityLow}
+}
+
+// Rank returns the position of a priority from most to least urgent.
+// Unset and unknown priorities rank last.
+func (p Priority) Rank() int {
+	for i, q := range Priorities() {
+		if p == q {
+			return i
+		}
+	}
+	return len(Priorities())
+}
+
+// IsValid returns true if the priority is known.
+func (p Priority) IsValid() bool {
+	return p.Rank() < len(Priorities())
+}
+
+// DueDate returns the date a task is due (YYYY-MM-DD): its own dueDate,
+// otherwise the date of its milestone, otherwise the target date of its
+// phase. It is empty if none is set.
+func (tl *TaskList) DueDate(task Task) string {
+	if task.DueDate != "" {
+		return task.DueDate
+	}
+	if m, ok := tl.Milestone(task.Milestone); task.Milestone != "" && ok && m.Date != "" {
+		return m.Date
+	}
+	if p, ok := tl.Phase(task.Phase); task.Phase > 0 && ok {
+		return p.TargetDate
+	}
+	return ""
+}
+
+// DependencyOrder returns the position of each task ID in dependency order:
+// every task comes after the tasks it depends on, and otherwise tasks keep
+// their order in the list. Tasks in a dependency cycle, and tasks that
+// depend on them, follow all others in list order. Dependencies on unknown
+// or cross-project tasks are ignored.
+func (tl *TaskList) DependencyOrder() map[string]int {
+	index := make(map[string]int, len(tl.Tasks))
+	for i, task := range tl.Tasks {
+		if _, dup := index[task.ID]; !dup {
+			index[task.ID] = i
+		}
+	}
+
+	pending := make([]int, len(tl.Tasks))
+	dependents := make([][]int, len(tl.Tasks))
+	for i, task := range tl.Tasks {
+		for _, dep := range task.DependsOn {
+			if j, ok := index[dep]; ok && j != i {
+				pending[i]++
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+	}
+
+	// Repeatedly take the earliest task whose dependencies are all placed.
+	order := make(map[string]int, len(tl.Tasks))
+	placed := make([]bool, len(tl.Tasks))
+	var ready []int
+	for i := range tl.Tasks {
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	for len(ready) > 0 {
+		sort.Ints(ready)
+		i := ready[0]
+		ready = ready[1:]
+		placed[i] = true
+		if _, ok := order[tl.Tasks[i].ID]; !ok {
+			order[tl.Tasks[i].ID] = len(order)
+		}
+		for _, j := range dependents[i] {
+			if pending[j]--; pending[j] == 0 {
+				ready = append(ready, j)
+			}
+		}
+	}
+	for i, task := range tl.Tasks {
+		if _, ok := order[task.ID]; !ok && !placed[i] {
+			order[task.ID] = len(order)
+		}
+	}
+	return order
+}
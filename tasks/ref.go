@@ -0,0 +1,60 @@
+package tasks
+
+import "strings"
+
+// RefSeparator separates the project key from the task ID in a qualified
+// cross-project reference such as "other-project#task-id".
+const RefSeparator = "#"
+
+// Ref is a qualified reference to a task in another project.
+type Ref struct {
+	Project string // Project key, as listed in a manifest
+	ID      string // Task ID within the project
+}
+
+// ParseRef parses a qualified reference. ok is false for local task IDs.
+func ParseRef(s string) (ref Ref, ok bool) {
+	project, id, found := strings.Cut(s, RefSeparator)
+	if !found || project == "" || id == "" {
+		return Ref{}, false
+	}
+	return Ref{Project: project, ID: id}, true
+}
+
+// String returns the reference in project#id form.
+func (r Ref) String() string {
+	return r.Project + RefSeparator + r.ID
+}
+
+// ProjectResolver looks up the task lists of other projects by key, so that
+// qualified references can be checked and labeled.
+type ProjectResolver interface {
+	ResolveProject(key string) (*TaskList, error)
+}
+
+// ResolveRef returns the task a qualified reference points to. ok is false
+// if the project cannot be resolved or has no such task; err is non-nil
+// only if the project itself is unavailable.
+func ResolveRef(r ProjectResolver, ref Ref) (task Task, ok bool, err error) {
+	if r == nil {
+		return Task{}, false, ErrUnresolvedReference
+	}
+	tl, err := r.ResolveProject(ref.Project)
+	if err != nil {
+		return Task{}, false, err
+	}
+	if task, ok := tl.TaskByID(ref.ID); ok {
+		return task, true, nil
+	}
+	return Task{}, false, nil
+}
+
+// TaskByID returns the first task with the given ID.
+func (tl *TaskList) TaskByID(id string) (Task, bool) {
+	for _, task := range tl.Tasks {
+		if task.ID == id {
+			return task, true
+		}
+	}
+	return Task{}, false
+}
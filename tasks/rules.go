@@ -0,0 +1,141 @@
+package tasks
+
+import "sort"
+
+// Rule is a stable code identifying a validation check.
+type Rule string
+
+// Validation rules.
+const (
+	RuleParseError            Rule = "parse-error"
+	RuleRequiredField         Rule = "required-field"
+	RuleIRVersion             Rule = "ir-version"
+	RuleDuplicateID           Rule = "duplicate-id"
+	RuleInvalidStatus         Rule = "invalid-status"
+	RuleInvalidPhase          Rule = "invalid-phase"
+	RuleInvalidType           Rule = "invalid-type"
+	RuleInvalidPriority       Rule = "invalid-priority"
+	RuleUnknownDependency     Rule = "unknown-dependency"
+	RuleUnresolvedReference   Rule = "unresolved-reference"
+	RuleUnknownArea           Rule = "unknown-area"
+	RuleUnknownPhase          Rule = "unknown-phase"
+	RuleUnknownMilestone      Rule = "unknown-milestone"
+	RuleUnknownExternal       Rule = "unknown-external"
+	RuleUnknownLabel          Rule = "unknown-label"
+	RuleUnknownBlocker        Rule = "unknown-blocker"
+	RuleStatusReason          Rule = "status-reason"
+	RuleInvalidDate           Rule = "invalid-date"
+	RuleInvalidContent        Rule = "invalid-content"
+	RuleInvalidLink           Rule = "invalid-link"
+	RuleUndeclaredArea        Rule = "undeclared-area"
+	RulePhaseOrder            Rule = "phase-order"
+	RuleCompletedDependency   Rule = "completed-dependency"
+	RuleCompletedOpenSubtasks Rule = "completed-open-subtasks"
+)
+
+// RuleInfo describes a validation rule.
+type RuleInfo struct {
+	Code        Rule
+	Severity    Severity
+	Description string
+	Err         error
+}
+
+var ruleRegistry = map[Rule]RuleInfo{
+	RuleParseError:            {RuleParseError, SeverityError, "Files must be readable and well-formed JSON, YAML or TOML", ErrInvalidFormat},
+	RuleRequiredField:         {RuleRequiredField, SeverityError, "Required fields must be present", ErrMissingRequiredField},
+	RuleIRVersion:             {RuleIRVersion, SeverityError, "irVersion must be a supported version", ErrInvalidIRVersion},
+	RuleDuplicateID:           {RuleDuplicateID, SeverityError, "Task, area, label, phase, milestone, section, dependency and status IDs must be unique", ErrDuplicateID},
+	RuleInvalidStatus:         {RuleInvalidStatus, SeverityError, "Statuses must be built-in or declared in statuses or legend", ErrInvalidStatus},
+	RuleInvalidPhase:          {RuleInvalidPhase, SeverityError, "Phase numbers must be non-negative", ErrInvalidFormat},
+	RuleInvalidType:           {RuleInvalidType, SeverityError, "Task type must be a structured-changelog change type", ErrInvalidType},
+	RuleInvalidPriority:       {RuleInvalidPriority, SeverityError, "Task priority must be critical, high, medium or low", ErrInvalidFormat},
+	RuleUnknownDependency:     {RuleUnknownDependency, SeverityError, "dependsOn must reference existing tasks", ErrInvalidReference},
+	RuleUnresolvedReference:   {RuleUnresolvedReference, SeverityWarning, "Cross-project references should resolve through the manifest", ErrUnresolvedReference},
+	RuleUnknownArea:           {RuleUnknownArea, SeverityError, "Task area must reference a declared area", ErrInvalidReference},
+	RuleUnknownPhase:          {RuleUnknownPhase, SeverityError, "Task phase must reference a declared phase when phases are declared", ErrInvalidReference},
+	RuleUnknownMilestone:      {RuleUnknownMilestone, SeverityError, "Task milestone must reference a milestone in versionHistory", ErrInvalidReference},
+	RuleUnknownExternal:       {RuleUnknownExternal, SeverityError, "Task waitsOn must reference a declared external dependency", ErrInvalidReference},
+	RuleUnknownLabel:          {RuleUnknownLabel, SeverityError, "Task labels must be declared in labels when labels are declared", ErrInvalidReference},
+	RuleUnknownBlocker:        {RuleUnknownBlocker, SeverityError, "blockedBy must reference a task or external dependency", ErrInvalidReference},
+	RuleStatusReason:          {RuleStatusReason, SeverityWarning, "Blocked and cancelled tasks should give a reason, and only they", ErrInconsistentStatus},
+	RuleInvalidDate:           {RuleInvalidDate, SeverityError, "Dates must use the YYYY-MM-DD format", ErrInvalidFormat},
+	RuleInvalidContent:        {RuleInvalidContent, SeverityError, "Content blocks must have a known type and the fields it requires", ErrInvalidFormat},
+	RuleInvalidLink:           {RuleInvalidLink, SeverityError, "Task links must have an absolute URL and a known kind", ErrInvalidFormat},
+	RuleUndeclaredArea:        {RuleUndeclaredArea, SeverityInfo, "Task areas are used but no areas are declared", ErrInvalidReference},
+	RulePhaseOrder:            {RulePhaseOrder, SeverityWarning, "Tasks should not depend on tasks in a later phase", ErrPhaseOrder},
+	RuleCompletedDependency:   {RuleCompletedDependency, SeverityWarning, "Completed tasks should only depend on completed tasks", ErrInconsistentStatus},
+	RuleCompletedOpenSubtasks: {RuleCompletedOpenSubtasks, SeverityWarning, "Completed tasks should not have unchecked subtasks", ErrInconsistentStatus},
+}
+
+// Rules returns all validation rules sorted by code.
+func Rules() []RuleInfo {
+	rules := make([]RuleInfo, 0, len(ruleRegistry))
+	for _, info := range ruleRegistry {
+		rules = append(rules, info)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Code < rules[j].Code
+	})
+	return rules
+}
+
+// LookupRule returns the rule with the given code.
+func LookupRule(code Rule) (RuleInfo, bool) {
+	info, ok := ruleRegistry[code]
+	return info, ok
+}
+
+// ValidateOptions controls which validation rules run and how findings
+// affect the result.
+type ValidateOptions struct {
+	// Disabled lists rules that are skipped.
+	Disabled map[Rule]bool
+
+	// Strict treats warnings as failures.
+	Strict bool
+
+	// Resolver resolves cross-project references (project#task-id). If nil,
+	// such references are reported as unresolved.
+	Resolver ProjectResolver
+
+	// Tasks, if set, limits task findings to the tasks it returns true
+	// for. Findings on other fields are always reported.
+	Tasks func(Task) bool
+}
+
+// DefaultValidateOptions returns options with all rules enabled.
+func DefaultValidateOptions() ValidateOptions {
+	return ValidateOptions{}
+}
+
+// WithDisabled disables the given rules.
+func (o ValidateOptions) WithDisabled(rules ...Rule) ValidateOptions {
+	disabled := make(map[Rule]bool, len(o.Disabled)+len(rules))
+	for r, v := range o.Disabled {
+		disabled[r] = v
+	}
+	for _, r := range rules {
+		disabled[r] = true
+	}
+	o.Disabled = disabled
+	return o
+}
+
+// WithStrict enables or disables strict mode.
+func (o ValidateOptions) WithStrict(enabled bool) ValidateOptions {
+	o.Strict = enabled
+	return o
+}
+
+// WithTasks limits task findings to the tasks keep returns true for.
+func (o ValidateOptions) WithTasks(keep func(Task) bool) ValidateOptions {
+	o.Tasks = keep
+	return o
+}
+
+// WithResolver sets the resolver used to check cross-project references.
+func (o ValidateOptions) WithResolver(r ProjectResolver) ValidateOptions {
+	o.Resolver = r
+	return o
+}
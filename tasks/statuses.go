@@ -0,0 +1,312 @@
+package tasks
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Graph node shapes for status definitions.
+const (
+	ShapeRectangle = "rectangle"
+	ShapeStadium   = "stadium"
+	ShapeHexagon   = "hexagon"
+	ShapeCircle    = "circle"
+)
+
+// StatusDef defines a task status: how it is displayed, where it sorts and
+// whether it counts as done, cancelled, blocked, started or proposed work. Status definitions are listed in the top-level
+// "statuses" field and extend or override the built-in statuses.
+type StatusDef struct {
+	ID          Status `json:"id"`
+	Emoji       string `json:"emoji,omitempty"`
+	Description string `json:"description,omitempty"`
+	Order       int    `json:"order,omitempty"`     // Display and sort order, lowest first
+	Done        bool   `json:"done,omitempty"`      // Counts as done for progress and completion
+	Cancelled   bool   `json:"cancelled,omitempty"` // Dropped work: hidden by default and excluded from progress totals
+	Blocked     bool   `json:"blocked,omitempty"`   // Work that cannot proceed: gives a blockedReason
+	Started     bool   `json:"started,omitempty"`   // Work under way: counts as in progress
+	Proposed    bool   `json:"proposed,omitempty"`  // Work under consideration rather than planned
+	Color       string `json:"color,omitempty"`     // Graph node color, a name or #rrggbb
+	Shape       string `json:"shape,omitempty"`     // Graph node shape: rectangle, stadium, hexagon, circle
+}
+
+// DefaultStatuses returns the built-in status definitions.
+func DefaultStatuses() []StatusDef {
+	return []StatusDef{
+		{ID: StatusInProgress, Emoji: "🚧", Description: "In Progress", Order: 10, Started: true, Color: "orange", Shape: ShapeHexagon},
+		{ID: StatusBlocked, Emoji: "⛔", Description: "Blocked", Order: 15, Blocked: true, Color: "red", Shape: ShapeHexagon},
+		{ID: StatusPlanned, Emoji: "📋", Description: "Planned", Order: 20, Color: "blue", Shape: ShapeRectangle},
+		{ID: StatusFuture, Emoji: "💡", Description: "Under Consideration", Order: 30, Proposed: true, Color: "gray", Shape: ShapeCircle},
+		{ID: StatusCompleted, Emoji: "✅", Description: "Completed", Order: 40, Done: true, Color: "green", Shape: ShapeStadium},
+		{ID: StatusCancelled, Emoji: "🚫", Description: "Cancelled", Order: 50, Cancelled: true, Color: "lightgray", Shape: ShapeCircle},
+	}
+}
+
+// StatusRegistry holds the statuses known to a task list, in order.
+type StatusRegistry struct {
+	defs []StatusDef
+}
+
+// NewStatusRegistry returns a registry of the built-in statuses extended by
+// defs. A definition with the ID of a known status overrides the fields it
+// sets; the Done, Cancelled, Blocked, Started and Proposed flags can be
+// set but not cleared, so for example built-in completed stays done.
+// New statuses without an Order are placed after all others in declaration
+// order.
+func NewStatusRegistry(defs ...StatusDef) *StatusRegistry {
+	r := &StatusRegistry{defs: DefaultStatuses()}
+	next := 0
+	for _, d := range r.defs {
+		next = max(next, d.Order)
+	}
+	for _, d := range defs {
+		if d.ID == "" {
+			continue
+		}
+		i := r.index(d.ID)
+		if i < 0 {
+			if d.Order == 0 {
+				next += 10
+				d.Order = next
+			} else {
+				next = max(next, d.Order)
+			}
+			r.defs = append(r.defs, d)
+			continue
+		}
+		cur := &r.defs[i]
+		if d.Emoji != "" {
+			cur.Emoji = d.Emoji
+		}
+		if d.Description != "" {
+			cur.Description = d.Description
+		}
+		if d.Order != 0 {
+			cur.Order = d.Order
+		}
+		if d.Color != "" {
+			cur.Color = d.Color
+		}
+		if d.Shape != "" {
+			cur.Shape = d.Shape
+		}
+		cur.Done = cur.Done || d.Done
+		cur.Cancelled = cur.Cancelled || d.Cancelled
+		cur.Blocked = cur.Blocked || d.Blocked
+		cur.Started = cur.Started || d.Started
+		cur.Proposed = cur.Proposed || d.Proposed
+	}
+	sort.SliceStable(r.defs, func(i, j int) bool {
+		return r.defs[i].Order < r.defs[j].Order
+	})
+	return r
+}
+
+// DefaultStatusRegistry returns a registry of the built-in statuses.
+func DefaultStatusRegistry() *StatusRegistry {
+	return NewStatusRegistry()
+}
+
+// StatusRegistry returns the statuses of the task list: the built-in
+// statuses, then legend entries, then the statuses declared in Statuses.
+// Legend entries for unknown statuses define new statuses, ordered by ID.
+func (tl *TaskList) StatusRegistry() *StatusRegistry {
+	legendIDs := make([]Status, 0, len(tl.Legend))
+	for id := range tl.Legend {
+		legendIDs = append(legendIDs, id)
+	}
+	sort.Slice(legendIDs, func(i, j int) bool { return legendIDs[i] < legendIDs[j] })
+
+	defs := make([]StatusDef, 0, len(legendIDs)+len(tl.Statuses))
+	for _, id := range legendIDs {
+		entry := tl.Legend[id]
+		defs = append(defs, StatusDef{ID: id, Emoji: entry.Emoji, Description: entry.Description})
+	}
+	defs = append(defs, tl.Statuses...)
+	return NewStatusRegistry(defs...)
+}
+
+func (r *StatusRegistry) index(id Status) int {
+	for i, d := range r.defs {
+		if d.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// All returns the status definitions in order.
+func (r *StatusRegistry) All() []StatusDef {
+	return append([]StatusDef(nil), r.defs...)
+}
+
+// Order returns the status IDs in order.
+func (r *StatusRegistry) Order() []Status {
+	ids := make([]Status, len(r.defs))
+	for i, d := range r.defs {
+		ids[i] = d.ID
+	}
+	return ids
+}
+
+// Lookup returns the definition of a status.
+func (r *StatusRegistry) Lookup(id Status) (StatusDef, bool) {
+	if i := r.index(id); i >= 0 {
+		return r.defs[i], true
+	}
+	return StatusDef{}, false
+}
+
+// IsValid returns true if the status is known.
+func (r *StatusRegistry) IsValid(id Status) bool {
+	return r.index(id) >= 0
+}
+
+// IsDone returns true if the status counts as done.
+func (r *StatusRegistry) IsDone(id Status) bool {
+	d, _ := r.Lookup(id)
+	return d.Done
+}
+
+// IsInProgress returns true if the status marks work under way, such as
+// inProgress or a custom status declared as started.
+func (r *StatusRegistry) IsInProgress(id Status) bool {
+	d, _ := r.Lookup(id)
+	return d.Started && !d.Done && !d.Cancelled
+}
+
+// IsProposed returns true if the status marks work under consideration,
+// such as future.
+func (r *StatusRegistry) IsProposed(id Status) bool {
+	d, _ := r.Lookup(id)
+	return d.Proposed
+}
+
+// IsCancelled returns true if the status counts as cancelled.
+func (r *StatusRegistry) IsCancelled(id Status) bool {
+	d, _ := r.Lookup(id)
+	return d.Cancelled
+}
+
+// IsBlocked returns true if the status marks work that cannot proceed.
+func (r *StatusRegistry) IsBlocked(id Status) bool {
+	d, _ := r.Lookup(id)
+	return d.Blocked
+}
+
+// Rank returns the position of a status in display order. Unknown statuses
+// sort last.
+func (r *StatusRegistry) Rank(id Status) int {
+	if i := r.index(id); i >= 0 {
+		return i
+	}
+	return len(r.defs)
+}
+
+// Legend returns the emoji and description of each status.
+func (r *StatusRegistry) Legend() map[Status]LegendEntry {
+	legend := make(map[Status]LegendEntry, len(r.defs))
+	for _, d := range r.defs {
+		legend[d.ID] = LegendEntry{Emoji: d.Emoji, Description: d.Description}
+	}
+	return legend
+}
+
+// Color returns the graph color of a status, or "gray" if it has none.
+func (r *StatusRegistry) Color(id Status) string {
+	if d, ok := r.Lookup(id); ok && d.Color != "" {
+		return d.Color
+	}
+	return "gray"
+}
+
+// Shape returns the graph node shape of a status. Statuses without a shape
+// are drawn as stadiums if done and circles otherwise.
+func (r *StatusRegistry) Shape(id Status) string {
+	d, _ := r.Lookup(id)
+	switch {
+	case d.Shape != "":
+		return d.Shape
+	case d.Done:
+		return ShapeStadium
+	}
+	return ShapeCircle
+}
+
+func isValidShape(shape string) bool {
+	switch shape {
+	case ShapeRectangle, ShapeStadium, ShapeHexagon, ShapeCircle:
+		return true
+	}
+	return false
+}
+
+// statusFlags returns the names of the flags set on a status definition.
+// At most one may be set.
+func statusFlags(d StatusDef) []string {
+	var flags []string
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{{"done", d.Done}, {"cancelled", d.Cancelled}, {"blocked", d.Blocked}, {"started", d.Started}, {"proposed", d.Proposed}} {
+		if f.set {
+			flags = append(flags, f.name)
+		}
+	}
+	return flags
+}
+
+// validateStatuses reports invalid status definitions.
+func validateStatuses(defs []StatusDef, report func(rule Rule, field, message string)) {
+	seen := make(map[Status]bool)
+	for i, d := range defs {
+		prefix := fmt.Sprintf("statuses[%d]", i)
+		if d.ID == "" {
+			report(RuleRequiredField, prefix+".id", "required field is missing")
+		} else if seen[d.ID] {
+			report(RuleDuplicateID, prefix+".id", fmt.Sprintf("duplicate status: %s", d.ID))
+		} else {
+			seen[d.ID] = true
+		}
+		if flags := statusFlags(d); len(flags) > 1 {
+			report(RuleInvalidStatus, prefix+"."+flags[1], fmt.Sprintf("status cannot be both %s and %s", flags[0], flags[1]))
+		}
+		if d.Shape != "" && !isValidShape(d.Shape) {
+			report(RuleInvalidStatus, prefix+".shape", fmt.Sprintf("invalid shape: %s (expected rectangle, stadium, hexagon or circle)", d.Shape))
+		}
+	}
+}
+
+// validateStatusReasons reports blocked and cancelled tasks without a
+// reason, reasons on tasks in other statuses, and blockers that are not a
+// task, a cross-project reference or an external dependency.
+func validateStatusReasons(tl *TaskList, reportTask func(i int, rule Rule, field, message string)) {
+	statuses := tl.StatusRegistry()
+	for i, task := range tl.Tasks {
+		prefix := fmt.Sprintf("tasks[%d]", i)
+		blocked := statuses.IsBlocked(task.Status)
+		cancelled := statuses.IsCancelled(task.Status)
+
+		switch {
+		case blocked && task.BlockedReason == "":
+			reportTask(i, RuleStatusReason, prefix+".blockedReason", "blocked task has no reason")
+		case !blocked && (task.BlockedReason != "" || task.BlockedBy != ""):
+			reportTask(i, RuleStatusReason, prefix+".blockedReason", fmt.Sprintf("task is not blocked (status %s)", task.Status))
+		}
+		switch {
+		case cancelled && task.CancelledReason == "":
+			reportTask(i, RuleStatusReason, prefix+".cancelledReason", "cancelled task has no reason")
+		case !cancelled && task.CancelledReason != "":
+			reportTask(i, RuleStatusReason, prefix+".cancelledReason", fmt.Sprintf("task is not cancelled (status %s)", task.Status))
+		}
+
+		if by := task.BlockedBy; by != "" {
+			_, isTask := tl.TaskByID(by)
+			_, isRef := ParseRef(by)
+			_, isExternal := tl.ExternalDependency(by)
+			if !isTask && !isRef && !isExternal {
+				reportTask(i, RuleUnknownBlocker, prefix+".blockedBy", fmt.Sprintf("references unknown task or dependency: %s", by))
+			}
+		}
+	}
+}
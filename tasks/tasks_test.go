@@ -1,9 +1,16 @@
 package tasks
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/grokify/structured-changelog/changelog"
 )
 
 func TestParse(t *testing.T) {
@@ -485,14 +492,14 @@ func TestTasksByType(t *testing.T) {
 
 func TestStatusOrder(t *testing.T) {
 	order := StatusOrder()
-	if len(order) != 4 {
-		t.Errorf("StatusOrder() returned %d items, want 4", len(order))
+	if len(order) != 6 {
+		t.Errorf("StatusOrder() returned %d items, want 6", len(order))
 	}
 	if order[0] != StatusInProgress {
 		t.Errorf("StatusOrder()[0] = %q, want %q", order[0], StatusInProgress)
 	}
-	if order[3] != StatusCompleted {
-		t.Errorf("StatusOrder()[3] = %q, want %q", order[3], StatusCompleted)
+	if order[4] != StatusCompleted {
+		t.Errorf("StatusOrder()[4] = %q, want %q", order[4], StatusCompleted)
 	}
 }
 
@@ -589,6 +596,32 @@ func TestValidationError(t *testing.T) {
 	}
 }
 
+func TestParseFailure(t *testing.T) {
+	path := t.TempDir() + "/TASKS.yaml"
+	if err := os.WriteFile(path, []byte("irVersion: 1.0\ntasks: [\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := ParseFile(path)
+	if err == nil {
+		t.Fatal("Expected parse error")
+	}
+
+	result := ParseFailure(err)
+	if result.Valid || len(result.Errors) != 1 {
+		t.Fatalf("Expected one error, got %+v", result)
+	}
+	e := result.Errors[0]
+	if e.Code != RuleParseError || e.Severity != SeverityError || !e.Pos.IsValid() {
+		t.Errorf("Unexpected finding: %+v", e)
+	}
+	if !errors.Is(result.Err(), ErrParseYAML) {
+		t.Error("Expected result to wrap ErrParseYAML")
+	}
+	if e.Error() != e.Message {
+		t.Errorf("Expected a finding without field to print its message, got %q", e.Error())
+	}
+}
+
 func TestValidationResultWithErrors(t *testing.T) {
 	result := ValidationResult{
 		Valid: false,
@@ -693,3 +726,1387 @@ func TestValidateMoreCases(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatePhaseOrdering(t *testing.T) {
+	tests := []struct {
+		name         string
+		tasks        []Task
+		wantWarnings []string
+	}{
+		{
+			name: "dependency on later phase",
+			tasks: []Task{
+				{ID: "a", Title: "A", Status: StatusPlanned, Phase: 1, DependsOn: []string{"b"}},
+				{ID: "b", Title: "B", Status: StatusPlanned, Phase: 3},
+			},
+			wantWarnings: []string{"phase 1 task depends on later phase 3 task: b"},
+		},
+		{
+			name: "dependency on earlier phase and unphased tasks",
+			tasks: []Task{
+				{ID: "a", Title: "A", Status: StatusPlanned, Phase: 1},
+				{ID: "b", Title: "B", Status: StatusPlanned, Phase: 2, DependsOn: []string{"a"}},
+				{ID: "c", Title: "C", Status: StatusPlanned, DependsOn: []string{"b"}},
+			},
+		},
+		{
+			name: "completed task with unfinished dependency",
+			tasks: []Task{
+				{ID: "a", Title: "A", Status: StatusInProgress, Phase: 1},
+				{ID: "b", Title: "B", Status: StatusCompleted, Phase: 1, DependsOn: []string{"a"}},
+			},
+			wantWarnings: []string{"completed task depends on unfinished task: a"},
+		},
+		{
+			name: "completed task with unchecked subtask",
+			tasks: []Task{
+				{ID: "a", Title: "A", Status: StatusCompleted, Subtasks: []Subtask{
+					{Description: "done", Completed: true},
+					{Description: "not done"},
+				}},
+			},
+			wantWarnings: []string{"completed task has unchecked subtask"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Validate(&TaskList{IRVersion: "1.0", Project: "test", Tasks: tt.tasks})
+			if !result.Valid {
+				t.Errorf("Expected warnings not to affect validity, got errors: %v", result.Errors)
+			}
+			if len(result.Warnings) != len(tt.wantWarnings) {
+				t.Fatalf("Expected %d warning(s), got %v", len(tt.wantWarnings), result.Warnings)
+			}
+			for i, want := range tt.wantWarnings {
+				if result.Warnings[i].Message != want {
+					t.Errorf("Warning %d = %q, want %q", i, result.Warnings[i].Message, want)
+				}
+				if result.Warnings[i].Severity != SeverityWarning {
+					t.Errorf("Warning %d severity = %q, want %q", i, result.Warnings[i].Severity, SeverityWarning)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateDiagnostics(t *testing.T) {
+	tl := &TaskList{
+		IRVersion: "1.0",
+		Project:   "test",
+		Tasks: []Task{
+			{ID: "a", Title: "A", Status: StatusPlanned, Phase: 1, Area: "core", DependsOn: []string{"b"}},
+			{ID: "b", Title: "B", Status: StatusPlanned, Phase: 2},
+			{ID: "b", Title: "B again", Status: StatusPlanned},
+		},
+	}
+
+	t.Run("codes, severities and sentinels", func(t *testing.T) {
+		result := Validate(tl)
+		if result.Valid {
+			t.Fatal("Expected invalid result for duplicate ID")
+		}
+		if len(result.Errors) != 1 || result.Errors[0].Code != RuleDuplicateID || result.Errors[0].Severity != SeverityError {
+			t.Fatalf("Expected one duplicate-id error, got %v", result.Errors)
+		}
+		if !errors.Is(result.Errors[0], ErrDuplicateID) {
+			t.Error("Expected error to wrap ErrDuplicateID")
+		}
+		if len(result.Warnings) != 1 || result.Warnings[0].Code != RulePhaseOrder {
+			t.Errorf("Expected one phase-order warning, got %v", result.Warnings)
+		}
+		if len(result.Infos) != 1 || result.Infos[0].Code != RuleUndeclaredArea || result.Infos[0].Severity != SeverityInfo {
+			t.Errorf("Expected one undeclared-area info, got %v", result.Infos)
+		}
+		if len(result.Diagnostics()) != 3 {
+			t.Errorf("Expected 3 diagnostics, got %d", len(result.Diagnostics()))
+		}
+
+		err := result.Err()
+		if !errors.Is(err, ErrValidation) || !errors.Is(err, ErrDuplicateID) {
+			t.Errorf("Expected Err() to wrap ErrValidation and ErrDuplicateID, got %v", err)
+		}
+	})
+
+	t.Run("disabled rules", func(t *testing.T) {
+		result := ValidateWithOptions(tl, DefaultValidateOptions().WithDisabled(RuleDuplicateID, RulePhaseOrder))
+		if !result.Valid {
+			t.Errorf("Expected valid result with duplicate-id disabled, got %v", result.Errors)
+		}
+		if len(result.Warnings) != 0 {
+			t.Errorf("Expected no warnings with phase-order disabled, got %v", result.Warnings)
+		}
+		if result.Err() != nil {
+			t.Errorf("Expected nil Err() for valid result, got %v", result.Err())
+		}
+	})
+
+	t.Run("strict mode", func(t *testing.T) {
+		result := ValidateWithOptions(tl, DefaultValidateOptions().WithDisabled(RuleDuplicateID).WithStrict(true))
+		if result.Valid {
+			t.Error("Expected warnings to fail validation in strict mode")
+		}
+		if !errors.Is(result.Err(), ErrPhaseOrder) {
+			t.Errorf("Expected Err() to wrap ErrPhaseOrder, got %v", result.Err())
+		}
+	})
+}
+
+func TestRules(t *testing.T) {
+	rules := Rules()
+	if len(rules) == 0 {
+		t.Fatal("Expected rules to be registered")
+	}
+	for i, rule := range rules {
+		if i > 0 && rules[i-1].Code >= rule.Code {
+			t.Errorf("Rules() not sorted at %s", rule.Code)
+		}
+		if rule.Err == nil || rule.Description == "" || rule.Severity == "" {
+			t.Errorf("Rule %s is incomplete: %+v", rule.Code, rule)
+		}
+	}
+	if _, ok := LookupRule("no-such-rule"); ok {
+		t.Error("Expected LookupRule to fail for unknown rule")
+	}
+}
+
+func TestValidatePositions(t *testing.T) {
+	data := []byte(`{
+  "irVersion": "1.0",
+  "project": "test",
+  "tasks": [
+    {"id": "a", "title": "A", "status": "planned"},
+    {"id": "b", "title": "Bé", "status": "planned",
+     "dependsOn": ["a", "missing"]},
+    {"id": "c", "status": "planned"}
+  ]
+}`)
+
+	tl, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want Position
+	}{
+		{"irVersion", Position{Line: 2, Column: 16}},
+		{"tasks[1].dependsOn[1]", Position{Line: 7, Column: 25}},
+		{"tasks[1].status", Position{Line: 6, Column: 42}}, // columns count characters, not bytes,
+		{"tasks[2].title", Position{Line: 8, Column: 5}},   // missing field falls back to the task
+		{"nope", Position{Line: 1, Column: 1}},             // unknown paths fall back to the document root
+	}
+	for _, tt := range tests {
+		if got := tl.Position(tt.path); got != tt.want {
+			t.Errorf("Position(%q) = %+v, want %+v", tt.path, got, tt.want)
+		}
+	}
+
+	result := Validate(tl)
+	if len(result.Errors) != 2 {
+		t.Fatalf("Expected 2 errors, got %v", result.Errors)
+	}
+	for _, e := range result.Errors {
+		if !e.Pos.IsValid() {
+			t.Errorf("Expected position for %s", e.Field)
+		}
+	}
+	if got := result.Errors[0].Pos.String(); got != "8:5" {
+		t.Errorf("Pos.String() = %q, want %q", got, "8:5")
+	}
+}
+
+func TestParseErrorPosition(t *testing.T) {
+	t.Run("syntax error", func(t *testing.T) {
+		_, err := Parse([]byte("{\n  \"project\": \"x\",\n  oops\n}"))
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Fatalf("Expected *ParseError, got %T", err)
+		}
+		if pe.Pos.Line != 3 {
+			t.Errorf("Expected line 3, got %+v", pe.Pos)
+		}
+		if !errors.Is(err, ErrParseJSON) {
+			t.Error("Expected error to wrap ErrParseJSON")
+		}
+	})
+
+	t.Run("type error", func(t *testing.T) {
+		path := t.TempDir() + "/TASKS.json"
+		if err := os.WriteFile(path, []byte("{\n  \"tasks\": [\n    {\"id\": 5}\n  ]\n}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		_, err := ParseFile(path)
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Fatalf("Expected *ParseError, got %T", err)
+		}
+		want := Position{File: path, Line: 3, Column: 12}
+		if pe.Pos != want {
+			t.Errorf("Pos = %+v, want %+v", pe.Pos, want)
+		}
+		if !strings.HasPrefix(err.Error(), path+":3:12: ") {
+			t.Errorf("Expected file:line:col prefix, got %q", err.Error())
+		}
+	})
+}
+
+const yamlTaskList = `# Task list in YAML
+irVersion: "1.0"
+project: test-project
+areas:
+  - id: core
+    name: Core
+tasks:
+  - id: task-1
+    title: Feature 1
+    description: |
+      First line.
+      Second line.
+    status: completed
+    phase: 1
+    area: core
+  - id: task-2
+    title: Feature 2
+    status: planned
+    dependsOn: [task-1, missing]
+`
+
+const tomlTaskList = `irVersion = "1.0"
+project = "test-project"
+
+[[areas]]
+id = "core"
+name = "Core"
+
+[[tasks]]
+id = "task-1"
+title = "Feature 1"
+description = """
+First line.
+Second line.
+"""
+status = "completed"
+phase = 1
+area = "core"
+
+[[tasks]]
+id = "task-2"
+title = "Feature 2"
+status = "planned"
+dependsOn = ["task-1", "missing"]
+`
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		path string
+		data string
+		want Format
+	}{
+		{"TASKS.json", "", FormatJSON},
+		{"TASKS.yaml", "", FormatYAML},
+		{"TASKS.yml", "", FormatYAML},
+		{"TASKS.toml", "", FormatTOML},
+		{"TASKS", `{"irVersion": "1.0"}`, FormatJSON},
+		{"TASKS", yamlTaskList, FormatYAML},
+		{"TASKS", tomlTaskList, FormatTOML},
+	}
+	for _, tt := range tests {
+		if got := DetectFormat(tt.path, []byte(tt.data)); got != tt.want {
+			t.Errorf("DetectFormat(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParseYAMLAndTOML(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		data   string
+		format Format
+	}{
+		{"yaml", yamlTaskList, FormatYAML},
+		{"toml", tomlTaskList, FormatTOML},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tl, err := ParseFormat([]byte(tc.data), tc.format)
+			if err != nil {
+				t.Fatalf("ParseFormat() error = %v", err)
+			}
+			if tl.Project != "test-project" || len(tl.Tasks) != 2 || len(tl.Areas) != 1 {
+				t.Fatalf("Unexpected task list: %+v", tl)
+			}
+			if tl.Tasks[0].Description != "First line.\nSecond line.\n" {
+				t.Errorf("Description = %q", tl.Tasks[0].Description)
+			}
+			if tl.Tasks[0].Phase != 1 || tl.Tasks[1].DependsOn[0] != "task-1" {
+				t.Errorf("Unexpected task fields: %+v", tl.Tasks)
+			}
+
+			// Semantic validation behaves the same regardless of format
+			result := Validate(tl)
+			if len(result.Errors) != 1 || result.Errors[0].Field != "tasks[1].dependsOn[1]" {
+				t.Errorf("Expected unknown dependency error, got %v", result.Errors)
+			}
+		})
+	}
+
+	t.Run("yaml positions", func(t *testing.T) {
+		tl, err := ParseFormat([]byte(yamlTaskList), FormatYAML)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := Position{Line: 19, Column: 25}
+		if got := tl.Position("tasks[1].dependsOn[1]"); got != want {
+			t.Errorf("Position = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("yaml unquoted scalars", func(t *testing.T) {
+		data := "irVersion: 1.0\nproject: 2026\ntasks:\n  - id: 42\n    title: yes\n    status: planned\n    version: 1.10\n    phase: 2\ndependencies:\n  internal:\n    - package: 3\n      dependsOn: util\n"
+		tl, err := ParseFormat([]byte(data), FormatYAML)
+		if err != nil {
+			t.Fatalf("ParseFormat() error = %v", err)
+		}
+		task := tl.Tasks[0]
+		if tl.IRVersion != "1.0" || tl.Project != "2026" || task.ID != "42" || task.Title != "yes" || task.Version != "1.10" || task.Phase != 2 {
+			t.Errorf("Unexpected task list: %+v", tl)
+		}
+		if internal := tl.Dependencies.Internal[0]; internal.Package != "3" || len(internal.DependsOn) != 1 || internal.DependsOn[0] != "util" {
+			t.Errorf("Unexpected internal dependency: %+v", internal)
+		}
+
+		_, err = ParseFormat([]byte("irVersion: 1.0\ntasks:\n  - id: a\n    phase: one\n"), FormatYAML)
+		var pe *ParseError
+		if !errors.As(err, &pe) || !errors.Is(err, ErrParseYAML) || pe.Pos.Line != 4 || pe.Pos.Column != 12 {
+			t.Errorf("Expected positioned ErrParseYAML, got %v", err)
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		if _, err := ParseFormat([]byte("tasks: [\n  - bad"), FormatYAML); !errors.Is(err, ErrParseYAML) {
+			t.Errorf("Expected ErrParseYAML, got %v", err)
+		}
+		_, err := ParseFormat([]byte("project = \"x\"\nproject = \"y\"\n"), FormatTOML)
+		var pe *ParseError
+		if !errors.As(err, &pe) || !errors.Is(err, ErrParseTOML) || pe.Pos.Line != 2 {
+			t.Errorf("Expected positioned ErrParseTOML, got %v", err)
+		}
+	})
+}
+
+func TestWriteFileFormats(t *testing.T) {
+	tl := &TaskList{
+		IRVersion: "1.0",
+		Project:   "test-project",
+		Tasks: []Task{
+			{ID: "task-1", Title: "Feature 1", Description: "Line one\nLine two", Status: StatusCompleted, Phase: 2,
+				Subtasks: []Subtask{{Description: "Sub", Completed: true}}},
+		},
+	}
+
+	for _, ext := range []string{".json", ".yaml", ".toml"} {
+		t.Run(ext, func(t *testing.T) {
+			path := t.TempDir() + "/TASKS" + ext
+			if err := WriteFile(path, tl); err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+			got, err := ParseFile(path)
+			if err != nil {
+				t.Fatalf("ParseFile() error = %v", err)
+			}
+			if got.Project != tl.Project || len(got.Tasks) != 1 {
+				t.Fatalf("Round trip lost data: %+v", got)
+			}
+			task := got.Tasks[0]
+			if task.Description != "Line one\nLine two" || task.Phase != 2 || !task.Subtasks[0].Completed {
+				t.Errorf("Round trip changed task: %+v", task)
+			}
+		})
+	}
+
+	t.Run("yaml literal blocks", func(t *testing.T) {
+		data, err := Marshal(tl, FormatYAML)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), "description: |-\n") {
+			t.Errorf("Expected literal block for multi-line description, got:\n%s", data)
+		}
+		if strings.Index(string(data), "irVersion") > strings.Index(string(data), "project") {
+			t.Error("Expected field order to follow the JSON IR")
+		}
+	})
+}
+
+const jsoncTaskList = `// Roadmap for the test project
+{
+  "irVersion": "1.0",
+  "project": "test-project", /* keep in sync with go.mod */
+  "tasks": [
+    // TODO ask design
+    {
+      "id": "task-1",
+      "title": "First task", // short title
+      "status": "planned",
+    },
+    {
+      "id": "task-2",
+      "title": "Second task",
+      "status": "inProgress",
+      "dependsOn": ["task-1", "missing",],
+    },
+    // more tasks go here
+  ],
+}
+`
+
+func TestParseJSONC(t *testing.T) {
+	tl, err := Parse([]byte(jsoncTaskList))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if tl.Project != "test-project" || len(tl.Tasks) != 2 || len(tl.Tasks[1].DependsOn) != 2 {
+		t.Fatalf("Unexpected task list: %+v", tl)
+	}
+
+	// Positions refer to the original, commented source
+	want := Position{Line: 16, Column: 31}
+	if got := tl.Position("tasks[1].dependsOn[1]"); got != want {
+		t.Errorf("Position = %+v, want %+v", got, want)
+	}
+
+	if got := DetectFormat("TASKS", []byte(jsoncTaskList)); got != FormatJSON {
+		t.Errorf("DetectFormat() = %q, want json", got)
+	}
+
+	// Comments inside strings are not comments
+	tl, err = Parse([]byte(`{"irVersion": "1.0", "project": "http://example.com/*x*/"}`))
+	if err != nil || tl.Project != "http://example.com/*x*/" {
+		t.Errorf("Parse() = %v, %v", tl, err)
+	}
+}
+
+func TestMarshalKeepsComments(t *testing.T) {
+	tl, err := Parse([]byte(jsoncTaskList))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Programmatic edit: reorder tasks and add a new one
+	tl.Tasks[0], tl.Tasks[1] = tl.Tasks[1], tl.Tasks[0]
+	tl.Tasks = append(tl.Tasks, Task{ID: "task-3", Title: "Third task", Status: StatusFuture})
+
+	data, err := Marshal(tl, FormatJSON)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	out := string(data)
+	for _, want := range []string{
+		"// Roadmap for the test project\n{",
+		`"project": "test-project", /* keep in sync with go.mod */`,
+		"    // TODO ask design\n    {\n      \"id\": \"task-1\"",
+		`"title": "First task", // short title`,
+		"    // more tasks go here\n  ]",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Output missing %q:\n%s", want, out)
+		}
+	}
+
+	// The output parses back to the same tasks and comments
+	again, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() of output error = %v", err)
+	}
+	if len(again.Tasks) != 3 || again.Tasks[1].ID != "task-1" {
+		t.Errorf("Unexpected round-trip tasks: %+v", again.Tasks)
+	}
+	if data2, _ := Marshal(again, FormatJSON); string(data2) != out {
+		t.Errorf("Round trip changed output:\n%s\nvs\n%s", data2, out)
+	}
+
+	// ToJSON stays plain JSON
+	plain, err := ToJSON(tl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(plain), "//") || !json.Valid(plain) {
+		t.Errorf("ToJSON() should not include comments:\n%s", plain)
+	}
+}
+
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestParseFileIncludes(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"TASKS.json": `{
+  "irVersion": "1.0",
+  "project": "monorepo",
+  "include": ["areas/*.json", "extra.yaml"],
+  "tasks": [
+    {"id": "root-1", "title": "Root task", "status": "planned"}
+  ]
+}`,
+		"areas/api.json": `{
+  "areas": [{"id": "api", "name": "API"}],
+  "tasks": [
+    // owned by the API team
+    {"id": "api-1", "title": "Endpoint", "status": "planned", "area": "api"}
+  ]
+}`,
+		"areas/cli.json": `{
+  "areas": [{"id": "cli", "name": "CLI"}],
+  "tasks": [
+    {"id": "cli-1", "title": "Command", "status": "planned", "area": "cli", "dependsOn": ["api-1"]},
+    {"id": "root-1", "title": "Duplicate", "status": "planned", "area": "cli"}
+  ]
+}`,
+		"extra.yaml": "tasks:\n  - id: extra-1\n    title: Extra\n    status: future\n",
+	})
+
+	tl, err := ParseFile(filepath.Join(dir, "TASKS.json"))
+	if err != nil {
+		t.Fatalf("ParseFile() error = %v", err)
+	}
+
+	var ids []string
+	for _, task := range tl.Tasks {
+		ids = append(ids, task.ID)
+	}
+	if got := strings.Join(ids, ","); got != "root-1,api-1,cli-1,root-1,extra-1" {
+		t.Errorf("Task order = %s", got)
+	}
+	if len(tl.Areas) != 2 || tl.Include != nil || len(tl.IncludedFiles()) != 3 {
+		t.Errorf("Unexpected merge result: areas=%v include=%v files=%v", tl.Areas, tl.Include, tl.IncludedFiles())
+	}
+	if got := tl.TaskSource(2); got != filepath.Join(dir, "areas", "cli.json") {
+		t.Errorf("TaskSource(2) = %s", got)
+	}
+
+	// Duplicates across files are reported against the file that holds them
+	result := Validate(tl)
+	if len(result.Errors) != 1 || result.Errors[0].Code != RuleDuplicateID {
+		t.Fatalf("Expected one duplicate ID error, got %v", result.Errors)
+	}
+	want := Position{File: filepath.Join(dir, "areas", "cli.json"), Line: 5, Column: 12}
+	if got := result.Errors[0].Pos; got != want {
+		t.Errorf("Error position = %+v, want %+v", got, want)
+	}
+
+	// Comments in included files are kept on their tasks
+	data, err := Marshal(tl, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "// owned by the API team\n    {\n      \"id\": \"api-1\"") {
+		t.Errorf("Expected included comment in output:\n%s", data)
+	}
+}
+
+func TestParseFileIncludeErrors(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTestFiles(t, dir, map[string]string{
+			"TASKS.json": "{\n  \"irVersion\": \"1.0\",\n  \"include\": [\"missing.json\"]\n}",
+		})
+		_, err := ParseFile(filepath.Join(dir, "TASKS.json"))
+		var pe *ParseError
+		if !errors.As(err, &pe) || !errors.Is(err, ErrReadFile) || pe.Pos.Line != 3 {
+			t.Errorf("Expected read error at include entry, got %v", err)
+		}
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTestFiles(t, dir, map[string]string{
+			"a.json": `{"include": ["b.json"]}`,
+			"b.json": `{"include": ["a.json"]}`,
+		})
+		if _, err := ParseFile(filepath.Join(dir, "a.json")); !errors.Is(err, ErrIncludeCycle) {
+			t.Errorf("Expected ErrIncludeCycle, got %v", err)
+		}
+	})
+
+	t.Run("glob matching itself", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTestFiles(t, dir, map[string]string{
+			"a.json": `{"include": ["*.json"], "tasks": [{"id": "a"}]}`,
+			"b.json": `{"tasks": [{"id": "b"}]}`,
+		})
+		tl, err := ParseFile(filepath.Join(dir, "a.json"))
+		if err != nil || len(tl.Tasks) != 2 {
+			t.Errorf("ParseFile() = %v, %v", tl, err)
+		}
+	})
+}
+
+func TestSplitByArea(t *testing.T) {
+	tl := &TaskList{
+		IRVersion: "1.0",
+		Project:   "test",
+		Areas:     []Area{{ID: "api", Name: "API"}, {ID: "docs", Name: "Docs"}},
+		Tasks: []Task{
+			{ID: "t1", Area: "api"},
+			{ID: "t2"},
+			{ID: "t3", Area: "docs"},
+			{ID: "t4", Area: "api"},
+			{ID: "t5", Area: "unknown"},
+		},
+	}
+	rest, parts := SplitByArea(tl)
+	if len(parts) != 2 || len(parts[0].Tasks) != 2 || len(parts[1].Tasks) != 1 {
+		t.Fatalf("Unexpected parts: %+v", parts)
+	}
+	if parts[0].Areas[0].ID != "api" || parts[0].Tasks[1].ID != "t4" {
+		t.Errorf("Unexpected api part: %+v", parts[0])
+	}
+	if len(rest.Tasks) != 2 || len(rest.Areas) != 0 || rest.Project != "test" {
+		t.Errorf("Unexpected rest: %+v", rest)
+	}
+}
+
+type mapResolver map[string]*TaskList
+
+func (m mapResolver) ResolveProject(key string) (*TaskList, error) {
+	if tl, ok := m[key]; ok {
+		return tl, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnresolvedReference, key)
+}
+
+func TestParseRef(t *testing.T) {
+	ref, ok := ParseRef("api#auth")
+	if !ok || ref.Project != "api" || ref.ID != "auth" || ref.String() != "api#auth" {
+		t.Errorf("ParseRef() = %+v, %v", ref, ok)
+	}
+	for _, s := range []string{"auth", "#auth", "api#"} {
+		if _, ok := ParseRef(s); ok {
+			t.Errorf("ParseRef(%q) should not be qualified", s)
+		}
+	}
+}
+
+func TestValidateCrossProjectRefs(t *testing.T) {
+	tl := &TaskList{
+		IRVersion: "1.0",
+		Project:   "web",
+		Tasks: []Task{
+			{ID: "login", Title: "Login", Status: StatusPlanned, DependsOn: []string{"api#auth", "api#missing", "billing#invoices"}},
+		},
+	}
+
+	// Without a resolver every external reference is a warning
+	result := Validate(tl)
+	if !result.Valid || len(result.Warnings) != 3 || result.Warnings[0].Code != RuleUnresolvedReference {
+		t.Fatalf("Expected three unresolved warnings, got %+v", result)
+	}
+	if !errors.Is(result.Warnings[0], ErrUnresolvedReference) {
+		t.Error("Expected ErrUnresolvedReference")
+	}
+
+	resolver := mapResolver{
+		"api": {Project: "api", Tasks: []Task{{ID: "auth", Title: "Auth", Status: StatusCompleted}}},
+	}
+	result = ValidateWithOptions(tl, DefaultValidateOptions().WithResolver(resolver))
+	if len(result.Errors) != 1 || result.Errors[0].Field != "tasks[0].dependsOn[1]" || result.Errors[0].Code != RuleUnknownDependency {
+		t.Errorf("Expected unknown dependency for api#missing, got %v", result.Errors)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0].Field != "tasks[0].dependsOn[2]" {
+		t.Errorf("Expected unavailable project warning for billing, got %v", result.Warnings)
+	}
+
+	// Local IDs containing the separator still resolve locally
+	tl.Tasks = append(tl.Tasks, Task{ID: "api#missing", Title: "Local", Status: StatusPlanned})
+	tl.Tasks[0].DependsOn = []string{"api#missing"}
+	if result := Validate(tl); len(result.Errors)+len(result.Warnings) != 0 {
+		t.Errorf("Expected no findings, got %+v", result)
+	}
+}
+
+func TestArchive(t *testing.T) {
+	newList := func() *TaskList {
+		return &TaskList{
+			IRVersion: "1.0",
+			Project:   "Test",
+			Statuses:  []StatusDef{{ID: "shipped", Done: true}},
+			Tasks: []Task{
+				{ID: "a", Title: "Parser", Description: "JSON input", Status: StatusCompleted, Phase: 1},
+				{ID: "b", Title: "Crash on empty file", Status: "shipped", Phase: 1, Type: "Fixed"},
+				{ID: "c", Title: "Docs", Status: StatusInProgress, Phase: 1, DependsOn: []string{"a"}},
+				{ID: "d", Title: "Later", Status: StatusCompleted, Phase: 2, Blocks: []string{"b", "c"}},
+				{ID: "e", Title: "Export", Status: StatusBlocked, Phase: 2, BlockedReason: "Needs the parser", BlockedBy: "a"},
+			},
+		}
+	}
+
+	tl, cl := newList(), changelog.New("Test")
+	archived, err := Archive(tl, cl, ArchiveOptions{Phase: 1, Version: "0.2.0", Date: "2026-01-15"})
+	if err != nil {
+		t.Fatalf("Archive() error: %v", err)
+	}
+	if len(archived) != 2 || len(tl.Tasks) != 3 {
+		t.Fatalf("Expected 2 archived and 3 remaining tasks, got %d and %d", len(archived), len(tl.Tasks))
+	}
+	if tl.Tasks[2].BlockedBy != "" || tl.Tasks[2].BlockedReason == "" {
+		t.Errorf("Expected blockedBy on an archived task cleared, got %+v", tl.Tasks[2])
+	}
+	if tl.Tasks[0].DependsOn != nil || len(tl.Tasks[1].Blocks) != 1 || tl.Tasks[1].Blocks[0] != "c" {
+		t.Errorf("Expected references to archived tasks removed, got %+v", tl.Tasks)
+	}
+	release := cl.Releases[0]
+	if archived[0].Version != "0.2.0" {
+		t.Errorf("Expected archived tasks to carry the release version, got %q", archived[0].Version)
+	}
+	if release.Version != "0.2.0" || release.Date != "2026-01-15" {
+		t.Errorf("Unexpected release %+v", release)
+	}
+	if len(release.Added) != 1 || release.Added[0].Description != "Parser: JSON input" {
+		t.Errorf("Expected untyped task under Added, got %+v", release.Added)
+	}
+	if len(release.Fixed) != 1 || release.Fixed[0].Description != "Crash on empty file" {
+		t.Errorf("Expected Fixed entry, got %+v", release.Fixed)
+	}
+	if result := Validate(tl); !result.Valid {
+		t.Errorf("Expected remaining task list to be valid: %v", result.Errors)
+	}
+
+	// Mark keeps tasks and skips them on the next run
+	tl = newList()
+	if _, err := Archive(tl, changelog.New("Test"), ArchiveOptions{Phase: 1, Version: "0.2.0", Mark: true}); err != nil {
+		t.Fatalf("Archive() error: %v", err)
+	}
+	if len(tl.Tasks) != 5 || !tl.Tasks[0].Archived || tl.Tasks[2].Archived {
+		t.Errorf("Expected completed tasks marked archived, got %+v", tl.Tasks)
+	}
+	if stats := tl.Stats(); stats.Total != 3 || stats.Done != 1 || len(tl.WithoutArchived().Tasks) != 3 {
+		t.Errorf("Expected archived tasks excluded from stats, got %+v", stats)
+	}
+	if _, err := Archive(tl, changelog.New("Test"), ArchiveOptions{Phase: 1, Version: "0.3.0"}); !errors.Is(err, ErrNothingToArchive) {
+		t.Errorf("Expected ErrNothingToArchive, got %v", err)
+	}
+
+	// Existing versions and unknown types are rejected
+	if _, err := Archive(newList(), cl, ArchiveOptions{Phase: 2, Version: "0.2.0"}); !errors.Is(err, ErrDuplicateID) {
+		t.Errorf("Expected ErrDuplicateID, got %v", err)
+	}
+	tl = newList()
+	tl.Tasks[3].Type = "Feature"
+	if _, err := Archive(tl, cl, ArchiveOptions{Phase: 2, Version: "0.3.0"}); !errors.Is(err, ErrInvalidType) {
+		t.Errorf("Expected ErrInvalidType, got %v", err)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.0", "v1.2.0", 0},
+		{"0.10.0", "0.9.1", 1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0-rc.2", "1.0.0-rc.10", -1},
+		{"1.0", "1.0.1", -1},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSyncVersions(t *testing.T) {
+	cl := changelog.New("Test")
+	older := changelog.NewRelease("0.1.0", "2026-01-01")
+	older.AddAdded(changelog.NewEntry("Parser"))
+	older.AddFixed(changelog.NewEntry("Crash fix").WithIssue("crash"))
+	older.AddChanged(changelog.NewEntry("Update docs for cli flags"))
+	newer := changelog.NewRelease("0.2.0", "2026-02-01")
+	newer.AddChanged(changelog.NewEntry("Faster parsing (parser), see docs-2"))
+	newer.AddAdded(changelog.NewEntry("Documentation site (#docs)"))
+	cl.AddRelease(older)
+	cl.AddRelease(newer)
+
+	tl := &TaskList{
+		IRVersion: "1.0",
+		Project:   "Test",
+		Statuses:  []StatusDef{{ID: "shipped", Done: true}},
+		Tasks: []Task{
+			{ID: "parser", Title: "Parser", Status: StatusCompleted},
+			{ID: "crash", Title: "Fix crash", Status: StatusCompleted, Version: "0.2.0"},
+			{ID: "docs", Title: "Docs", Status: "shipped"},
+			{ID: "docs-2", Title: "More docs", Status: StatusPlanned},
+			{ID: "cli", Title: "CLI", Status: StatusCompleted},
+		},
+	}
+
+	result := SyncVersions(tl, cl, false)
+	if len(result.Missing) != 2 || result.Missing[0].Release != "0.1.0" || result.Missing[1].TaskID != "docs" {
+		t.Errorf("Unexpected missing versions: %+v", result.Missing)
+	}
+	if len(result.Mismatched) != 1 || result.Mismatched[0].Release != "0.1.0" {
+		t.Errorf("Unexpected mismatches: %+v", result.Mismatched)
+	}
+	if len(result.Unmatched) != 1 || result.Unmatched[0] != "cli" {
+		t.Errorf("Unexpected unmatched tasks: %v", result.Unmatched)
+	}
+	if tl.Tasks[0].Version != "" || result.InSync() {
+		t.Error("Expected check mode to leave versions unchanged")
+	}
+
+	SyncVersions(tl, cl, true)
+	if tl.Tasks[0].Version != "0.1.0" || tl.Tasks[2].Version != "0.2.0" || tl.Tasks[1].Version != "0.2.0" {
+		t.Errorf("Unexpected versions after fill: %+v", tl.Tasks)
+	}
+	if versions := tl.Versions(); len(versions) != 2 || versions[0] != "0.2.0" {
+		t.Errorf("Versions() = %v", versions)
+	}
+	if !mentionsID("Docs site [docs]", "docs") || mentionsID("Update docs", "docs") || mentionsID("See (#docs-2)", "docs") {
+		t.Error("Expected only explicit (#id) and [id] markers to refer to a task")
+	}
+}
+
+func TestMilestones(t *testing.T) {
+	data := `{
+  "irVersion": "1.0",
+  "project": "Test",
+  "versionHistory": [
+    {"version": "0.1.0", "date": "2026-01-15", "status": "completed", "summary": "First release"},
+    {"version": "0.2.0", "date": "2026-04"},
+    {"version": "0.2.0"}
+  ],
+  "tasks": [
+    {"id": "a", "title": "A", "status": "completed", "milestone": "0.2.0"},
+    {"id": "b", "title": "B", "status": "planned", "milestone": "0.2.0"},
+    {"id": "c", "title": "C", "status": "planned", "milestone": "1.0.0"}
+  ]
+}`
+	tl, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if len(tl.Milestones) != 3 || tl.Milestones[0].Summary != "First release" {
+		t.Fatalf("Unexpected milestones: %+v", tl.Milestones)
+	}
+
+	result := Validate(tl)
+	codes := make(map[Rule]string)
+	for _, e := range result.Errors {
+		codes[e.Code] = e.Field
+	}
+	want := map[Rule]string{
+		RuleInvalidDate:      "versionHistory[1].date",
+		RuleDuplicateID:      "versionHistory[2].version",
+		RuleUnknownMilestone: "tasks[2].milestone",
+	}
+	for code, field := range want {
+		if codes[code] != field {
+			t.Errorf("Expected %s at %s, got errors %v", code, field, result.Errors)
+		}
+	}
+
+	m, ok := tl.Milestone("0.2.0")
+	if !ok || tl.MilestoneStatus(m) != StatusInProgress {
+		t.Errorf("Expected derived in-progress status, got %s", tl.MilestoneStatus(m))
+	}
+	if tl.MilestoneStatus(tl.Milestones[0]) != StatusCompleted {
+		t.Error("Expected explicit milestone status")
+	}
+	if got := len(tl.TasksByMilestone()["0.2.0"]); got != 2 {
+		t.Errorf("TasksByMilestone()[0.2.0] has %d tasks, want 2", got)
+	}
+}
+
+func TestPhases(t *testing.T) {
+	data := `{
+  "irVersion": "1.0",
+  "project": "Test",
+  "phases": [
+    {"number": 1, "id": "foundation", "name": "Phase 1: Foundation", "status": "completed"},
+    {"number": 2, "name": "Phase 2: Interfaces", "targetDate": "2026-06-01"},
+    {"number": 2, "name": "Duplicate", "targetDate": "June"},
+    {"number": 0, "name": ""}
+  ],
+  "tasks": [
+    {"id": "a", "title": "A", "status": "completed", "phase": 1},
+    {"id": "b", "title": "B", "status": "inProgress", "phase": 2},
+    {"id": "c", "title": "C", "status": "planned", "phase": 4}
+  ]
+}`
+	tl, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	result := Validate(tl)
+	fields := make(map[string]Rule)
+	for _, e := range result.Errors {
+		fields[e.Field] = e.Code
+	}
+	want := map[string]Rule{
+		"phases[2].number":     RuleDuplicateID,
+		"phases[2].targetDate": RuleInvalidDate,
+		"phases[3].number":     RuleInvalidPhase,
+		"phases[3].name":       RuleRequiredField,
+		"tasks[2].phase":       RuleUnknownPhase,
+	}
+	for field, code := range want {
+		if fields[field] != code {
+			t.Errorf("Expected %s at %s, got errors %v", code, field, result.Errors)
+		}
+	}
+
+	if got := tl.PhaseName(2); got != "Phase 2: Interfaces" {
+		t.Errorf("PhaseName(2) = %q", got)
+	}
+	if got := tl.PhaseName(4); got != "Phase 4" {
+		t.Errorf("PhaseName(4) = %q", got)
+	}
+	if tl.PhaseStatus(1) != StatusCompleted || tl.PhaseStatus(2) != StatusInProgress {
+		t.Errorf("Unexpected phase statuses: %s, %s", tl.PhaseStatus(1), tl.PhaseStatus(2))
+	}
+}
+
+func TestSectionsAndContent(t *testing.T) {
+	tl := &TaskList{
+		IRVersion: "1.0",
+		Project:   "Test",
+		Sections: []Section{
+			{ID: "faq", Title: "FAQ", Order: 2},
+			{ID: "intro", Title: "Design", Content: []ContentBlock{{Type: ContentText, Value: "Notes"}}},
+			{ID: "notes", Title: "Notes", Order: 1},
+			{ID: "intro", Title: "", Order: -1, Content: []ContentBlock{
+				{Type: ContentTable, Headers: []string{"A", "B"}, Rows: [][]string{{"1", "2"}, {"3"}}},
+				{Type: ContentList},
+				{Type: "video", Value: "x"},
+			}},
+		},
+		Tasks: []Task{
+			{ID: "a", Title: "A", Status: StatusPlanned, Content: []ContentBlock{{Type: ContentCode, Language: "go"}}},
+		},
+	}
+
+	before, after := tl.SectionsBefore(), tl.SectionsAfter()
+	if len(before) != 2 || before[0].Order != -1 || before[1].Title != "Design" {
+		t.Errorf("Unexpected sections before tasks: %+v", before)
+	}
+	if len(after) != 2 || after[0].ID != "notes" || after[1].ID != "faq" {
+		t.Errorf("Unexpected sections after tasks: %+v", after)
+	}
+
+	result := Validate(tl)
+	fields := make(map[string]Rule)
+	for _, e := range result.Errors {
+		fields[e.Field] = e.Code
+	}
+	want := map[string]Rule{
+		"sections[3].id":                 RuleDuplicateID,
+		"sections[3].title":              RuleRequiredField,
+		"sections[3].content[0].rows[1]": RuleInvalidContent,
+		"sections[3].content[1].items":   RuleInvalidContent,
+		"sections[3].content[2].type":    RuleInvalidContent,
+		"tasks[0].content[0].value":      RuleInvalidContent,
+	}
+	for field, code := range want {
+		if fields[field] != code {
+			t.Errorf("Expected %s at %s, got errors %v", code, field, result.Errors)
+		}
+	}
+	if len(result.Errors) != len(want) {
+		t.Errorf("Expected %d errors, got %v", len(want), result.Errors)
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	data := `{
+  "irVersion": "1.0",
+  "project": "Test",
+  "dependencies": {
+    "external": [
+      {"name": "github.com/acme/sdk", "status": "available"},
+      {"name": "billing-api", "status": "buildClient", "note": "OpenAPI spec only"},
+      {"name": "billing-api", "status": "soon"},
+      {"status": "planned"}
+    ],
+    "internal": [
+      {"package": "cmd/app", "dependsOn": ["core", "store"]},
+      {"package": "store", "dependsOn": "core"},
+      {"package": "store"}
+    ]
+  },
+  "tasks": [
+    {"id": "a", "title": "A", "status": "planned", "waitsOn": ["billing-api"]},
+    {"id": "b", "title": "B", "status": "planned", "waitsOn": ["payments"]}
+  ]
+}`
+	tl, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	if got := tl.Dependencies.Internal[1].DependsOn; len(got) != 1 || got[0] != "core" {
+		t.Errorf("Expected single-string dependsOn to parse as a list, got %v", got)
+	}
+	if d, ok := tl.ExternalDependency("billing-api"); !ok || d.Status != ExternalBuildClient {
+		t.Errorf("ExternalDependency(billing-api) = %+v, %v", d, ok)
+	}
+	if waiting := tl.TasksWaitingOn("billing-api"); len(waiting) != 1 || waiting[0].ID != "a" {
+		t.Errorf("TasksWaitingOn(billing-api) = %v", waiting)
+	}
+
+	result := Validate(tl)
+	fields := make(map[string]Rule)
+	for _, e := range result.Errors {
+		fields[e.Field] = e.Code
+	}
+	want := map[string]Rule{
+		"dependencies.external[2].name":    RuleDuplicateID,
+		"dependencies.external[2].status":  RuleInvalidStatus,
+		"dependencies.external[3].name":    RuleRequiredField,
+		"dependencies.internal[2].package": RuleDuplicateID,
+		"tasks[1].waitsOn[0]":              RuleUnknownExternal,
+	}
+	for field, code := range want {
+		if fields[field] != code {
+			t.Errorf("Expected %s at %s, got errors %v", code, field, result.Errors)
+		}
+	}
+	if len(result.Errors) != len(want) {
+		t.Errorf("Expected %d errors, got %v", len(want), result.Errors)
+	}
+
+	if _, err := Parse([]byte(`{"irVersion": "1.0", "project": "Test", "dependencies": {"internal": [{"package": "a", "dependsOn": 1}]}}`)); err == nil {
+		t.Error("Expected error for non-string dependsOn")
+	}
+}
+
+func TestCustomStatuses(t *testing.T) {
+	data := `{
+  "irVersion": "1.0",
+  "project": "Test",
+  "legend": {"completed": {"emoji": "✔️", "description": "Done"}},
+  "statuses": [
+    {"id": "blocked", "emoji": "⛔", "description": "Blocked", "order": 15, "color": "red"},
+    {"id": "wontDo", "emoji": "🚫", "description": "Won't Do", "done": true},
+    {"id": "inReview", "emoji": "👀", "description": "In Review", "shape": "triangle", "started": true},
+    {"id": "blocked"},
+    {"id": "onHold", "description": "On Hold"}
+  ],
+  "tasks": [
+    {"id": "a", "title": "A", "status": "blocked"},
+    {"id": "b", "title": "B", "status": "wontDo", "dependsOn": ["a"]},
+    {"id": "c", "title": "C", "status": "completed"},
+    {"id": "d", "title": "D", "status": "shipped"}
+  ]
+}`
+	tl, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	statuses := tl.StatusRegistry()
+	want := []Status{StatusInProgress, StatusBlocked, StatusPlanned, StatusFuture, StatusCompleted, StatusCancelled, "wontDo", "inReview", "onHold"}
+	if got := statuses.Order(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Order() = %v, want %v", got, want)
+	}
+	if !statuses.IsDone("wontDo") || !statuses.IsDone(StatusCompleted) || statuses.IsDone("blocked") {
+		t.Error("Unexpected done statuses")
+	}
+	if !statuses.IsInProgress("inReview") || !statuses.IsInProgress(StatusInProgress) || statuses.IsInProgress("wontDo") || statuses.IsInProgress(StatusBlocked) || statuses.IsInProgress("onHold") {
+		t.Error("Unexpected in-progress statuses")
+	}
+	if got := derivedStatus([]Task{{Status: "onHold"}, {Status: StatusPlanned}}, statuses); got != StatusPlanned {
+		t.Errorf("derivedStatus(onHold, planned) = %s, want planned", got)
+	}
+	if got := derivedStatus([]Task{{Status: "inReview"}, {Status: StatusPlanned}}, statuses); got != StatusInProgress {
+		t.Errorf("derivedStatus(inReview, planned) = %s, want inProgress", got)
+	}
+	vendor := &TaskList{IRVersion: "1.0", Project: "Test", Statuses: []StatusDef{{ID: "waitingOnVendor", Blocked: true}},
+		Tasks: []Task{{ID: "a", Title: "A", Status: "waitingOnVendor"}}}
+	if !vendor.StatusRegistry().IsBlocked("waitingOnVendor") || statuses.IsBlocked("onHold") {
+		t.Error("Unexpected blocked statuses")
+	}
+	if w := Validate(vendor).Warnings; len(w) != 1 || w[0].Field != "tasks[0].blockedReason" {
+		t.Errorf("Expected blocking custom status without a reason to warn, got %v", w)
+	}
+	var conflicts []string
+	validateStatuses([]StatusDef{{ID: "x", Started: true, Done: true}, {ID: "y", Proposed: true}}, func(_ Rule, field, _ string) {
+		conflicts = append(conflicts, field)
+	})
+	if !reflect.DeepEqual(conflicts, []string{"statuses[0].started"}) {
+		t.Errorf("Expected started and done to conflict, got %v", conflicts)
+	}
+	if statuses.Color("blocked") != "red" || statuses.Color("wontDo") != "gray" {
+		t.Errorf("Unexpected colors: %s, %s", statuses.Color("blocked"), statuses.Color("wontDo"))
+	}
+	if statuses.Shape("wontDo") != ShapeStadium || statuses.Shape(StatusBlocked) != ShapeHexagon {
+		t.Errorf("Unexpected shapes: %s, %s", statuses.Shape("wontDo"), statuses.Shape(StatusBlocked))
+	}
+	if entry := tl.GetLegend()[StatusCompleted]; entry.Description != "Done" {
+		t.Errorf("Expected legend override, got %+v", entry)
+	}
+
+	stats := tl.Stats()
+	if stats.Done != 2 || stats.CompletedCount() != 1 {
+		t.Errorf("Expected 2 done and 1 completed, got %d and %d", stats.Done, stats.CompletedCount())
+	}
+
+	result := Validate(tl)
+	fields := make(map[string]Rule)
+	for _, e := range result.Errors {
+		fields[e.Field] = e.Code
+	}
+	for field, code := range map[string]Rule{
+		"statuses[2].shape": RuleInvalidStatus,
+		"statuses[3].id":    RuleDuplicateID,
+		"tasks[3].status":   RuleInvalidStatus,
+	} {
+		if fields[field] != code {
+			t.Errorf("Expected %s at %s, got errors %v", code, field, result.Errors)
+		}
+	}
+	var completedDep bool
+	for _, w := range result.Warnings {
+		completedDep = completedDep || w.Field == "tasks[1].dependsOn[0]" && w.Code == RuleCompletedDependency
+	}
+	if !completedDep {
+		t.Errorf("Expected done task depending on blocked task to warn, got %v", result.Warnings)
+	}
+}
+
+func TestBlockedAndCancelled(t *testing.T) {
+	data := `{
+  "irVersion": "1.0",
+  "project": "Test",
+  "dependencies": {"external": [{"name": "idp", "status": "planned"}]},
+  "versionHistory": [{"version": "1.0.0"}, {"version": "2.0.0"}],
+  "tasks": [
+    {"id": "a", "title": "A", "status": "completed", "milestone": "1.0.0"},
+    {"id": "b", "title": "B", "status": "blocked", "milestone": "1.0.0", "blockedReason": "Contract", "blockedBy": "idp"},
+    {"id": "c", "title": "C", "status": "cancelled", "milestone": "2.0.0", "cancelledReason": "No demand"},
+    {"id": "d", "title": "D", "status": "blocked", "blockedBy": "other#x"},
+    {"id": "e", "title": "E", "status": "planned", "blockedBy": "nowhere", "cancelledReason": "Dropped"},
+    {"id": "f", "title": "F", "status": "cancelled"}
+  ]
+}`
+	tl, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	stats := tl.Stats()
+	if stats.BlockedCount() != 2 || stats.Cancelled != 2 || stats.ProgressTotal() != 4 {
+		t.Errorf("Expected 2 blocked, 2 cancelled and 4 counted, got %+v", stats)
+	}
+	if got := stats.Progress(); got != 25 {
+		t.Errorf("Progress() = %.1f, want 25", got)
+	}
+	if got := tl.MilestoneStatus(tl.Milestones[0]); got != StatusInProgress {
+		t.Errorf("MilestoneStatus(1.0.0) = %s, want inProgress", got)
+	}
+	if got := tl.MilestoneStatus(tl.Milestones[1]); got != StatusCancelled {
+		t.Errorf("MilestoneStatus(2.0.0) = %s, want cancelled", got)
+	}
+	if got := derivedStatus([]Task{{Status: StatusBlocked}}, tl.StatusRegistry()); got != StatusPlanned {
+		t.Errorf("derivedStatus(blocked) = %s, want planned", got)
+	}
+	if got := derivedStatus([]Task{{Status: StatusCompleted}, {Status: StatusCancelled}}, tl.StatusRegistry()); got != StatusCompleted {
+		t.Errorf("derivedStatus(completed, cancelled) = %s, want completed", got)
+	}
+
+	result := Validate(tl)
+	if len(result.Errors) != 1 || result.Errors[0].Field != "tasks[4].blockedBy" || result.Errors[0].Code != RuleUnknownBlocker {
+		t.Errorf("Expected unknown blocker error only, got %v", result.Errors)
+	}
+	warnings := make(map[string]Rule)
+	for _, w := range result.Warnings {
+		warnings[w.Field] = w.Code
+	}
+	for _, field := range []string{"tasks[3].blockedReason", "tasks[4].blockedReason", "tasks[4].cancelledReason", "tasks[5].cancelledReason"} {
+		if warnings[field] != RuleStatusReason {
+			t.Errorf("Expected %s at %s, got warnings %v", RuleStatusReason, field, result.Warnings)
+		}
+	}
+	if _, ok := warnings["tasks[1].blockedReason"]; ok {
+		t.Errorf("Unexpected warning for blocked task with reason: %v", result.Warnings)
+	}
+}
+
+func TestPriorityAndOrder(t *testing.T) {
+	data := `{
+  "irVersion": "1.0",
+  "project": "Test",
+  "phases": [{"number": 1, "name": "One", "targetDate": "2026-09-01"}],
+  "versionHistory": [{"version": "1.0.0", "date": "2026-06-01"}],
+  "tasks": [
+    {"id": "a", "title": "A", "status": "planned", "priority": "high", "dependsOn": ["c"], "phase": 1},
+    {"id": "b", "title": "B", "status": "planned", "priority": "urgent", "dueDate": "June 1", "milestone": "1.0.0"},
+    {"id": "c", "title": "C", "status": "planned", "dependsOn": ["d"]},
+    {"id": "d", "title": "D", "status": "planned", "dependsOn": ["c"]},
+    {"id": "e", "title": "E", "status": "planned", "dueDate": "2026-01-15", "milestone": "1.0.0"}
+  ]
+}`
+	tl, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	if PriorityCritical.Rank() >= PriorityLow.Rank() || Priority("").Rank() != len(Priorities()) {
+		t.Error("Unexpected priority ranks")
+	}
+	for i, want := range []string{"2026-09-01", "June 1", "", "", "2026-01-15"} {
+		if got := tl.DueDate(tl.Tasks[i]); got != want {
+			t.Errorf("DueDate(%s) = %q, want %q", tl.Tasks[i].ID, got, want)
+		}
+	}
+
+	// c and d form a cycle, so they and a, which depends on c, follow the
+	// others in list order.
+	order := tl.DependencyOrder()
+	want := map[string]int{"b": 0, "e": 1, "a": 2, "c": 3, "d": 4}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("DependencyOrder() = %v, want %v", order, want)
+	}
+
+	result := Validate(tl)
+	fields := make(map[string]Rule)
+	for _, e := range result.Errors {
+		fields[e.Field] = e.Code
+	}
+	if fields["tasks[1].priority"] != RuleInvalidPriority || fields["tasks[1].dueDate"] != RuleInvalidDate {
+		t.Errorf("Expected priority and due date errors, got %v", result.Errors)
+	}
+}
+
+func TestLabels(t *testing.T) {
+	data := `{
+  "irVersion": "1.0",
+  "project": "Test",
+  "labels": [
+    {"id": "security", "name": "Security", "color": "red"},
+    {"id": "perf"},
+    {"id": "perf"}
+  ],
+  "tasks": [
+    {"id": "a", "title": "A", "status": "planned", "labels": ["security", "perf"]},
+    {"id": "b", "title": "B", "status": "planned", "labels": ["perf", "ux"]},
+    {"id": "c", "title": "C", "status": "planned", "labels": [""]},
+    {"id": "d", "title": "D", "status": "planned"}
+  ]
+}`
+	tl, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	if got, want := tl.LabelIDs(), []string{"security", "perf", "ux"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("LabelIDs() = %q, want %q", got, want)
+	}
+	if tl.LabelName("security") != "Security" || tl.LabelName("perf") != "perf" {
+		t.Errorf("Unexpected label names: %s, %s", tl.LabelName("security"), tl.LabelName("perf"))
+	}
+	byLabel := tl.TasksByLabel()
+	if len(byLabel["perf"]) != 2 || len(byLabel["_unspecified"]) != 1 {
+		t.Errorf("Unexpected TasksByLabel: %v", byLabel)
+	}
+	if stats := tl.Stats(); stats.ByLabel["perf"] != 2 || stats.ByLabel["security"] != 1 {
+		t.Errorf("Unexpected label stats: %v", stats.ByLabel)
+	}
+
+	filter := ParseLabelFilter([]string{"perf", "!ux"})
+	if !reflect.DeepEqual(filter, LabelFilter{Include: []string{"perf"}, Exclude: []string{"ux"}}) {
+		t.Errorf("ParseLabelFilter() = %+v", filter)
+	}
+	filtered := tl.Filter(filter.Match)
+	if len(filtered.Tasks) != 1 || filtered.Tasks[0].ID != "a" || len(tl.Tasks) != 4 {
+		t.Errorf("Filter() = %v", filtered.Tasks)
+	}
+	if n := len(tl.Filter(ParseLabelFilter([]string{"!perf"}).Match).Tasks); n != 2 {
+		t.Errorf("Expected 2 tasks without perf, got %d", n)
+	}
+
+	result := Validate(tl)
+	fields := make(map[string]Rule)
+	for _, e := range result.Errors {
+		fields[e.Field] = e.Code
+	}
+	want := map[string]Rule{
+		"labels[2].id":       RuleDuplicateID,
+		"tasks[1].labels[1]": RuleUnknownLabel,
+		"tasks[2].labels[0]": RuleRequiredField,
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("Validate() errors = %v, want %v", fields, want)
+	}
+
+	result = ValidateWithOptions(tl, DefaultValidateOptions().WithTasks(ParseLabelFilter([]string{"security"}).Match))
+	if len(result.Errors) != 1 || result.Errors[0].Field != "labels[2].id" {
+		t.Errorf("Expected only non-task findings and findings on selected tasks, got %v", result.Errors)
+	}
+	second := tl.Tasks[1].ID
+	result = ValidateWithOptions(tl, DefaultValidateOptions().WithTasks(func(task Task) bool { return task.ID == second }))
+	fields = make(map[string]Rule)
+	for _, e := range result.Errors {
+		fields[e.Field] = e.Code
+	}
+	if len(fields) != 2 || fields["tasks[1].labels[1]"] != RuleUnknownLabel || fields["labels[2].id"] != RuleDuplicateID {
+		t.Errorf("Expected findings on the selected task kept, got %v", result.Errors)
+	}
+}
+
+func TestLinks(t *testing.T) {
+	data := `{
+  "irVersion": "1.0",
+  "project": "Test",
+  "tasks": [
+    {"id": "a", "title": "A", "status": "planned", "links": [
+      {"kind": "pr", "url": "https://github.com/org/repo/pull/2"},
+      {"kind": "issue", "url": "https://github.com/org/repo/issues/1"},
+      {"kind": "issue", "url": "https://github.com/org/repo/issues/3"}
+    ]},
+    {"id": "b", "title": "B", "status": "planned", "links": [
+      {"url": "https://example.com/doc"},
+      {"kind": "ticket", "url": "example.com/ticket"},
+      {"kind": "doc"}
+    ]}
+  ]
+}`
+	tl, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	issue, ok := tl.Tasks[0].PrimaryIssue()
+	if !ok || issue.URL != "https://github.com/org/repo/issues/1" {
+		t.Errorf("PrimaryIssue() = %v, %v", issue, ok)
+	}
+	if _, ok := tl.Tasks[1].PrimaryIssue(); ok {
+		t.Error("Expected no primary issue for task without issue links")
+	}
+
+	result := Validate(tl)
+	fields := make(map[string]Rule)
+	for _, e := range result.Errors {
+		fields[e.Field] = e.Code
+	}
+	want := map[string]Rule{
+		"tasks[1].links[1].url":  RuleInvalidLink,
+		"tasks[1].links[1].kind": RuleInvalidLink,
+		"tasks[1].links[2].url":  RuleRequiredField,
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("Validate() errors = %v, want %v", fields, want)
+	}
+}
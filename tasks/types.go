@@ -10,25 +10,36 @@ const (
 	StatusPlanned    Status = "planned"
 	StatusFuture     Status = "future"
 	StatusCompleted  Status = "completed"
+	StatusBlocked    Status = "blocked"   // Cannot proceed; see Task.BlockedReason
+	StatusCancelled  Status = "cancelled" // Dropped; see Task.CancelledReason
 )
 
 // DefaultLegend returns the default status legend with emoji and descriptions.
 func DefaultLegend() map[Status]LegendEntry {
-	return map[Status]LegendEntry{
-		StatusInProgress: {Emoji: "🚧", Description: "In Progress"},
-		StatusPlanned:    {Emoji: "📋", Description: "Planned"},
-		StatusFuture:     {Emoji: "💡", Description: "Under Consideration"},
-		StatusCompleted:  {Emoji: "✅", Description: "Completed"},
-	}
+	return DefaultStatusRegistry().Legend()
 }
 
 // TaskList is the top-level IR structure for a project task list.
 type TaskList struct {
-	IRVersion string                 `json:"irVersion"`
-	Project   string                 `json:"project"`
-	Legend    map[Status]LegendEntry `json:"legend,omitempty"`
-	Areas     []Area                 `json:"areas,omitempty"`
-	Tasks     []Task                 `json:"tasks,omitempty"`
+	IRVersion    string                 `json:"irVersion"`
+	Project      string                 `json:"project"`
+	Legend       map[Status]LegendEntry `json:"legend,omitempty"`
+	Statuses     []StatusDef            `json:"statuses,omitempty"` // Custom statuses, extending the built-ins
+	Include      []string               `json:"include,omitempty"`  // Files (or globs) merged in by ParseFile
+	Areas        []Area                 `json:"areas,omitempty"`
+	Labels       []LabelDef             `json:"labels,omitempty"` // Optional label registry
+	Phases       []Phase                `json:"phases,omitempty"`
+	Milestones   []Milestone            `json:"versionHistory,omitempty"`
+	Sections     []Section              `json:"sections,omitempty"`
+	Dependencies *Dependencies          `json:"dependencies,omitempty"`
+	Tasks        []Task                 `json:"tasks,omitempty"`
+
+	// Source information recorded by Parse and ParseFile.
+	sourceFile string
+	positions  map[string]Position
+	comments   map[string]*jsonComments // JSONC comments, written back by Marshal
+
+	includedFiles []string // Files merged in from Include
 }
 
 // LegendEntry defines the emoji and description for a status.
@@ -44,19 +55,33 @@ type Area struct {
 }
 
 // Task represents a work item (feature, task, improvement).
-// Order is determined by position in the Tasks array.
+// By default order is determined by position in the Tasks array; renderers
+// can sort by priority, status, title, due date or dependencies instead.
 // Type should be a valid category name from structured-changelog (e.g., "Added", "Fixed").
 type Task struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description,omitempty"`
-	Status      Status    `json:"status"`
-	Phase       int       `json:"phase,omitempty"`
-	Area        string    `json:"area,omitempty"`
-	Type        string    `json:"type,omitempty"`
-	DependsOn   []string  `json:"dependsOn,omitempty"`
-	Blocks      []string  `json:"blocks,omitempty"`
-	Subtasks    []Subtask `json:"subtasks,omitempty"`
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Status      Status   `json:"status"`
+	Priority    Priority `json:"priority,omitempty"`
+	DueDate     string   `json:"dueDate,omitempty"` // YYYY-MM-DD
+	Phase       int      `json:"phase,omitempty"`
+	Area        string   `json:"area,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	Version     string   `json:"version,omitempty"`   // Release the task shipped in
+	Milestone   string   `json:"milestone,omitempty"` // Version of the milestone the task is scheduled for
+	DependsOn   []string `json:"dependsOn,omitempty"`
+	Blocks      []string `json:"blocks,omitempty"`
+	WaitsOn     []string `json:"waitsOn,omitempty"` // Names of external dependencies the task waits on
+	Links       []Link   `json:"links,omitempty"`   // Issues, pull requests and documents the task relates to
+
+	BlockedReason   string         `json:"blockedReason,omitempty"`   // Why a blocked task cannot proceed
+	BlockedBy       string         `json:"blockedBy,omitempty"`       // Task (or project#task) or external dependency blocking it
+	CancelledReason string         `json:"cancelledReason,omitempty"` // Why a cancelled task was dropped
+	Subtasks        []Subtask      `json:"subtasks,omitempty"`
+	Content         []ContentBlock `json:"content,omitempty"`
+	Archived        bool           `json:"archived,omitempty"` // Moved to the changelog; hidden from rendered output
 }
 
 // Subtask represents a checkbox item within a task.
@@ -66,16 +91,10 @@ type Subtask struct {
 	Completed   bool   `json:"completed"`
 }
 
-// GetLegend returns the task list's legend, falling back to defaults.
+// GetLegend returns the emoji and description of every status known to the
+// task list, including custom statuses and legend overrides.
 func (tl *TaskList) GetLegend() map[Status]LegendEntry {
-	if len(tl.Legend) > 0 {
-		legend := DefaultLegend()
-		for k, v := range tl.Legend {
-			legend[k] = v
-		}
-		return legend
-	}
-	return DefaultLegend()
+	return tl.StatusRegistry().Legend()
 }
 
 // GetStatusEmoji returns the emoji for a status.
@@ -122,6 +141,20 @@ func (tl *TaskList) TasksByPhase() map[int][]Task {
 	return result
 }
 
+// TasksByVersion returns tasks grouped by the release they shipped in.
+// Tasks without a version are grouped under "_unspecified".
+func (tl *TaskList) TasksByVersion() map[string][]Task {
+	result := make(map[string][]Task)
+	for _, task := range tl.Tasks {
+		v := task.Version
+		if v == "" {
+			v = "_unspecified"
+		}
+		result[v] = append(result[v], task)
+	}
+	return result
+}
+
 // TasksByStatus returns tasks grouped by status.
 func (tl *TaskList) TasksByStatus() map[Status][]Task {
 	result := make(map[Status][]Task)
@@ -131,17 +164,26 @@ func (tl *TaskList) TasksByStatus() map[Status][]Task {
 	return result
 }
 
-// Stats returns statistics about the task list.
+// Stats returns statistics about the task list. Archived tasks are not
+// counted.
 func (tl *TaskList) Stats() Stats {
 	stats := Stats{
 		ByStatus: make(map[Status]int),
 		ByArea:   make(map[string]int),
 		ByType:   make(map[string]int),
 		ByPhase:  make(map[int]int),
+		ByLabel:  make(map[string]int),
 	}
-	stats.Total = len(tl.Tasks)
-	for _, task := range tl.Tasks {
+	statuses := tl.StatusRegistry()
+	for _, task := range tl.WithoutArchived().Tasks {
+		stats.Total++
 		stats.ByStatus[task.Status]++
+		if statuses.IsDone(task.Status) {
+			stats.Done++
+		}
+		if statuses.IsCancelled(task.Status) {
+			stats.Cancelled++
+		}
 		if task.Area != "" {
 			stats.ByArea[task.Area]++
 		}
@@ -149,17 +191,23 @@ func (tl *TaskList) Stats() Stats {
 			stats.ByType[task.Type]++
 		}
 		stats.ByPhase[task.Phase]++
+		for _, label := range task.Labels {
+			stats.ByLabel[label]++
+		}
 	}
 	return stats
 }
 
 // Stats holds task list statistics.
 type Stats struct {
-	Total    int
-	ByStatus map[Status]int
-	ByArea   map[string]int
-	ByType   map[string]int
-	ByPhase  map[int]int
+	Total     int
+	Done      int // Tasks whose status counts as done
+	Cancelled int // Tasks whose status counts as cancelled
+	ByStatus  map[Status]int
+	ByArea    map[string]int
+	ByType    map[string]int
+	ByPhase   map[int]int
+	ByLabel   map[string]int // A task counts once for each of its labels
 }
 
 // InProgressCount returns the number of in-progress tasks.
@@ -172,14 +220,36 @@ func (s Stats) PlannedCount() int {
 	return s.ByStatus[StatusPlanned]
 }
 
-// CompletedCount returns the number of completed tasks.
+// BlockedCount returns the number of blocked tasks.
+func (s Stats) BlockedCount() int {
+	return s.ByStatus[StatusBlocked]
+}
+
+// ProgressTotal returns the number of tasks progress is measured against:
+// all tasks except cancelled ones.
+func (s Stats) ProgressTotal() int {
+	return s.Total - s.Cancelled
+}
+
+// Progress returns the percentage of tasks that are done out of
+// ProgressTotal, or 0 if there are none.
+func (s Stats) Progress() float64 {
+	if s.ProgressTotal() == 0 {
+		return 0
+	}
+	return float64(s.Done) / float64(s.ProgressTotal()) * 100
+}
+
+// CompletedCount returns the number of tasks with the completed status. Use
+// Done to include custom statuses that count as done.
 func (s Stats) CompletedCount() int {
 	return s.ByStatus[StatusCompleted]
 }
 
-// StatusOrder returns the canonical order of statuses for display.
+// StatusOrder returns the display order of the built-in statuses. Use
+// TaskList.StatusRegistry for the order including custom statuses.
 func StatusOrder() []Status {
-	return []Status{StatusInProgress, StatusPlanned, StatusFuture, StatusCompleted}
+	return []Status{StatusInProgress, StatusBlocked, StatusPlanned, StatusFuture, StatusCompleted, StatusCancelled}
 }
 
 // PhaseNumbers returns sorted phase numbers from the task list.
@@ -1,74 +1,174 @@
 package tasks
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/grokify/structured-changelog/changelog"
 )
 
-// ValidationError represents a validation error.
+// Severity indicates how serious a validation finding is.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// ValidationError represents a validation finding (error, warning or info).
 type ValidationError struct {
-	Field   string
-	Message string
+	Field    string   `json:"field"`
+	Message  string   `json:"message"`
+	Severity Severity `json:"severity"`
+	Code     Rule     `json:"code"`
+	Pos      Position `json:"position"` // Source position, if parsed from a file
+	Err      error    `json:"-"`        // Underlying sentinel error
 }
 
 func (e ValidationError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
 	return fmt.Sprintf("%s: %s", e.Field, e.Message)
 }
 
+func (e ValidationError) Unwrap() error {
+	return e.Err
+}
+
 // ValidationResult holds the results of validation.
+// Warnings and infos do not affect Valid unless strict mode is enabled.
 type ValidationResult struct {
-	Valid  bool
-	Errors []ValidationError
+	Valid    bool              `json:"valid"`
+	Errors   []ValidationError `json:"errors"`
+	Warnings []ValidationError `json:"warnings"`
+	Infos    []ValidationError `json:"infos"`
+}
+
+// Diagnostics returns all findings: errors, then warnings, then infos.
+func (r ValidationResult) Diagnostics() []ValidationError {
+	all := make([]ValidationError, 0, len(r.Errors)+len(r.Warnings)+len(r.Infos))
+	all = append(all, r.Errors...)
+	all = append(all, r.Warnings...)
+	return append(all, r.Infos...)
+}
+
+// Err returns nil if the result is valid. Otherwise it returns an error
+// wrapping ErrValidation and each finding, so errors.Is can match sentinels.
+func (r ValidationResult) Err() error {
+	if r.Valid {
+		return nil
+	}
+	errs := []error{ErrValidation}
+	for _, e := range r.Errors {
+		errs = append(errs, e)
+	}
+	for _, w := range r.Warnings {
+		errs = append(errs, w)
+	}
+	return errors.Join(errs...)
+}
+
+// ParseFailure returns the result of validating a file that could not be
+// parsed: a single parse-error finding at the error's position, if known.
+func ParseFailure(err error) ValidationResult {
+	finding := ValidationError{
+		Message:  err.Error(),
+		Severity: SeverityError,
+		Code:     RuleParseError,
+		Err:      err,
+	}
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		finding.Message = pe.Err.Error()
+		finding.Pos = pe.Pos
+	}
+	return ValidationResult{
+		Errors:   []ValidationError{finding},
+		Warnings: []ValidationError{},
+		Infos:    []ValidationError{},
+	}
 }
 
-// Validate checks a TaskList for validity.
+// Validate checks a TaskList for validity with all rules enabled.
 func Validate(tl *TaskList) ValidationResult {
-	result := ValidationResult{Valid: true}
+	return ValidateWithOptions(tl, DefaultValidateOptions())
+}
+
+// ValidateWithOptions checks a TaskList for validity using the given options.
+func ValidateWithOptions(tl *TaskList, opts ValidateOptions) ValidationResult {
+	result := ValidationResult{
+		Valid:    true,
+		Errors:   []ValidationError{},
+		Warnings: []ValidationError{},
+		Infos:    []ValidationError{},
+	}
+	report := func(rule Rule, field, message string) {
+		result.report(opts, rule, field, message, tl.Position(field))
+	}
+	// reportTask reports a finding on the task at index i, unless
+	// opts.Tasks leaves that task out.
+	reportTask := func(i int, rule Rule, field, message string) {
+		if opts.Tasks == nil || opts.Tasks(tl.Tasks[i]) {
+			report(rule, field, message)
+		}
+	}
 
 	// Required fields
 	if tl.IRVersion == "" {
-		result.addError("ir_version", "required field is missing")
+		report(RuleRequiredField, "irVersion", "required field is missing")
 	} else if tl.IRVersion != "1.0" {
-		result.addError("ir_version", fmt.Sprintf("unsupported version: %s", tl.IRVersion))
+		report(RuleIRVersion, "irVersion", fmt.Sprintf("unsupported version: %s", tl.IRVersion))
 	}
 
 	if tl.Project == "" {
-		result.addError("project", "required field is missing")
+		report(RuleRequiredField, "project", "required field is missing")
 	}
 
+	validateStatuses(tl.Statuses, report)
+	statuses := tl.StatusRegistry()
+
 	// Validate tasks
 	taskIDs := make(map[string]bool)
 	for i, task := range tl.Tasks {
 		prefix := fmt.Sprintf("tasks[%d]", i)
 
 		if task.ID == "" {
-			result.addError(prefix+".id", "required field is missing")
+			reportTask(i, RuleRequiredField, prefix+".id", "required field is missing")
 		} else if taskIDs[task.ID] {
-			result.addError(prefix+".id", fmt.Sprintf("duplicate ID: %s", task.ID))
+			reportTask(i, RuleDuplicateID, prefix+".id", fmt.Sprintf("duplicate ID: %s", task.ID))
 		} else {
 			taskIDs[task.ID] = true
 		}
 
 		if task.Title == "" {
-			result.addError(prefix+".title", "required field is missing")
+			reportTask(i, RuleRequiredField, prefix+".title", "required field is missing")
 		}
 
 		if task.Status == "" {
-			result.addError(prefix+".status", "required field is missing")
-		} else if !isValidStatus(task.Status) {
-			result.addError(prefix+".status", fmt.Sprintf("invalid status: %s", task.Status))
+			reportTask(i, RuleRequiredField, prefix+".status", "required field is missing")
+		} else if !statuses.IsValid(task.Status) {
+			reportTask(i, RuleInvalidStatus, prefix+".status", fmt.Sprintf("invalid status: %s", task.Status))
+		}
+
+		if task.Priority != "" && !task.Priority.IsValid() {
+			reportTask(i, RuleInvalidPriority, prefix+".priority", fmt.Sprintf("invalid priority: %s (expected critical, high, medium or low)", task.Priority))
+		}
+		if task.DueDate != "" && !isValidDate(task.DueDate) {
+			reportTask(i, RuleInvalidDate, prefix+".dueDate", fmt.Sprintf("invalid date: %s (expected YYYY-MM-DD)", task.DueDate))
 		}
 
 		// Validate phase is non-negative
 		if task.Phase < 0 {
-			result.addError(prefix+".phase", "phase must be non-negative")
+			reportTask(i, RuleInvalidPhase, prefix+".phase", "phase must be non-negative")
 		}
 
 		// Validate type against structured-changelog change types
 		if task.Type != "" {
 			if !changelog.DefaultRegistry.IsValidName(task.Type) {
-				result.addError(prefix+".type", fmt.Sprintf("invalid change type: %s (see structured-changelog for valid types)", task.Type))
+				reportTask(i, RuleInvalidType, prefix+".type", fmt.Sprintf("invalid change type: %s (see structured-changelog for valid types)", task.Type))
 			}
 		}
 
@@ -76,16 +176,36 @@ func Validate(tl *TaskList) ValidationResult {
 		for j, subtask := range task.Subtasks {
 			subtaskPrefix := fmt.Sprintf("%s.subtasks[%d]", prefix, j)
 			if subtask.Description == "" {
-				result.addError(subtaskPrefix+".description", "required field is missing")
+				reportTask(i, RuleRequiredField, subtaskPrefix+".description", "required field is missing")
 			}
 		}
+
+		validateContent(task.Content, prefix, func(rule Rule, field, message string) {
+			reportTask(i, rule, field, message)
+		})
 	}
 
-	// Validate depends_on references
+	// Validate dependsOn references. Qualified references (project#task-id)
+	// are checked against the other project when it can be resolved.
 	for i, task := range tl.Tasks {
-		for _, dep := range task.DependsOn {
-			if !taskIDs[dep] {
-				result.addError(fmt.Sprintf("tasks[%d].depends_on", i), fmt.Sprintf("references unknown task: %s", dep))
+		for j, dep := range task.DependsOn {
+			if taskIDs[dep] {
+				continue
+			}
+			field := fmt.Sprintf("tasks[%d].dependsOn[%d]", i, j)
+			ref, external := ParseRef(dep)
+			if !external {
+				reportTask(i, RuleUnknownDependency, field, fmt.Sprintf("references unknown task: %s", dep))
+				continue
+			}
+			_, found, err := ResolveRef(opts.Resolver, ref)
+			switch {
+			case opts.Resolver == nil:
+				reportTask(i, RuleUnresolvedReference, field, fmt.Sprintf("cannot check cross-project reference without a manifest: %s", dep))
+			case err != nil:
+				reportTask(i, RuleUnresolvedReference, field, fmt.Sprintf("project %s is not available: %v", ref.Project, err))
+			case !found:
+				reportTask(i, RuleUnknownDependency, field, fmt.Sprintf("references unknown task: %s", dep))
 			}
 		}
 	}
@@ -95,36 +215,189 @@ func Validate(tl *TaskList) ValidationResult {
 	for i, area := range tl.Areas {
 		prefix := fmt.Sprintf("areas[%d]", i)
 		if area.ID == "" {
-			result.addError(prefix+".id", "required field is missing")
+			report(RuleRequiredField, prefix+".id", "required field is missing")
 		} else if areaIDs[area.ID] {
-			result.addError(prefix+".id", fmt.Sprintf("duplicate ID: %s", area.ID))
+			report(RuleDuplicateID, prefix+".id", fmt.Sprintf("duplicate ID: %s", area.ID))
 		} else {
 			areaIDs[area.ID] = true
 		}
 		if area.Name == "" {
-			result.addError(prefix+".name", "required field is missing")
+			report(RuleRequiredField, prefix+".name", "required field is missing")
 		}
 	}
 
 	// Validate task area references
 	for i, task := range tl.Tasks {
-		if task.Area != "" && len(tl.Areas) > 0 && !areaIDs[task.Area] {
-			result.addError(fmt.Sprintf("tasks[%d].area", i), fmt.Sprintf("references unknown area: %s", task.Area))
+		if task.Area == "" {
+			continue
+		}
+		if len(tl.Areas) == 0 {
+			reportTask(i, RuleUndeclaredArea, fmt.Sprintf("tasks[%d].area", i), fmt.Sprintf("area is not declared in areas: %s", task.Area))
+		} else if !areaIDs[task.Area] {
+			reportTask(i, RuleUnknownArea, fmt.Sprintf("tasks[%d].area", i), fmt.Sprintf("references unknown area: %s", task.Area))
+		}
+	}
+
+	// Validate phases and task phase references
+	phases := make(map[int]bool)
+	phaseIDs := make(map[string]bool)
+	for i, p := range tl.Phases {
+		prefix := fmt.Sprintf("phases[%d]", i)
+		switch {
+		case p.Number <= 0:
+			report(RuleInvalidPhase, prefix+".number", "phase number must be positive")
+		case phases[p.Number]:
+			report(RuleDuplicateID, prefix+".number", fmt.Sprintf("duplicate phase: %d", p.Number))
+		default:
+			phases[p.Number] = true
+		}
+		if p.ID != "" {
+			if phaseIDs[p.ID] {
+				report(RuleDuplicateID, prefix+".id", fmt.Sprintf("duplicate ID: %s", p.ID))
+			}
+			phaseIDs[p.ID] = true
+		}
+		if p.Name == "" {
+			report(RuleRequiredField, prefix+".name", "required field is missing")
+		}
+		if p.Status != "" && !statuses.IsValid(p.Status) {
+			report(RuleInvalidStatus, prefix+".status", fmt.Sprintf("invalid status: %s", p.Status))
+		}
+		if p.TargetDate != "" && !isValidDate(p.TargetDate) {
+			report(RuleInvalidDate, prefix+".targetDate", fmt.Sprintf("invalid date: %s (expected YYYY-MM-DD)", p.TargetDate))
+		}
+	}
+	if len(tl.Phases) > 0 {
+		for i, task := range tl.Tasks {
+			if task.Phase > 0 && !phases[task.Phase] {
+				reportTask(i, RuleUnknownPhase, fmt.Sprintf("tasks[%d].phase", i), fmt.Sprintf("references undeclared phase: %d", task.Phase))
+			}
+		}
+	}
+
+	// Validate milestones and task milestone references
+	milestones := make(map[string]bool)
+	for i, m := range tl.Milestones {
+		prefix := fmt.Sprintf("versionHistory[%d]", i)
+		if m.Version == "" {
+			report(RuleRequiredField, prefix+".version", "required field is missing")
+		} else if milestones[m.Version] {
+			report(RuleDuplicateID, prefix+".version", fmt.Sprintf("duplicate milestone: %s", m.Version))
+		} else {
+			milestones[m.Version] = true
+		}
+		if m.Status != "" && !statuses.IsValid(m.Status) {
+			report(RuleInvalidStatus, prefix+".status", fmt.Sprintf("invalid status: %s", m.Status))
+		}
+		if m.Date != "" && !isValidDate(m.Date) {
+			report(RuleInvalidDate, prefix+".date", fmt.Sprintf("invalid date: %s (expected YYYY-MM-DD)", m.Date))
+		}
+	}
+	for i, task := range tl.Tasks {
+		if task.Milestone != "" && !milestones[task.Milestone] {
+			reportTask(i, RuleUnknownMilestone, fmt.Sprintf("tasks[%d].milestone", i), fmt.Sprintf("references unknown milestone: %s", task.Milestone))
+		}
+	}
+
+	// Validate sections
+	sectionIDs := make(map[string]bool)
+	for i, section := range tl.Sections {
+		prefix := fmt.Sprintf("sections[%d]", i)
+		if section.ID == "" {
+			report(RuleRequiredField, prefix+".id", "required field is missing")
+		} else if sectionIDs[section.ID] {
+			report(RuleDuplicateID, prefix+".id", fmt.Sprintf("duplicate ID: %s", section.ID))
+		} else {
+			sectionIDs[section.ID] = true
+		}
+		if section.Title == "" {
+			report(RuleRequiredField, prefix+".title", "required field is missing")
 		}
+		validateContent(section.Content, prefix, report)
+	}
+
+	validateDependencies(tl, report, reportTask)
+	validateLabels(tl, report, reportTask)
+	validateLinks(tl, reportTask)
+	validateStatusReasons(tl, reportTask)
+
+	validatePhaseOrdering(tl, reportTask)
+
+	if opts.Strict && len(result.Warnings) > 0 {
+		result.Valid = false
 	}
 
 	return result
 }
 
-func (r *ValidationResult) addError(field, message string) {
-	r.Errors = append(r.Errors, ValidationError{Field: field, Message: message})
-	r.Valid = false
+// isValidDate returns true if s is a date in YYYY-MM-DD format.
+func isValidDate(s string) bool {
+	_, err := time.Parse("2006-01-02", s)
+	return err == nil
+}
+
+// validatePhaseOrdering reports dependencies and completion states that
+// are inconsistent with phase order.
+func validatePhaseOrdering(tl *TaskList, reportTask func(i int, rule Rule, field, message string)) {
+	statuses := tl.StatusRegistry()
+	taskByID := make(map[string]Task)
+	for _, task := range tl.Tasks {
+		if _, ok := taskByID[task.ID]; !ok {
+			taskByID[task.ID] = task
+		}
+	}
+
+	for i, task := range tl.Tasks {
+		prefix := fmt.Sprintf("tasks[%d]", i)
+
+		for j, depID := range task.DependsOn {
+			depField := fmt.Sprintf("%s.dependsOn[%d]", prefix, j)
+			dep, ok := taskByID[depID]
+			if !ok {
+				continue
+			}
+			if task.Phase > 0 && dep.Phase > task.Phase {
+				reportTask(i, RulePhaseOrder, depField, fmt.Sprintf("phase %d task depends on later phase %d task: %s", task.Phase, dep.Phase, depID))
+			}
+			if statuses.IsDone(task.Status) && !statuses.IsDone(dep.Status) {
+				reportTask(i, RuleCompletedDependency, depField, fmt.Sprintf("completed task depends on unfinished task: %s", depID))
+			}
+		}
+
+		if statuses.IsDone(task.Status) {
+			for j, subtask := range task.Subtasks {
+				if !subtask.Completed {
+					reportTask(i, RuleCompletedOpenSubtasks, fmt.Sprintf("%s.subtasks[%d]", prefix, j), "completed task has unchecked subtask")
+				}
+			}
+		}
+	}
 }
 
-func isValidStatus(s Status) bool {
-	switch s {
-	case StatusCompleted, StatusInProgress, StatusPlanned, StatusFuture:
-		return true
+// report records a finding for a rule unless the rule is disabled.
+func (r *ValidationResult) report(opts ValidateOptions, rule Rule, field, message string, pos Position) {
+	if opts.Disabled[rule] {
+		return
+	}
+	info, ok := LookupRule(rule)
+	if !ok {
+		info = RuleInfo{Code: rule, Severity: SeverityError}
+	}
+	e := ValidationError{
+		Field:    field,
+		Message:  message,
+		Severity: info.Severity,
+		Code:     rule,
+		Pos:      pos,
+		Err:      info.Err,
+	}
+	switch info.Severity {
+	case SeverityWarning:
+		r.Warnings = append(r.Warnings, e)
+	case SeverityInfo:
+		r.Infos = append(r.Infos, e)
+	default:
+		r.Errors = append(r.Errors, e)
+		r.Valid = false
 	}
-	return false
 }
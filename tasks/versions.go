@@ -0,0 +1,171 @@
+package tasks
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/grokify/structured-changelog/changelog"
+)
+
+// Versions returns the distinct task versions, newest first.
+func (tl *TaskList) Versions() []string {
+	seen := make(map[string]bool)
+	var versions []string
+	for _, task := range tl.Tasks {
+		if task.Version != "" && !seen[task.Version] {
+			seen[task.Version] = true
+			versions = append(versions, task.Version)
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return CompareVersions(versions[i], versions[j]) > 0
+	})
+	return versions
+}
+
+// CompareVersions compares two version strings such as "v1.2.0" and
+// "1.10.0" by their dot-separated numeric parts, returning -1, 0 or 1. A
+// leading "v" is ignored, and a pre-release suffix ("1.0.0-rc.1") sorts
+// before the release. Non-numeric parts compare as strings.
+func CompareVersions(a, b string) int {
+	a, b = strings.TrimPrefix(a, "v"), strings.TrimPrefix(b, "v")
+	aCore, aPre, _ := strings.Cut(a, "-")
+	bCore, bPre, _ := strings.Cut(b, "-")
+	if c := compareVersionParts(aCore, bCore); c != 0 {
+		return c
+	}
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+	return compareVersionParts(aPre, bPre)
+}
+
+func compareVersionParts(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		switch {
+		case xerr == nil && yerr == nil:
+			if xn != yn {
+				return sign(xn - yn)
+			}
+		case x != y:
+			return sign(strings.Compare(x, y))
+		}
+	}
+	return 0
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// VersionChange describes a completed task whose version differs from the
+// release that mentions it in the changelog.
+type VersionChange struct {
+	Index   int    // Index of the task in the task list
+	TaskID  string // Task ID
+	Version string // Version in the task list, empty if unset
+	Release string // Version of the changelog release mentioning the task
+}
+
+// SyncResult holds the outcome of SyncVersions.
+type SyncResult struct {
+	Missing    []VersionChange // Tasks without a version found in the changelog
+	Mismatched []VersionChange // Tasks whose version differs from the changelog
+	Unmatched  []string        // IDs of completed tasks without a version not found in the changelog
+}
+
+// InSync returns true if no task is missing a version or has a mismatched one.
+func (r SyncResult) InSync() bool {
+	return len(r.Missing) == 0 && len(r.Mismatched) == 0
+}
+
+// SyncVersions matches completed tasks (tasks whose status counts as done)
+// to changelog releases and reports tasks whose version is missing or
+// differs from the changelog. If fill is true, missing versions are set on
+// the task list; mismatched versions are only reported.
+//
+// A changelog entry refers to a task if its issue is the task ID, its
+// description marks the task ID as "(#id)" or "[id]", or its description is
+// the task's title (optionally followed by ": " and the task description,
+// as written by Archive). Releases are searched from oldest to newest, so a
+// task mentioned in several releases is matched to the first it shipped in.
+// Unreleased changes are ignored.
+func SyncVersions(tl *TaskList, cl *changelog.Changelog, fill bool) SyncResult {
+	var result SyncResult
+	statuses := tl.StatusRegistry()
+	for i, task := range tl.Tasks {
+		if !statuses.IsDone(task.Status) {
+			continue
+		}
+		release, ok := releaseFor(cl, task)
+		switch {
+		case !ok:
+			if task.Version == "" {
+				result.Unmatched = append(result.Unmatched, task.ID)
+			}
+		case task.Version == "":
+			result.Missing = append(result.Missing, VersionChange{Index: i, TaskID: task.ID, Release: release})
+			if fill {
+				tl.Tasks[i].Version = release
+			}
+		case CompareVersions(task.Version, release) != 0:
+			result.Mismatched = append(result.Mismatched, VersionChange{Index: i, TaskID: task.ID, Version: task.Version, Release: release})
+		}
+	}
+	return result
+}
+
+// releaseFor returns the version of the oldest release with an entry that
+// refers to task.
+func releaseFor(cl *changelog.Changelog, task Task) (string, bool) {
+	for i := len(cl.Releases) - 1; i >= 0; i-- {
+		r := &cl.Releases[i]
+		for _, category := range r.Categories() {
+			for _, e := range category.Entries {
+				if entryRefersTo(e, task) {
+					return r.Version, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+func entryRefersTo(e changelog.Entry, task Task) bool {
+	if e.Issue == task.ID {
+		return true
+	}
+	if e.Description == task.Title || e.Description == archiveDescription(task) {
+		return true
+	}
+	return mentionsID(e.Description, task.ID)
+}
+
+// mentionsID returns true if text marks id as "(#id)" or "[id]". A bare
+// word is not enough, since short IDs such as docs or cli also appear in
+// unrelated entries.
+func mentionsID(text, id string) bool {
+	return id != "" && (strings.Contains(text, "(#"+id+")") || strings.Contains(text, "["+id+"]"))
+}